@@ -0,0 +1,315 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// EbitenBackend 把绘制命令绘制到ebiten图像的后端
+// 裁剪使用子图像实现，图层使用临时图像池中的离屏图像
+type EbitenBackend struct {
+	// Target 绘制目标
+	Target *ebiten.Image
+
+	stack    []ebitenSurface
+	sources  map[image.Image]*ebiten.Image // 非ebiten源图像转换后的缓存
+	path     vector.Path
+	verts    []ebiten.Vertex
+	indices  []uint16
+	uniforms map[string]interface{} // 渐变和阴影着色器的参数
+}
+
+// ebitenSurface 当前的绘制表面（裁剪后的子图像，或者图层图像）
+type ebitenSurface struct {
+	image   *ebiten.Image
+	origin  image.Point // 表面坐标系原点在目标坐标系中的位置
+	empty   bool        // 裁剪区域为空，跳过绘制
+	layer   bool
+	rect    image.Rectangle // 图层在目标坐标系中覆盖的区域
+	opacity int
+}
+
+// NewEbitenBackend 创建绘制到target的ebiten后端
+func NewEbitenBackend(target *ebiten.Image) *EbitenBackend {
+	return &EbitenBackend{Target: target}
+}
+
+// Execute 执行命令列表（未配对的裁剪和图层在结束时自动关闭）
+func (b *EbitenBackend) Execute(list *DrawList) {
+	if b.Target == nil || list == nil {
+		return
+	}
+	b.stack = append(b.stack[:0], ebitenSurface{image: b.Target})
+
+	for i := range list.Commands {
+		cmd := &list.Commands[i]
+		top := &b.stack[len(b.stack)-1]
+		switch cmd.Op {
+		case OpPushClip:
+			b.pushClip(cmd.Rect)
+			continue
+		case OpPushLayer:
+			b.pushLayer(cmd.Rect, cmd.Opacity)
+			continue
+		case OpPopClip, OpPopLayer:
+			b.pop()
+			continue
+		}
+		if top.empty {
+			continue
+		}
+		b.execute(top, cmd)
+	}
+
+	for len(b.stack) > 1 {
+		b.pop()
+	}
+	b.stack[0] = ebitenSurface{}
+	b.stack = b.stack[:0]
+}
+
+// execute 在表面上执行一条绘制命令
+func (b *EbitenBackend) execute(s *ebitenSurface, cmd *DrawCommand) {
+	rect := cmd.Rect.Sub(s.origin)
+	switch cmd.Op {
+	case OpFillRect:
+		vector.DrawFilledRect(s.image, float32(rect.Min.X), float32(rect.Min.Y),
+			float32(rect.Dx()), float32(rect.Dy()), nrgba(cmd.Color), false)
+	case OpFillRoundedRect:
+		b.path = vector.Path{}
+		appendRoundedRect(&b.path, rect, cmd.Radius)
+		b.fillPath(s.image, cmd.Color, ebiten.FillAll)
+	case OpStrokeRoundedRect:
+		b.path = vector.Path{}
+		appendRoundedRect(&b.path, rect, cmd.Radius)
+		inner := rect.Inset(cmd.Width)
+		if !inner.Empty() {
+			appendRoundedRect(&b.path, inner, max(cmd.Radius-cmd.Width, 0))
+		}
+		b.fillPath(s.image, cmd.Color, ebiten.EvenOdd)
+	case OpImage:
+		src := b.source(cmd.Image)
+		if src == nil {
+			return
+		}
+		if !cmd.Source.Empty() {
+			src = src.SubImage(cmd.Source.Add(src.Bounds().Min)).(*ebiten.Image)
+		}
+		sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+		if sw == 0 || sh == 0 {
+			return
+		}
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(float64(rect.Dx())/float64(sw), float64(rect.Dy())/float64(sh))
+		op.GeoM.Translate(float64(rect.Min.X), float64(rect.Min.Y))
+		if cmd.Opacity < 100 {
+			op.ColorScale.ScaleAlpha(float32(cmd.Opacity) / 100)
+		}
+		s.image.DrawImage(src, op)
+	case OpText:
+		text.Draw(s.image, cmd.Text, cmd.Face, rect.Min.X, rect.Min.Y, nrgba(cmd.Color))
+	case OpFillGradient:
+		paint := newGradientPaint(cmd.Gradient, cmd.Rect)
+		if paint.count == 0 {
+			return
+		}
+		u := b.effectUniforms(0, cmd.Rect, cmd.Radius)
+		u["Radial"] = boolFloat(paint.radial)
+		u["Center"] = [2]float32{float32(paint.center[0]), float32(paint.center[1])}
+		u["Axis"] = [2]float32{float32(paint.axis[0]), float32(paint.axis[1])}
+		u["StopCount"] = float32(paint.count)
+		var offsets [gradientMaxStops]float32
+		var colors [gradientMaxStops * 4]float32
+		for i := 0; i < paint.count; i++ {
+			offsets[i] = float32(paint.offsets[i])
+			for k, v := range paint.colors[i] {
+				colors[i*4+k] = float32(v)
+			}
+		}
+		u["StopOffsets"] = offsets
+		u["StopColors"] = colors
+		b.drawEffect(s, cmd.Rect, u)
+	case OpBoxShadow:
+		geom := newShadowGeometry(cmd.Rect, cmd.Radius, cmd.Shadow)
+		mode := 1
+		if cmd.Shadow.Inset {
+			mode = 2
+		}
+		u := b.effectUniforms(mode, cmd.Rect, cmd.Radius)
+		u["Shape"] = rectVec4(geom.shape)
+		u["ShapeRadius"] = float32(geom.shapeRadius)
+		u["Sigma"] = float32(geom.sigma)
+		c := cmd.Shadow.Color
+		a := float32(c.A) / 255
+		u["Color"] = [4]float32{float32(c.R) / 255 * a, float32(c.G) / 255 * a, float32(c.B) / 255 * a, a}
+		b.drawEffect(s, geom.area, u)
+	}
+}
+
+// effectUniforms 准备渐变和阴影着色器的公共参数（复用同一个映射）
+func (b *EbitenBackend) effectUniforms(mode int, box image.Rectangle, radius int) map[string]interface{} {
+	if b.uniforms == nil {
+		b.uniforms = make(map[string]interface{})
+	}
+	for k := range b.uniforms {
+		delete(b.uniforms, k)
+	}
+	b.uniforms["Mode"] = float32(mode)
+	b.uniforms["Box"] = rectVec4(box)
+	b.uniforms["BoxRadius"] = float32(radius)
+	return b.uniforms
+}
+
+// drawEffect 用着色器绘制覆盖area（目标坐标系）的矩形，源坐标为目标坐标系中的绝对坐标
+func (b *EbitenBackend) drawEffect(s *ebitenSurface, area image.Rectangle, uniforms map[string]interface{}) {
+	shader := loadEffectShader()
+	if shader == nil || area.Empty() {
+		return
+	}
+	dst := area.Sub(s.origin)
+	b.verts = b.verts[:0]
+	for _, p := range [4]image.Point{area.Min, {area.Max.X, area.Min.Y}, {area.Min.X, area.Max.Y}, area.Max} {
+		d := p.Sub(area.Min).Add(dst.Min)
+		b.verts = append(b.verts, ebiten.Vertex{
+			DstX: float32(d.X), DstY: float32(d.Y),
+			SrcX: float32(p.X), SrcY: float32(p.Y),
+			ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1,
+		})
+	}
+	b.indices = append(b.indices[:0], 0, 1, 2, 1, 3, 2)
+	s.image.DrawTrianglesShader(b.verts, b.indices, shader, &ebiten.DrawTrianglesShaderOptions{Uniforms: uniforms})
+}
+
+// rectVec4 把矩形转换为着色器的vec4（minX, minY, maxX, maxY）
+func rectVec4(r image.Rectangle) [4]float32 {
+	return [4]float32{float32(r.Min.X), float32(r.Min.Y), float32(r.Max.X), float32(r.Max.Y)}
+}
+
+// boolFloat 把布尔值转换为着色器中的0或1
+func boolFloat(v bool) float32 {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// fillPath 用纯色填充当前路径
+func (b *EbitenBackend) fillPath(dst *ebiten.Image, c RGBA, rule ebiten.FillRule) {
+	b.verts, b.indices = b.path.AppendVerticesAndIndicesForFilling(b.verts[:0], b.indices[:0])
+	r, g, bl, a := float32(c.R)/255, float32(c.G)/255, float32(c.B)/255, float32(c.A)/255
+	for i := range b.verts {
+		// 顶点颜色为预乘alpha
+		b.verts[i].ColorR = r * a
+		b.verts[i].ColorG = g * a
+		b.verts[i].ColorB = bl * a
+		b.verts[i].ColorA = a
+	}
+	dst.DrawTriangles(b.verts, b.indices, whitePixel(), &ebiten.DrawTrianglesOptions{FillRule: rule})
+}
+
+// pushClip 把当前表面裁剪到矩形
+func (b *EbitenBackend) pushClip(r image.Rectangle) {
+	top := b.stack[len(b.stack)-1]
+	s := ebitenSurface{image: top.image, origin: top.origin, empty: top.empty}
+	if !s.empty {
+		clip := r.Sub(top.origin).Intersect(top.image.Bounds())
+		if clip.Empty() {
+			s.empty = true
+		} else {
+			s.image = top.image.SubImage(clip).(*ebiten.Image)
+		}
+	}
+	b.stack = append(b.stack, s)
+}
+
+// pushLayer 开始绘制到离屏图层
+func (b *EbitenBackend) pushLayer(r image.Rectangle, opacity int) {
+	top := b.stack[len(b.stack)-1]
+	s := ebitenSurface{layer: true, opacity: opacity, empty: top.empty}
+	if !s.empty {
+		rect := r.Intersect(top.image.Bounds().Add(top.origin))
+		if rect.Empty() || opacity <= 0 {
+			s.empty = true
+		} else {
+			s.image = scratchImages.Get(rect.Dx(), rect.Dy())
+			s.origin, s.rect = rect.Min, rect
+		}
+	}
+	b.stack = append(b.stack, s)
+}
+
+// pop 结束最近的裁剪或图层（图层按透明度合成到下层表面）
+func (b *EbitenBackend) pop() {
+	if len(b.stack) <= 1 {
+		return
+	}
+	s := b.stack[len(b.stack)-1]
+	b.stack[len(b.stack)-1] = ebitenSurface{}
+	b.stack = b.stack[:len(b.stack)-1]
+	if !s.layer || s.image == nil {
+		return
+	}
+	below := b.stack[len(b.stack)-1]
+	op := &ebiten.DrawImageOptions{}
+	at := s.rect.Min.Sub(below.origin)
+	op.GeoM.Translate(float64(at.X), float64(at.Y))
+	if s.opacity < 100 {
+		op.ColorScale.ScaleAlpha(float32(s.opacity) / 100)
+	}
+	below.image.DrawImage(s.image, op)
+	scratchImages.Put(s.image)
+}
+
+// source 获取可以绘制的ebiten源图像（其他图像首次使用时转换并缓存）
+func (b *EbitenBackend) source(img image.Image) *ebiten.Image {
+	if e, ok := img.(*ebiten.Image); ok {
+		return e
+	}
+	if e, ok := b.sources[img]; ok {
+		return e
+	}
+	if b.sources == nil {
+		b.sources = make(map[image.Image]*ebiten.Image)
+	}
+	e := ebiten.NewImageFromImage(img)
+	b.sources[img] = e
+	return e
+}
+
+// ReleaseSources 释放转换非ebiten源图像时创建的图像
+func (b *EbitenBackend) ReleaseSources() {
+	for img, e := range b.sources {
+		e.Dispose()
+		delete(b.sources, img)
+	}
+}
+
+// appendRoundedRect 向路径添加顺时针的圆角矩形子路径（半径不超过短边的一半）
+func appendRoundedRect(path *vector.Path, rect image.Rectangle, radius int) {
+	x := float32(rect.Min.X)
+	y := float32(rect.Min.Y)
+	w := float32(rect.Dx())
+	h := float32(rect.Dy())
+	rad := min(float32(radius), min(w, h)/2)
+
+	path.MoveTo(x+rad, y)
+	path.LineTo(x+w-rad, y)
+	path.Arc(x+w-rad, y+rad, rad, -math.Pi/2, 0, vector.Clockwise) // 右上角
+	path.LineTo(x+w, y+h-rad)
+	path.Arc(x+w-rad, y+h-rad, rad, 0, math.Pi/2, vector.Clockwise) // 右下角
+	path.LineTo(x+rad, y+h)
+	path.Arc(x+rad, y+h-rad, rad, math.Pi/2, math.Pi, vector.Clockwise) // 左下角
+	path.LineTo(x, y+rad)
+	path.Arc(x+rad, y+rad, rad, math.Pi, math.Pi*3/2, vector.Clockwise) // 左上角
+	path.Close()
+}
+
+// nrgba 转换为标准库的非预乘颜色
+func nrgba(c RGBA) color.NRGBA {
+	return color.NRGBA{R: c.R, G: c.G, B: c.B, A: c.A}
+}
@@ -0,0 +1,246 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// SoftwareBackend 用纯Go（image/draw）把绘制命令绘制到*image.RGBA的后端
+// 不依赖GPU，可以在无图形环境中生成截图或者进行像素级的golden测试
+type SoftwareBackend struct {
+	// Target 绘制目标
+	Target *image.RGBA
+	// Sources ebiten图像对应的CPU侧图像（ebiten图像的像素不能在绘制之外读取，没有对应图像的ebiten图像会被跳过）
+	Sources map[*ebiten.Image]image.Image
+
+	stack []softwareSurface
+}
+
+// softwareSurface 当前的绘制表面
+type softwareSurface struct {
+	dst     *image.RGBA
+	clip    image.Rectangle // 目标坐标系中的裁剪区域
+	layer   bool
+	opacity int
+}
+
+// NewSoftwareBackend 创建绘制到target的纯Go后端
+func NewSoftwareBackend(target *image.RGBA) *SoftwareBackend {
+	return &SoftwareBackend{Target: target}
+}
+
+// Execute 执行命令列表（未配对的裁剪和图层在结束时自动关闭）
+func (b *SoftwareBackend) Execute(list *DrawList) {
+	if b.Target == nil || list == nil {
+		return
+	}
+	b.stack = append(b.stack[:0], softwareSurface{dst: b.Target, clip: b.Target.Bounds()})
+
+	for i := range list.Commands {
+		cmd := &list.Commands[i]
+		top := b.stack[len(b.stack)-1]
+		switch cmd.Op {
+		case OpPushClip:
+			top.clip = top.clip.Intersect(cmd.Rect)
+			top.layer = false
+			b.stack = append(b.stack, top)
+			continue
+		case OpPushLayer:
+			clip := top.clip.Intersect(cmd.Rect)
+			if cmd.Opacity <= 0 {
+				clip = image.Rectangle{}
+			}
+			b.stack = append(b.stack, softwareSurface{dst: image.NewRGBA(clip), clip: clip, layer: true, opacity: cmd.Opacity})
+			continue
+		case OpPopClip, OpPopLayer:
+			b.pop()
+			continue
+		}
+		if top.clip.Empty() {
+			continue
+		}
+		b.execute(top, cmd)
+	}
+
+	for len(b.stack) > 1 {
+		b.pop()
+	}
+	b.stack = b.stack[:0]
+}
+
+// execute 在表面上执行一条绘制命令
+func (b *SoftwareBackend) execute(s softwareSurface, cmd *DrawCommand) {
+	switch cmd.Op {
+	case OpFillRect:
+		draw.Draw(s.dst, cmd.Rect.Intersect(s.clip), image.NewUniform(nrgba(cmd.Color)), image.Point{}, draw.Over)
+	case OpFillRoundedRect:
+		fillShape(s.dst, cmd.Rect.Intersect(s.clip), nrgba(cmd.Color), func(x, y int) bool {
+			return insideRoundedRect(cmd.Rect, cmd.Radius, x, y)
+		})
+	case OpStrokeRoundedRect:
+		inner := cmd.Rect.Inset(cmd.Width)
+		innerRadius := max(cmd.Radius-cmd.Width, 0)
+		fillShape(s.dst, cmd.Rect.Intersect(s.clip), nrgba(cmd.Color), func(x, y int) bool {
+			return insideRoundedRect(cmd.Rect, cmd.Radius, x, y) &&
+				(inner.Empty() || !insideRoundedRect(inner, innerRadius, x, y))
+		})
+	case OpImage:
+		src := b.source(cmd.Image)
+		if src == nil {
+			return
+		}
+		sr := src.Bounds()
+		if !cmd.Source.Empty() {
+			sr = cmd.Source.Add(sr.Min).Intersect(sr)
+		}
+		if sr.Empty() {
+			return
+		}
+		var opts *xdraw.Options
+		if cmd.Opacity < 100 {
+			opts = &xdraw.Options{DstMask: image.NewUniform(color.Alpha{A: uint8(cmd.Opacity * 255 / 100)})}
+		}
+		dst := s.dst.SubImage(s.clip).(*image.RGBA)
+		xdraw.NearestNeighbor.Scale(dst, cmd.Rect, src, sr, draw.Over, opts)
+	case OpFillGradient:
+		paint := newGradientPaint(cmd.Gradient, cmd.Rect)
+		if paint.count == 0 {
+			return
+		}
+		forEachPixel(cmd.Rect.Intersect(s.clip), func(x, y int, px, py float64) {
+			if roundedRectDistance(cmd.Rect, cmd.Radius, px, py) <= 0 {
+				t := paint.position(px, py)
+				blendPremultiplied(s.dst, x, y, paint.colorAt(t), 1)
+			}
+		})
+	case OpBoxShadow:
+		geom := newShadowGeometry(cmd.Rect, cmd.Radius, cmd.Shadow)
+		c := nrgba(cmd.Shadow.Color)
+		a := float64(c.A) / 255
+		color := [4]float64{float64(c.R) / 255 * a, float64(c.G) / 255 * a, float64(c.B) / 255 * a, a}
+		forEachPixel(geom.area.Intersect(s.clip), func(x, y int, px, py float64) {
+			// 外阴影只绘制在控件之外，内阴影只绘制在控件之内
+			if inBox := roundedRectDistance(cmd.Rect, cmd.Radius, px, py) <= 0; inBox == cmd.Shadow.Inset {
+				blendPremultiplied(s.dst, x, y, color, geom.coverage(px, py, cmd.Shadow.Inset))
+			}
+		})
+	case OpText:
+		d := font.Drawer{
+			Dst:  s.dst.SubImage(s.clip).(*image.RGBA),
+			Src:  image.NewUniform(nrgba(cmd.Color)),
+			Face: cmd.Face,
+			Dot:  fixed.P(cmd.Rect.Min.X, cmd.Rect.Min.Y),
+		}
+		// 与ebiten的text.Draw一致：换行符回到起点并下移一个行高
+		for i, line := range strings.Split(cmd.Text, "\n") {
+			if i > 0 {
+				d.Dot.X = fixed.I(cmd.Rect.Min.X)
+				d.Dot.Y += cmd.Face.Metrics().Height
+			}
+			d.DrawString(line)
+		}
+	}
+}
+
+// pop 结束最近的裁剪或图层（图层按透明度合成到下层表面）
+func (b *SoftwareBackend) pop() {
+	if len(b.stack) <= 1 {
+		return
+	}
+	s := b.stack[len(b.stack)-1]
+	b.stack = b.stack[:len(b.stack)-1]
+	if !s.layer || s.clip.Empty() {
+		return
+	}
+	below := b.stack[len(b.stack)-1]
+	var mask image.Image
+	if s.opacity < 100 {
+		mask = image.NewUniform(color.Alpha{A: uint8(s.opacity * 255 / 100)})
+	}
+	draw.DrawMask(below.dst, s.clip.Intersect(below.clip), s.dst, s.clip.Min, mask, image.Point{}, draw.Over)
+}
+
+// source 获取CPU侧的源图像
+func (b *SoftwareBackend) source(img image.Image) image.Image {
+	if e, ok := img.(*ebiten.Image); ok {
+		src, ok := b.Sources[e]
+		if !ok {
+			return nil
+		}
+		return src
+	}
+	return img
+}
+
+// fillShape 用纯色填充区域内像素中心位于形状内的像素（不做抗锯齿，与ebiten后端的路径填充一致）
+func fillShape(dst *image.RGBA, area image.Rectangle, c color.NRGBA, inside func(x, y int) bool) {
+	src := image.NewUniform(c)
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		start := -1
+		for x := area.Min.X; x <= area.Max.X; x++ {
+			in := x < area.Max.X && inside(x, y)
+			if in && start < 0 {
+				start = x
+			} else if !in && start >= 0 {
+				draw.Draw(dst, image.Rect(start, y, x, y+1), src, image.Point{}, draw.Over)
+				start = -1
+			}
+		}
+	}
+}
+
+// forEachPixel 对区域内的每个像素调用fn（px, py为像素中心）
+func forEachPixel(area image.Rectangle, fn func(x, y int, px, py float64)) {
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			fn(x, y, float64(x)+0.5, float64(y)+0.5)
+		}
+	}
+}
+
+// blendPremultiplied 把预乘颜色c按覆盖率coverage（0-1）合成到像素上（source-over）
+func blendPremultiplied(dst *image.RGBA, x, y int, c [4]float64, coverage float64) {
+	if coverage <= 0 || c[3] <= 0 {
+		return
+	}
+	i := dst.PixOffset(x, y)
+	p := dst.Pix[i : i+4 : i+4]
+	inv := 1 - c[3]*coverage
+	for k := range p {
+		p[k] = uint8(math.Round(max(0, min(c[k]*coverage*255+float64(p[k])*inv, 255))))
+	}
+}
+
+// insideRoundedRect 判断像素(x, y)的中心是否位于圆角矩形内（半径不超过短边的一半）
+func insideRoundedRect(r image.Rectangle, radius, x, y int) bool {
+	px, py := float64(x)+0.5, float64(y)+0.5
+	minX, minY := float64(r.Min.X), float64(r.Min.Y)
+	maxX, maxY := float64(r.Max.X), float64(r.Max.Y)
+	if px < minX || px > maxX || py < minY || py > maxY {
+		return false
+	}
+	rad := min(float64(radius), float64(min(r.Dx(), r.Dy()))/2)
+	dx := max(minX+rad-px, 0, px-(maxX-rad))
+	dy := max(minY+rad-py, 0, py-(maxY-rad))
+	return dx*dx+dy*dy <= rad*rad
+}
+
+// RenderToRGBA 用纯Go后端按层叠顺序把已经布局的控件树绘制为RGBA图像
+// sources为ebiten图像对应的CPU侧图像（例如Loader.ImageSources()），可以为nil
+func RenderToRGBA(roots []Widget, width, height int, sources map[*ebiten.Image]image.Image) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	var list DrawList
+	RecordWidgets(&list, roots, width, height)
+	backend := NewSoftwareBackend(dst)
+	backend.Sources = sources
+	backend.Execute(&list)
+	return dst
+}
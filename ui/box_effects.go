@@ -0,0 +1,549 @@
+package ui
+
+import (
+	"image"
+	"log"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// GradientType 渐变类型
+type GradientType string
+
+const (
+	GradientLinear GradientType = "linear" // 线性渐变
+	GradientRadial GradientType = "radial" // 径向渐变
+)
+
+// gradientMaxStops 渐变最多使用的色标数量（多出的色标被忽略）
+const gradientMaxStops = 8
+
+// GradientStop 渐变色标
+type GradientStop struct {
+	Offset float64 `json:"offset"` // 位置（0-1）
+	Color  RGBA    `json:"color"`
+}
+
+// Gradient 背景渐变（按控件的圆角裁剪）
+// 线性渐变的方向与CSS相同：0度向上、90度向右、180度向下，渐变线的长度使两端的色标正好经过矩形的角
+// 径向渐变以(CenterX, CenterY)（相对控件边界，0-1）为中心，椭圆经过距离中心最远的角
+// 颜色按预乘alpha插值，位置小于前一个色标的色标被调整到前一个色标的位置
+type Gradient struct {
+	Type    GradientType   `json:"type"`
+	Angle   float64        `json:"angle"`
+	CenterX float64        `json:"centerX"`
+	CenterY float64        `json:"centerY"`
+	Stops   []GradientStop `json:"stops"`
+}
+
+// IsZero 是否没有渐变
+func (g Gradient) IsZero() bool {
+	return len(g.Stops) == 0
+}
+
+// BoxShadow 盒子阴影
+// 外阴影绘制在控件边界之外（控件内部不绘制，半透明背景下不会透出阴影），内阴影绘制在控件边界之内
+// 阴影形状为控件的圆角矩形偏移(OffsetX, OffsetY)后外扩Spread（内阴影为内缩），Blur为模糊半径（高斯模糊的标准差为Blur/2）
+type BoxShadow struct {
+	OffsetX int  `json:"offsetX"`
+	OffsetY int  `json:"offsetY"`
+	Blur    int  `json:"blur"`
+	Spread  int  `json:"spread"`
+	Color   RGBA `json:"color"`
+	Inset   bool `json:"inset"`
+}
+
+// BorderStyle 边框样式
+type BorderStyle string
+
+const (
+	BorderSolid  BorderStyle = "solid"  // 实线（默认）
+	BorderDashed BorderStyle = "dashed" // 虚线：四个角为实线，直边上的虚线段和间隔等长并对称分布
+	BorderDouble BorderStyle = "double" // 双线：两条线各占边框宽度的三分之一
+)
+
+// parseGradient 解析.ui文件和主题中的渐变声明，例如
+// {"type": "linear", "angle": 90, "stops": [{"offset": 0, "color": "#ff0000"}, {"offset": 1, "color": "#0000ff", "alpha": 128}]}
+// 省略angle时为180（向下），省略centerX/centerY时为0.5；色标的color也可以用#RRGGBBAA格式给出透明度
+func parseGradient(data map[string]interface{}) Gradient {
+	g := Gradient{Type: GradientLinear, Angle: 180, CenterX: 0.5, CenterY: 0.5}
+	if t, ok := data["type"].(string); ok {
+		g.Type = GradientType(t)
+	}
+	if angle, ok := data["angle"].(float64); ok {
+		g.Angle = angle
+	}
+	if cx, ok := data["centerX"].(float64); ok {
+		g.CenterX = cx
+	}
+	if cy, ok := data["centerY"].(float64); ok {
+		g.CenterY = cy
+	}
+	stops, _ := data["stops"].([]interface{})
+	for i, item := range stops {
+		stop, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		s := GradientStop{Color: parseEffectColor(stop)}
+		if offset, ok := stop["offset"].(float64); ok {
+			s.Offset = offset
+		} else if len(stops) > 1 {
+			// 省略位置时均匀分布
+			s.Offset = float64(i) / float64(len(stops)-1)
+		}
+		g.Stops = append(g.Stops, s)
+	}
+	return g
+}
+
+// parseBoxShadows 解析.ui文件和主题中的阴影声明（单个对象或数组），例如
+// [{"offsetX": 0, "offsetY": 4, "blur": 8, "spread": 0, "color": "#000000", "alpha": 96}, {"blur": 6, "color": "#ffffff40", "inset": true}]
+func parseBoxShadows(value interface{}) []BoxShadow {
+	items, ok := value.([]interface{})
+	if !ok {
+		items = []interface{}{value}
+	}
+	var shadows []BoxShadow
+	for _, item := range items {
+		data, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		s := BoxShadow{Color: parseEffectColor(data)}
+		if x, ok := data["offsetX"].(float64); ok {
+			s.OffsetX = int(x)
+		}
+		if y, ok := data["offsetY"].(float64); ok {
+			s.OffsetY = int(y)
+		}
+		if blur, ok := data["blur"].(float64); ok {
+			s.Blur = max(int(blur), 0)
+		}
+		if spread, ok := data["spread"].(float64); ok {
+			s.Spread = int(spread)
+		}
+		if inset, ok := data["inset"].(bool); ok {
+			s.Inset = inset
+		}
+		shadows = append(shadows, s)
+	}
+	return shadows
+}
+
+// parseEffectColor 解析渐变色标和阴影的颜色（"color"为#RRGGBB或#RRGGBBAA，"alpha"覆盖透明度，默认不透明黑色）
+func parseEffectColor(data map[string]interface{}) RGBA {
+	c := RGBA{A: 255}
+	if s, ok := data["color"].(string); ok {
+		parsed, err := parseHexColor(s)
+		if err != nil {
+			log.Printf("[Style] %v", err)
+		} else {
+			c = parsed
+		}
+	}
+	if alpha, ok := data["alpha"].(float64); ok {
+		c.A = uint8(max(0, min(alpha, 255)))
+	}
+	return c
+}
+
+// FillGradient 用渐变填充圆角矩形（半径为0时为矩形）
+func (l *DrawList) FillGradient(r image.Rectangle, radius int, g Gradient) {
+	if g.IsZero() || r.Empty() {
+		return
+	}
+	l.add(DrawCommand{Op: OpFillGradient, Rect: r, Radius: radius, Gradient: g})
+}
+
+// DrawBoxShadow 绘制圆角矩形r的外阴影或内阴影
+func (l *DrawList) DrawBoxShadow(r image.Rectangle, radius int, s BoxShadow) {
+	if s.Color.A == 0 || r.Empty() {
+		return
+	}
+	l.add(DrawCommand{Op: OpBoxShadow, Rect: r, Radius: radius, Shadow: s})
+}
+
+// StrokeBorder 按样式在圆角矩形内侧绘制边框
+// 虚线的线段长度为dash（<=0时为边框宽度的3倍）；边框宽度小于3时双线按实线绘制
+func (l *DrawList) StrokeBorder(r image.Rectangle, radius, width int, style BorderStyle, dash int, c RGBA) {
+	if width <= 0 || c.A == 0 || r.Empty() {
+		return
+	}
+	switch style {
+	case BorderDouble:
+		if width < 3 {
+			break
+		}
+		line := width / 3
+		inset := width - line
+		l.StrokeRoundedRect(r, radius, line, c)
+		l.StrokeRoundedRect(r.Inset(inset), max(radius-inset, 0), line, c)
+		return
+	case BorderDashed:
+		l.strokeDashed(r, radius, width, dash, c)
+		return
+	}
+	l.StrokeRoundedRect(r, radius, width, c)
+}
+
+// strokeDashed 记录虚线边框：四个角（圆角时为圆弧）裁剪出实线边框，直边上绘制虚线段
+func (l *DrawList) strokeDashed(r image.Rectangle, radius, width, dash int, c RGBA) {
+	width = min(width, (min(r.Dx(), r.Dy())+1)/2)
+	radius = min(radius, min(r.Dx(), r.Dy())/2)
+	if dash <= 0 {
+		dash = 3 * width
+	}
+	corner := max(radius, width)
+	corner = min(corner, r.Dx()/2, r.Dy()/2)
+
+	// 四个角
+	corners := []image.Rectangle{
+		image.Rect(r.Min.X, r.Min.Y, r.Min.X+corner, r.Min.Y+corner),
+		image.Rect(r.Max.X-corner, r.Min.Y, r.Max.X, r.Min.Y+corner),
+		image.Rect(r.Min.X, r.Max.Y-corner, r.Min.X+corner, r.Max.Y),
+		image.Rect(r.Max.X-corner, r.Max.Y-corner, r.Max.X, r.Max.Y),
+	}
+	for _, box := range corners {
+		if radius > 0 {
+			l.PushClip(box)
+			l.StrokeRoundedRect(r, radius, width, c)
+			l.PopClip()
+		} else {
+			l.StrokeRect(box, width, c)
+		}
+	}
+
+	// 直边：n段虚线和n+1段间隔等分两角之间的长度，首尾都是间隔
+	horizontal := dashSegments(r.Min.X+corner, r.Max.X-corner, dash)
+	for _, seg := range horizontal {
+		l.FillRect(image.Rect(seg[0], r.Min.Y, seg[1], r.Min.Y+width), c)
+		l.FillRect(image.Rect(seg[0], r.Max.Y-width, seg[1], r.Max.Y), c)
+	}
+	vertical := dashSegments(r.Min.Y+corner, r.Max.Y-corner, dash)
+	for _, seg := range vertical {
+		l.FillRect(image.Rect(r.Min.X, seg[0], r.Min.X+width, seg[1]), c)
+		l.FillRect(image.Rect(r.Max.X-width, seg[0], r.Max.X, seg[1]), c)
+	}
+}
+
+// dashSegments 把[start, end)等分为间隔、虚线、间隔……间隔，返回各虚线段的起止位置
+func dashSegments(start, end, dash int) [][2]int {
+	length := end - start
+	n := int(math.Round((float64(length)/float64(dash) - 1) / 2))
+	if n <= 0 {
+		return nil
+	}
+	step := float64(length) / float64(2*n+1)
+	segments := make([][2]int, 0, n)
+	for i := 0; i < n; i++ {
+		a := start + int(math.Round(step*float64(2*i+1)))
+		b := start + int(math.Round(step*float64(2*i+2)))
+		if b > a {
+			segments = append(segments, [2]int{a, b})
+		}
+	}
+	return segments
+}
+
+// gradientPaint 在矩形上求值的渐变（两个后端使用相同的参数）
+type gradientPaint struct {
+	radial  bool
+	center  [2]float64
+	axis    [2]float64 // 线性渐变：方向除以渐变线长度；径向渐变：椭圆的两个半径
+	offsets [gradientMaxStops]float64
+	colors  [gradientMaxStops][4]float64 // 预乘alpha（0-1）
+	count   int
+}
+
+// newGradientPaint 计算渐变在矩形r上的参数
+func newGradientPaint(g Gradient, r image.Rectangle) gradientPaint {
+	var p gradientPaint
+	w, h := float64(r.Dx()), float64(r.Dy())
+	if g.Type == GradientRadial {
+		p.radial = true
+		p.center = [2]float64{float64(r.Min.X) + g.CenterX*w, float64(r.Min.Y) + g.CenterY*h}
+		rx := max(g.CenterX, 1-g.CenterX) * w * math.Sqrt2
+		ry := max(g.CenterY, 1-g.CenterY) * h * math.Sqrt2
+		p.axis = [2]float64{max(rx, 1e-6), max(ry, 1e-6)}
+	} else {
+		rad := g.Angle * math.Pi / 180
+		dx, dy := math.Sin(rad), -math.Cos(rad)
+		length := max(math.Abs(w*dx)+math.Abs(h*dy), 1e-6)
+		p.center = [2]float64{float64(r.Min.X) + w/2, float64(r.Min.Y) + h/2}
+		p.axis = [2]float64{dx / length, dy / length}
+	}
+
+	p.count = min(len(g.Stops), gradientMaxStops)
+	for i := 0; i < p.count; i++ {
+		s := g.Stops[i]
+		p.offsets[i] = s.Offset
+		if i > 0 {
+			p.offsets[i] = max(s.Offset, p.offsets[i-1])
+		}
+		a := float64(s.Color.A) / 255
+		p.colors[i] = [4]float64{float64(s.Color.R) / 255 * a, float64(s.Color.G) / 255 * a, float64(s.Color.B) / 255 * a, a}
+	}
+	return p
+}
+
+// position 像素中心(px, py)在渐变线上的位置
+func (p *gradientPaint) position(px, py float64) float64 {
+	x, y := px-p.center[0], py-p.center[1]
+	if p.radial {
+		return math.Hypot(x/p.axis[0], y/p.axis[1])
+	}
+	return x*p.axis[0] + y*p.axis[1] + 0.5
+}
+
+// colorAt 渐变线上位置t处的预乘颜色（与着色器中的算法相同）
+func (p *gradientPaint) colorAt(t float64) [4]float64 {
+	c := p.colors[0]
+	for i := 1; i < p.count; i++ {
+		o0, o1 := p.offsets[i-1], p.offsets[i]
+		if t <= o0 {
+			continue
+		}
+		f := 1.0
+		if o1 > o0 {
+			f = max(0, min((t-o0)/(o1-o0), 1))
+		}
+		for k := range c {
+			c[k] = p.colors[i-1][k] + (p.colors[i][k]-p.colors[i-1][k])*f
+		}
+	}
+	return c
+}
+
+// shadowGeometry 阴影的形状和需要绘制的区域
+type shadowGeometry struct {
+	shape       image.Rectangle
+	shapeRadius int
+	sigma       float64
+	area        image.Rectangle // 需要求值的像素区域（外阴影为模糊后的形状范围，内阴影为控件边界）
+}
+
+// newShadowGeometry 计算圆角矩形box的阴影形状
+func newShadowGeometry(box image.Rectangle, radius int, s BoxShadow) shadowGeometry {
+	g := shadowGeometry{sigma: float64(s.Blur) / 2}
+	shape := box.Add(image.Pt(s.OffsetX, s.OffsetY))
+	if s.Inset {
+		g.shape = shape.Inset(s.Spread)
+		g.shapeRadius = max(radius-s.Spread, 0)
+		g.area = box
+	} else {
+		g.shape = shape.Inset(-s.Spread)
+		if radius > 0 {
+			g.shapeRadius = max(radius+s.Spread, 0)
+		}
+		g.area = g.shape.Inset(-int(math.Ceil(g.sigma * 3)))
+	}
+	return g
+}
+
+// coverage 阴影在像素中心(px, py)处的不透明度（0-1，不含控件边界的遮罩）
+// 模糊按到形状边缘的距离用误差函数近似，直边处与高斯模糊一致
+func (g *shadowGeometry) coverage(px, py float64, inset bool) float64 {
+	var a float64
+	if g.shape.Empty() {
+		a = 0
+	} else {
+		d := roundedRectDistance(g.shape, g.shapeRadius, px, py)
+		if g.sigma <= 0 {
+			if d <= 0 {
+				a = 1
+			}
+		} else {
+			a = 0.5 - 0.5*math.Erf(d/(g.sigma*math.Sqrt2))
+		}
+	}
+	if inset {
+		return 1 - a
+	}
+	return a
+}
+
+// roundedRectDistance 点到圆角矩形边缘的有向距离（内部为负，半径不超过短边的一半）
+func roundedRectDistance(r image.Rectangle, radius int, px, py float64) float64 {
+	cx, cy := float64(r.Min.X+r.Max.X)/2, float64(r.Min.Y+r.Max.Y)/2
+	hw, hh := float64(r.Dx())/2, float64(r.Dy())/2
+	rad := min(float64(radius), hw, hh)
+	qx := math.Abs(px-cx) - hw + rad
+	qy := math.Abs(py-cy) - hh + rad
+	return math.Hypot(max(qx, 0), max(qy, 0)) + min(max(qx, qy), 0) - rad
+}
+
+// boxDecorator 具有扩展盒子外观（渐变、阴影、边框样式）的控件（嵌入BaseWidget的控件都实现了该接口）
+type boxDecorator interface {
+	paintOuterShadows(list *DrawList, bounds image.Rectangle, opacity int)
+	paintFill(list *DrawList, bounds image.Rectangle, bg RGBA)
+	paintInnerShadows(list *DrawList, bounds image.Rectangle)
+	paintBorder(list *DrawList, bounds image.Rectangle)
+	outerShadowBounds(bounds image.Rectangle) image.Rectangle
+}
+
+// paintOuterShadows 记录外阴影（位于控件边界之外，需要在pushGroup之前记录，透明度直接作用于阴影颜色）
+func (w *BaseWidget) paintOuterShadows(list *DrawList, bounds image.Rectangle, opacity int) {
+	for _, s := range w.BoxShadow {
+		if s.Inset {
+			continue
+		}
+		if opacity < 100 {
+			s.Color.A = uint8(int(s.Color.A) * max(opacity, 0) / 100)
+		}
+		list.DrawBoxShadow(bounds, w.BorderRadius, s)
+	}
+}
+
+// outerShadowBounds 计算边界为bounds的控件连同外阴影（偏移、扩展和模糊之后）覆盖的区域
+func (w *BaseWidget) outerShadowBounds(bounds image.Rectangle) image.Rectangle {
+	area := bounds
+	for _, s := range w.BoxShadow {
+		if !s.Inset {
+			area = area.Union(newShadowGeometry(bounds, w.BorderRadius, s).area)
+		}
+	}
+	return area
+}
+
+// paintFill 记录背景填充：设置了渐变时使用渐变，否则使用颜色bg，均按圆角裁剪
+func (w *BaseWidget) paintFill(list *DrawList, bounds image.Rectangle, bg RGBA) {
+	if !w.BackgroundGradient.IsZero() {
+		list.FillGradient(bounds, w.BorderRadius, w.BackgroundGradient)
+		return
+	}
+	list.FillRoundedRect(bounds, w.BorderRadius, bg)
+}
+
+// paintInnerShadows 记录内阴影（在背景之上、边框之下）
+func (w *BaseWidget) paintInnerShadows(list *DrawList, bounds image.Rectangle) {
+	for _, s := range w.BoxShadow {
+		if s.Inset {
+			list.DrawBoxShadow(bounds, w.BorderRadius, s)
+		}
+	}
+}
+
+// paintBorder 记录边框（实线、虚线或双线）
+func (w *BaseWidget) paintBorder(list *DrawList, bounds image.Rectangle) {
+	list.StrokeBorder(bounds, w.BorderRadius, w.BorderWidth, w.BorderStyle, w.BorderDash, w.GetBorderColor())
+}
+
+// hasBoxDecoration 是否有需要绘制的背景、阴影或边框
+func (w *BaseWidget) hasBoxDecoration() bool {
+	return w.BackgroundAlpha > 0 || w.backgroundImage != nil || !w.BackgroundGradient.IsZero() ||
+		len(w.BoxShadow) > 0 || w.BorderWidth > 0 && w.BorderAlpha > 0
+}
+
+// effectShaderSource 渐变和阴影的着色器（坐标通过顶点的源坐标传入，为目标坐标系中的绝对坐标）
+const effectShaderSource = `//kage:unit pixels
+
+package main
+
+var Mode float // 0: 渐变填充 1: 外阴影 2: 内阴影
+var Box vec4
+var BoxRadius float
+var Shape vec4
+var ShapeRadius float
+var Sigma float
+var Color vec4
+var Radial float
+var Center vec2
+var Axis vec2
+var StopCount float
+var StopOffsets [8]float
+var StopColors [8]vec4
+
+func roundedDistance(p vec2, box vec4, radius float) float {
+	center := (box.xy + box.zw) / 2
+	half := (box.zw - box.xy) / 2
+	r := min(radius, min(half.x, half.y))
+	q := abs(p-center) - half + r
+	return length(max(q, 0)) + min(max(q.x, q.y), 0) - r
+}
+
+func erf(x float) float {
+	a := abs(x)
+	t := 1 / (1 + 0.3275911*a)
+	y := 1 - (((((1.061405429*t-1.453152027)*t)+1.421413741)*t-0.284496736)*t+0.254829592)*t*exp(-a*a)
+	return sign(x) * y
+}
+
+func coverage(p vec2) float {
+	if Shape.z <= Shape.x || Shape.w <= Shape.y {
+		return 0
+	}
+	d := roundedDistance(p, Shape, ShapeRadius)
+	if Sigma <= 0 {
+		if d <= 0 {
+			return 1
+		}
+		return 0
+	}
+	return 0.5 - 0.5*erf(d/(Sigma*1.41421356))
+}
+
+func gradientColor(t float) vec4 {
+	c := StopColors[0]
+	for i := 1; i < 8; i++ {
+		if float(i) < StopCount {
+			o0 := StopOffsets[i-1]
+			o1 := StopOffsets[i]
+			if t > o0 {
+				f := 1.0
+				if o1 > o0 {
+					f = clamp((t-o0)/(o1-o0), 0, 1)
+				}
+				c = mix(StopColors[i-1], StopColors[i], f)
+			}
+		}
+	}
+	return c
+}
+
+func Fragment(dstPos vec4, srcPos vec2, color vec4) vec4 {
+	inBox := roundedDistance(srcPos, Box, BoxRadius) <= 0
+	if Mode < 0.5 {
+		if !inBox {
+			return vec4(0)
+		}
+		t := 0.0
+		if Radial > 0.5 {
+			t = length((srcPos - Center) / Axis)
+		} else {
+			t = dot(srcPos-Center, Axis) + 0.5
+		}
+		return gradientColor(t)
+	}
+	if Mode < 1.5 {
+		if inBox {
+			return vec4(0)
+		}
+		return Color * coverage(srcPos)
+	}
+	if !inBox {
+		return vec4(0)
+	}
+	return Color * (1 - coverage(srcPos))
+}
+`
+
+// effectShader 编译后的着色器（首次使用时编译，编译失败时为nil）
+var (
+	effectShader         *ebiten.Shader
+	effectShaderCompiled bool
+)
+
+// loadEffectShader 获取渐变和阴影的着色器
+func loadEffectShader() *ebiten.Shader {
+	if !effectShaderCompiled {
+		effectShaderCompiled = true
+		shader, err := ebiten.NewShader([]byte(effectShaderSource))
+		if err != nil {
+			log.Printf("[Renderer] failed to compile effect shader: %v", err)
+		}
+		effectShader = shader
+	}
+	return effectShader
+}
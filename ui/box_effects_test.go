@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"image"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// TestBoxEffects_LoaderParsesDeclarations 测试.ui中声明的渐变、阴影和边框样式
+func TestBoxEffects_LoaderParsesDeclarations(t *testing.T) {
+	loader := NewLoader()
+	roots, err := loader.LoadFromData(map[string]interface{}{
+		"widgets": []interface{}{
+			map[string]interface{}{"id": "panel", "type": "panel", "borderStyle": "dashed", "borderDash": float64(6),
+				"backgroundGradient": map[string]interface{}{
+					"angle": float64(90),
+					"stops": []interface{}{
+						map[string]interface{}{"color": "#ff000080"},
+						map[string]interface{}{"color": "#00ff00", "alpha": float64(200)},
+						map[string]interface{}{"color": "#0000ff"},
+					},
+				},
+				"boxShadow": []interface{}{
+					map[string]interface{}{"offsetX": float64(2), "offsetY": float64(3), "blur": float64(8), "spread": float64(1), "color": "#000000", "alpha": float64(100)},
+					map[string]interface{}{"blur": float64(4), "inset": true},
+				}},
+			map[string]interface{}{"id": "btn", "type": "button",
+				"backgroundGradient": map[string]interface{}{"type": "radial", "centerX": 0.25},
+				"boxShadow":          map[string]interface{}{"blur": float64(-3)}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	panel := roots[0].(*PanelWidget)
+	btn := roots[1].(*ButtonWidget)
+
+	wantGradient := Gradient{Type: GradientLinear, Angle: 90, CenterX: 0.5, CenterY: 0.5, Stops: []GradientStop{
+		{Offset: 0, Color: RGBA{255, 0, 0, 0x80}},
+		{Offset: 0.5, Color: RGBA{0, 255, 0, 200}},
+		{Offset: 1, Color: RGBA{0, 0, 255, 255}},
+	}}
+	if !reflect.DeepEqual(panel.BackgroundGradient, wantGradient) {
+		t.Errorf("expected gradient %+v, got %+v", wantGradient, panel.BackgroundGradient)
+	}
+	wantShadows := []BoxShadow{
+		{OffsetX: 2, OffsetY: 3, Blur: 8, Spread: 1, Color: RGBA{0, 0, 0, 100}},
+		{Blur: 4, Color: RGBA{0, 0, 0, 255}, Inset: true},
+	}
+	if !reflect.DeepEqual(panel.BoxShadow, wantShadows) {
+		t.Errorf("expected shadows %+v, got %+v", wantShadows, panel.BoxShadow)
+	}
+	if panel.BorderStyle != BorderDashed || panel.BorderDash != 6 {
+		t.Errorf("unexpected border style %q dash %d", panel.BorderStyle, panel.BorderDash)
+	}
+
+	// 没有色标的渐变不绘制；单个阴影可以不写成数组，负的模糊半径按0处理
+	if btn.BackgroundGradient.Type != GradientRadial || btn.BackgroundGradient.CenterX != 0.25 || !btn.BackgroundGradient.IsZero() {
+		t.Errorf("unexpected radial gradient %+v", btn.BackgroundGradient)
+	}
+	if len(btn.BoxShadow) != 1 || btn.BoxShadow[0].Blur != 0 {
+		t.Errorf("expected one shadow without blur, got %+v", btn.BoxShadow)
+	}
+}
+
+// TestBoxEffects_ThemeDeclaresEffects 测试主题声明的渐变和阴影，切换主题后恢复
+func TestBoxEffects_ThemeDeclaresEffects(t *testing.T) {
+	theme := mustTheme(t,
+		rule("panel", map[string]interface{}{
+			"backgroundGradient": map[string]interface{}{"type": "radial", "stops": []interface{}{
+				map[string]interface{}{"color": "#ffffff"},
+				map[string]interface{}{"color": "#00000000"},
+			}},
+			"boxShadow":   map[string]interface{}{"offsetY": float64(4), "blur": float64(6), "color": "#00000060"},
+			"borderStyle": "double",
+		}),
+	)
+	panel := NewPanel("p")
+	SetTheme([]Widget{panel}, theme)
+
+	if len(panel.BackgroundGradient.Stops) != 2 || panel.BackgroundGradient.Stops[1] != (GradientStop{Offset: 1, Color: RGBA{}}) {
+		t.Errorf("unexpected themed gradient %+v", panel.BackgroundGradient)
+	}
+	if !reflect.DeepEqual(panel.BoxShadow, []BoxShadow{{OffsetY: 4, Blur: 6, Color: RGBA{0, 0, 0, 0x60}}}) {
+		t.Errorf("unexpected themed shadow %+v", panel.BoxShadow)
+	}
+	if panel.BorderStyle != BorderDouble {
+		t.Errorf("expected double border, got %q", panel.BorderStyle)
+	}
+
+	SetTheme([]Widget{panel}, nil)
+	if !panel.BackgroundGradient.IsZero() || panel.BoxShadow != nil || panel.BorderStyle != "" {
+		t.Errorf("expected effects removed with the theme, got %+v %+v %q", panel.BackgroundGradient, panel.BoxShadow, panel.BorderStyle)
+	}
+}
+
+// TestBoxEffects_DashSegmentsSymmetric 测试虚线段等长分布且首尾间隔对称
+func TestBoxEffects_DashSegmentsSymmetric(t *testing.T) {
+	segments := dashSegments(10, 60, 6)
+	if len(segments) != 4 {
+		t.Fatalf("expected 4 dashes, got %v", segments)
+	}
+	if segments[0][0]-10 != 60-segments[len(segments)-1][1] {
+		t.Errorf("expected symmetric gaps, got %v", segments)
+	}
+	for _, s := range segments {
+		if n := s[1] - s[0]; n < 5 || n > 6 {
+			t.Errorf("expected dashes of about 6 pixels, got %v", segments)
+		}
+	}
+	if dashSegments(0, 10, 6) != nil {
+		t.Errorf("expected no dashes when the edge is too short")
+	}
+}
+
+// TestBoxEffects_BorderStyles 测试双线和虚线边框记录的命令
+func TestBoxEffects_BorderStyles(t *testing.T) {
+	var list DrawList
+	list.StrokeBorder(image.Rect(0, 0, 40, 30), 8, 6, BorderDouble, 0, RGBA{255, 0, 0, 255})
+	if got := ops(&list); !reflect.DeepEqual(got, []DrawOp{OpStrokeRoundedRect, OpStrokeRoundedRect}) {
+		t.Fatalf("expected two rounded strokes, got %v", got)
+	}
+	inner := list.Commands[1]
+	if list.Commands[0].Width != 2 || inner.Width != 2 || inner.Rect != image.Rect(4, 4, 36, 26) || inner.Radius != 4 {
+		t.Errorf("unexpected double border commands %v %v", list.Commands[0], inner)
+	}
+
+	// 宽度不足3时按实线绘制
+	list.Reset()
+	list.StrokeBorder(image.Rect(0, 0, 40, 30), 0, 2, BorderDouble, 0, RGBA{255, 0, 0, 255})
+	if got := ops(&list); len(got) != 4 || got[0] != OpFillRect {
+		t.Errorf("expected solid border for narrow double border, got %v", got)
+	}
+
+	// 虚线边框的实线部分不超出边框宽度
+	list.Reset()
+	list.StrokeBorder(image.Rect(0, 0, 60, 40), 0, 2, BorderDashed, 4, RGBA{255, 0, 0, 255})
+	img := image.NewRGBA(image.Rect(0, 0, 60, 40))
+	NewSoftwareBackend(img).Execute(&list)
+	if img.RGBAAt(30, 20).A != 0 || img.RGBAAt(2, 20).A != 0 {
+		t.Errorf("expected dashed border to stay within its width")
+	}
+	if img.RGBAAt(0, 0).A != 255 || img.RGBAAt(59, 39).A != 255 {
+		t.Errorf("expected solid corners")
+	}
+	var dashed, gaps int
+	for x := 2; x < 58; x++ {
+		if img.RGBAAt(x, 0).A == 255 {
+			dashed++
+		} else {
+			gaps++
+		}
+	}
+	if dashed == 0 || gaps == 0 {
+		t.Errorf("expected alternating dashes and gaps, got %d dashed and %d gap pixels", dashed, gaps)
+	}
+}
+
+// TestBoxEffects_ShadowGeometry 测试阴影的形状、绘制范围和模糊边缘的不透明度
+func TestBoxEffects_ShadowGeometry(t *testing.T) {
+	box := image.Rect(10, 10, 50, 40)
+	outer := newShadowGeometry(box, 4, BoxShadow{OffsetX: 3, OffsetY: 5, Blur: 4, Spread: 2})
+	if outer.shape != image.Rect(11, 13, 55, 47) || outer.shapeRadius != 6 || outer.area != image.Rect(5, 7, 61, 53) {
+		t.Errorf("unexpected outer shadow geometry %+v", outer)
+	}
+	// 边缘处为一半，向外衰减
+	if c := outer.coverage(55, 30, false); math.Abs(c-0.5) > 1e-9 {
+		t.Errorf("expected half coverage on the edge, got %v", c)
+	}
+	if c := outer.coverage(30, 30, false); c < 0.99 {
+		t.Errorf("expected full coverage inside the shape, got %v", c)
+	}
+	if c := outer.coverage(61, 30, false); c > 0.01 {
+		t.Errorf("expected no coverage beyond three sigma, got %v", c)
+	}
+
+	inner := newShadowGeometry(box, 4, BoxShadow{Spread: 3, Inset: true})
+	if inner.shape != image.Rect(13, 13, 47, 37) || inner.shapeRadius != 1 || inner.area != box {
+		t.Errorf("unexpected inset shadow geometry %+v", inner)
+	}
+	if inner.coverage(11.5, 20.5, true) != 1 || inner.coverage(30.5, 20.5, true) != 0 {
+		t.Errorf("expected hard inset shadow along the edges only")
+	}
+}
+
+// TestBoxEffects_SoftwareBackend 测试软件后端的渐变方向和阴影遮罩
+func TestBoxEffects_SoftwareBackend(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 60, 60))
+	var list DrawList
+	list.FillGradient(image.Rect(0, 0, 20, 60), 0, Gradient{Type: GradientLinear, Angle: 180, Stops: []GradientStop{
+		{Offset: 0, Color: RGBA{255, 0, 0, 255}},
+		{Offset: 1, Color: RGBA{0, 0, 255, 255}},
+	}})
+	list.DrawBoxShadow(image.Rect(30, 10, 50, 30), 0, BoxShadow{Blur: 6, Color: RGBA{0, 0, 0, 255}})
+	NewSoftwareBackend(img).Execute(&list)
+
+	top, bottom := img.RGBAAt(10, 0), img.RGBAAt(10, 59)
+	if top.R < 250 || top.B > 5 || bottom.B < 250 || bottom.R > 5 {
+		t.Errorf("expected red to blue from top to bottom, got %v and %v", top, bottom)
+	}
+	if mid := img.RGBAAt(10, 30); mid.R < 120 || mid.R > 135 || mid.A != 255 {
+		t.Errorf("expected half way color in the middle, got %v", mid)
+	}
+	if img.RGBAAt(40, 20).A != 0 {
+		t.Errorf("expected outer shadow not to be drawn inside the box")
+	}
+	near, far := img.RGBAAt(50, 20).A, img.RGBAAt(56, 20).A
+	if near == 0 || far >= near {
+		t.Errorf("expected shadow to fade outwards, got alpha %d then %d", near, far)
+	}
+}
+
+// TestGolden_BoxEffects 渐变背景、阴影和边框样式的golden图像
+func TestGolden_BoxEffects(t *testing.T) {
+	linear := NewPanel("linear")
+	linear.X, linear.Y, linear.Width, linear.Height = 10, 10, 100, 60
+	linear.BorderRadius = 12
+	linear.BackgroundGradient = Gradient{Type: GradientLinear, Angle: 135, Stops: []GradientStop{
+		{Offset: 0, Color: RGBA{250, 200, 60, 255}},
+		{Offset: 0.6, Color: RGBA{220, 60, 90, 255}},
+		{Offset: 1, Color: RGBA{70, 40, 160, 255}},
+	}}
+	linear.BoxShadow = []BoxShadow{{OffsetX: 3, OffsetY: 4, Blur: 8, Color: RGBA{0, 0, 0, 140}}}
+
+	radial := NewPanel("radial")
+	radial.X, radial.Y, radial.Width, radial.Height = 130, 10, 80, 80
+	radial.BorderRadius = 40
+	radial.BackgroundGradient = Gradient{Type: GradientRadial, CenterX: 0.35, CenterY: 0.35, Stops: []GradientStop{
+		{Offset: 0, Color: RGBA{255, 255, 255, 255}},
+		{Offset: 0.7, Color: RGBA{60, 140, 220, 255}},
+	}}
+	radial.BoxShadow = []BoxShadow{{Blur: 10, Spread: 2, Color: RGBA{60, 140, 220, 160}}}
+
+	inset := NewPanel("inset")
+	inset.X, inset.Y, inset.Width, inset.Height = 10, 90, 100, 40
+	inset.BorderRadius = 8
+	inset.BackgroundColor = RGBA{230, 230, 235, 255}
+	inset.BackgroundAlpha = 255
+	inset.BoxShadow = []BoxShadow{{OffsetX: 2, OffsetY: 3, Blur: 6, Color: RGBA{0, 0, 0, 150}, Inset: true}}
+
+	dashed := NewButton("dashed")
+	dashed.X, dashed.Y, dashed.Width, dashed.Height = 130, 100, 90, 30
+	dashed.Text = "Dashed"
+	dashed.BorderRadius = 6
+	dashed.BorderWidth = 2
+	dashed.BorderStyle = BorderDashed
+	dashed.BorderColor = RGBA{255, 255, 255, 255}
+	dashed.BorderAlpha = 255
+
+	double := NewLabel("double")
+	double.X, double.Y, double.Width, double.Height = 10, 145, 210, 30
+	double.Text = "Double border"
+	double.TextColor = RGBA{240, 200, 80, 255}
+	double.BorderWidth = 6
+	double.BorderStyle = BorderDouble
+	double.BorderColor = RGBA{240, 200, 80, 255}
+	double.BorderAlpha = 255
+
+	renderGolden(t, "box_effects", 230, 185, nil, linear, radial, inset, dashed, double)
+}
@@ -2,7 +2,6 @@ package ui
 
 import (
 	"image"
-	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text"
@@ -47,7 +46,7 @@ type ButtonWidget struct {
 	FontSize       int    `json:"fontSize"`
 	TextAlignment  string `json:"textAlignment"` // left, center, right
 
-	// 状态
+	// 状态（按下状态由输入分发器维护，CurrentState随BaseWidget.State同步）
 	CurrentState ButtonState
 	Enabled      bool
 
@@ -84,7 +83,7 @@ func NewButton(id string) *ButtonWidget {
 	}
 }
 
-// GetStateBackground 获取当前状态的背景属性
+// GetStateBackground 获取当前状态（禁用、按下、默认）的背景属性
 func (b *ButtonWidget) GetStateBackground() (RGBA, *ebiten.Image) {
 	if !b.Enabled {
 		bgColor := RGBA{
@@ -96,7 +95,7 @@ func (b *ButtonWidget) GetStateBackground() (RGBA, *ebiten.Image) {
 		return bgColor, b.backgroundImageDisabled
 	}
 
-	if b.CurrentState == ButtonStatePressed {
+	if b.state.Has(WidgetStatePressed) {
 		bgColor := RGBA{
 			R: b.BackgroundColorPressed.R,
 			G: b.BackgroundColorPressed.G,
@@ -115,25 +114,6 @@ func (b *ButtonWidget) GetStateBackground() (RGBA, *ebiten.Image) {
 	return bgColor, b.backgroundImageNormal
 }
 
-// Update 更新按钮状态
-func (b *ButtonWidget) Update() error {
-	// 处理鼠标交互
-	if b.Interactive && b.Enabled {
-		x, y := ebiten.CursorPosition()
-		if b.OnHover(x, y) {
-			if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-				b.CurrentState = ButtonStatePressed
-			} else {
-				b.CurrentState = ButtonStateNormal
-			}
-		} else {
-			b.CurrentState = ButtonStateNormal
-		}
-	}
-
-	return b.BaseWidget.Update()
-}
-
 // Draw 绘制按钮
 func (b *ButtonWidget) Draw(screen *ebiten.Image, parentX, parentY, parentWidth, parentHeight int) {
 	if !b.Visible {
@@ -148,86 +128,78 @@ func (b *ButtonWidget) Draw(screen *ebiten.Image, parentX, parentY, parentWidth,
 	// 计算响应式尺寸（支持边界锚定）
 	renderWidth, renderHeight := b.CalculateSize(parentWidth, parentHeight, localX, localY)
 
-	// 绘制背景和边框
-	bgColor, bgImage := b.GetStateBackground()
+	drawPainted(screen, b, image.Rect(absX, absY, absX+renderWidth, absY+renderHeight))
 
-	// 临时创建一个图像用于绘制按钮
-	btnImage := ebiten.NewImage(renderWidth, renderHeight)
+	// 绘制子控件
+	b.BaseWidget.DrawChildren(screen, absX, absY, renderWidth, renderHeight)
+}
 
-	// 绘制背景
-	if bgColor.A > 0 {
-		btnImage.Fill(bgColor.ToColor())
-	}
+// Paint 记录按钮的阴影、背景、边框和文本（内容裁剪到按钮边界，透明度整体应用）
+func (b *ButtonWidget) Paint(list *DrawList, bounds image.Rectangle) {
+	b.paintOuterShadows(list, bounds, b.Opacity)
+	pushGroup(list, bounds, b.Opacity)
 
-	if bgImage != nil {
-		op := &ebiten.DrawImageOptions{}
-		srcW, srcH := bgImage.Size()
-		scaleX := float64(renderWidth) / float64(srcW)
-		scaleY := float64(renderHeight) / float64(srcH)
-		op.GeoM.Scale(scaleX, scaleY)
-		btnImage.DrawImage(bgImage, op)
-	}
+	// 绘制背景
+	bgColor, bgImage := b.GetStateBackground()
+	b.paintFill(list, bounds, bgColor)
+	paintBackgroundImage(list, bgImage, b.BackgroundSliceFor(bgImage), bounds)
+	b.paintInnerShadows(list, bounds)
 
 	// 绘制边框
-	if b.BorderWidth > 0 {
-		// TODO: 实现边框绘制
-	}
+	b.paintBorder(list, bounds)
 
 	// 绘制文本
 	if b.Text != "" {
-		b.drawText(btnImage)
-	}
-
-	// 将按钮绘制到屏幕
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(absX), float64(absY))
-
-	// 应用透明度
-	if b.Opacity < 100 {
-		op.ColorScale.ScaleAlpha(float32(b.Opacity) / 100.0)
+		b.paintText(list, bounds)
 	}
 
-	screen.DrawImage(btnImage, op)
-
-	// 绘制子控件
-	b.BaseWidget.DrawChildren(screen, absX, absY, renderWidth, renderHeight)
+	popGroup(list, b.Opacity)
 }
 
-// drawText 绘制文本
-func (b *ButtonWidget) drawText(dst *ebiten.Image) {
+// paintText 记录文本
+func (b *ButtonWidget) paintText(list *DrawList, rect image.Rectangle) {
 	if b.Font == nil {
 		b.Font = basicfont.Face7x13
 	}
 
-	textColor := color.RGBA{
+	textColor := RGBA{
 		R: b.TextColor.R,
 		G: b.TextColor.G,
 		B: b.TextColor.B,
 		A: b.TextColorAlpha,
 	}
 
-	// 计算文本位置
+	// 计算文本位置（在实际绘制尺寸的内容区域内对齐）
 	bounds := text.BoundString(b.Font, b.Text)
 	textWidth := bounds.Dx()
+	content := b.ContentRect(rect)
 
 	var textX, textY int
 
 	// 水平对齐
 	switch b.TextAlignment {
 	case "left":
-		textX = b.BaseWidget.Padding.Left
+		textX = content.Min.X
 	case "right":
-		textX = b.BaseWidget.Width - textWidth - b.BaseWidget.Padding.Right
+		textX = content.Max.X - textWidth
 	default: // center
-		textX = (b.BaseWidget.Width - textWidth) / 2
+		textX = content.Min.X + (content.Dx()-textWidth)/2
 	}
 
 	// 垂直居中 - 修正基线偏移
 	// bounds.Min.Y 通常是负值（基线之上），bounds.Max.Y 是正值（基线之下）
 	// 真正的文本高度应该用 bounds.Max.Y - bounds.Min.Y
-	textY = b.BaseWidget.Height/2 - bounds.Min.Y - (bounds.Max.Y-bounds.Min.Y)/2
+	textY = content.Min.Y + content.Dy()/2 - bounds.Min.Y - (bounds.Max.Y-bounds.Min.Y)/2
+
+	list.DrawText(b.Text, b.Font, textX, textY, textColor)
+}
 
-	text.Draw(dst, b.Text, b.Font, textX, textY, textColor)
+// ContentSize 计算文本内容加内边距的尺寸（用于布局的内容尺寸）
+func (b *ButtonWidget) ContentSize() (int, int) {
+	if b.Font == nil {
+		b.Font = basicfont.Face7x13
+	}
+	return textContentSize(b.Font, b.Text, b.Padding)
 }
 
 // OnClick 处理点击事件
@@ -247,17 +219,38 @@ func (b *ButtonWidget) OnClick(x, y int) bool {
 	return false
 }
 
+// InteractionState 按交互状态推导的按钮状态
+func (b *ButtonWidget) InteractionState() ButtonState {
+	switch {
+	case !b.Enabled:
+		return ButtonStateDisabled
+	case b.state.Has(WidgetStatePressed):
+		return ButtonStatePressed
+	}
+	return ButtonStateNormal
+}
+
+// Update 更新子控件
+//
+// Deprecated: 按下状态改由InputDispatcher维护，Update不再读取鼠标；保留该方法用于兼容
+func (b *ButtonWidget) Update() error {
+	return b.BaseWidget.Update()
+}
+
 // SetEnabled 设置启用状态
 func (b *ButtonWidget) SetEnabled(enabled bool) {
+	b.MarkDirty()
 	b.Enabled = enabled
-	if !enabled {
-		b.CurrentState = ButtonStateDisabled
-	} else {
-		b.CurrentState = ButtonStateNormal
-	}
+	b.syncLegacyState()
+}
+
+// syncLegacyState 按交互状态同步CurrentState
+func (b *ButtonWidget) syncLegacyState() {
+	b.CurrentState = b.InteractionState()
 }
 
 // SetText 设置文本
 func (b *ButtonWidget) SetText(text string) {
+	b.MarkDirty()
 	b.Text = text
 }
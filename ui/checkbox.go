@@ -2,10 +2,8 @@ package ui
 
 import (
 	"image"
-	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text"
 	"golang.org/x/image/font/basicfont"
 )
 
@@ -84,29 +82,36 @@ func (c *CheckBoxWidget) Draw(screen *ebiten.Image, parentX, parentY, parentWidt
 	y := parentY + localY
 
 	// 计算响应式尺寸
-	_, height := c.CalculateSize(parentWidth, parentHeight, localX, localY)
+	width, height := c.CalculateSize(parentWidth, parentHeight, localX, localY)
+	drawPainted(screen, c, image.Rect(x, y, x+width, y+height))
+}
+
+// Paint 记录复选框、对勾和文本标签（在内容区域内布局）
+func (c *CheckBoxWidget) Paint(list *DrawList, bounds image.Rectangle) {
+	content := c.ContentRect(bounds)
+	x, y, height := content.Min.X, content.Min.Y, content.Dy()
 
 	// 绘制复选框
 	boxY := y + (height-c.BoxSize)/2
-	c.drawBox(screen, x, boxY, c.BoxSize)
+	c.paintBox(list, x, boxY, c.BoxSize)
 
 	// 如果选中，绘制对勾
 	if c.Checked {
-		c.drawCheckMark(screen, x, boxY, c.BoxSize)
+		c.paintCheckMark(list, x, boxY, c.BoxSize)
 	}
 
 	// 绘制文本标签
 	if c.Text != "" {
 		textX := x + c.BoxSize + 8
 		textY := y + height/2
-		c.drawText(screen, textX, textY)
+		c.paintText(list, textX, textY)
 	}
 }
 
-// drawBox 绘制复选框
-func (c *CheckBoxWidget) drawBox(screen *ebiten.Image, x, y, size int) {
+// paintBox 记录复选框的背景和边框
+func (c *CheckBoxWidget) paintBox(list *DrawList, x, y, size int) {
 	// 确定背景色
-	bgColor := color.RGBA{
+	bgColor := RGBA{
 		R: c.BoxBgColor.R,
 		G: c.BoxBgColor.G,
 		B: c.BoxBgColor.B,
@@ -114,7 +119,7 @@ func (c *CheckBoxWidget) drawBox(screen *ebiten.Image, x, y, size int) {
 	}
 
 	if c.Checked {
-		bgColor = color.RGBA{
+		bgColor = RGBA{
 			R: c.CheckedBgColor.R,
 			G: c.CheckedBgColor.G,
 			B: c.CheckedBgColor.B,
@@ -128,15 +133,11 @@ func (c *CheckBoxWidget) drawBox(screen *ebiten.Image, x, y, size int) {
 
 	// 绘制背景
 	bounds := image.Rect(x, y, x+size, y+size)
-	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
-		for px := bounds.Min.X; px < bounds.Max.X; px++ {
-			screen.Set(px, py, bgColor)
-		}
-	}
+	list.FillRect(bounds, bgColor)
 
 	// 绘制边框
 	if c.BoxBorderWidth > 0 {
-		borderColor := color.RGBA{
+		borderColor := RGBA{
 			R: c.BoxBorderColor.R,
 			G: c.BoxBorderColor.G,
 			B: c.BoxBorderColor.B,
@@ -147,30 +148,13 @@ func (c *CheckBoxWidget) drawBox(screen *ebiten.Image, x, y, size int) {
 			borderColor.A = 128
 		}
 
-		for i := 0; i < c.BoxBorderWidth; i++ {
-			// 上边
-			for px := x; px < x+size; px++ {
-				screen.Set(px, y+i, borderColor)
-			}
-			// 下边
-			for px := x; px < x+size; px++ {
-				screen.Set(px, y+size-1-i, borderColor)
-			}
-			// 左边
-			for py := y; py < y+size; py++ {
-				screen.Set(x+i, py, borderColor)
-			}
-			// 右边
-			for py := y; py < y+size; py++ {
-				screen.Set(x+size-1-i, py, borderColor)
-			}
-		}
+		list.StrokeRect(bounds, c.BoxBorderWidth, borderColor)
 	}
 }
 
-// drawCheckMark 绘制对勾
-func (c *CheckBoxWidget) drawCheckMark(screen *ebiten.Image, x, y, size int) {
-	checkColor := color.RGBA{
+// paintCheckMark 记录对勾
+func (c *CheckBoxWidget) paintCheckMark(list *DrawList, x, y, size int) {
+	checkColor := RGBA{
 		R: c.CheckMarkColor.R,
 		G: c.CheckMarkColor.G,
 		B: c.CheckMarkColor.B,
@@ -181,30 +165,29 @@ func (c *CheckBoxWidget) drawCheckMark(screen *ebiten.Image, x, y, size int) {
 		checkColor.A = 128
 	}
 
-	// 绘制对勾（简化版：两条线段）
-	// 第一段：从左下到中间
+	// 绘制对勾（简化版：两条线段），每一列只记录一个矩形，半透明时重叠处不会重复叠加
 	centerX := x + size/2
 	centerY := y + size/2
+	bar := size / 3
 
-	// 短竖线（左侧）
-	for i := 0; i < 3; i++ {
-		for j := 0; j < size/3; j++ {
-			screen.Set(centerX-2+i, centerY+j, checkColor)
-		}
+	// 短竖线（左侧，宽3像素），最右一列与长斜线的第一列重叠
+	joint := bar
+	if size/2 > 0 {
+		joint = max(bar, 3)
 	}
+	list.FillRect(image.Rect(centerX-2, centerY, centerX, centerY+bar), checkColor)
+	list.FillRect(image.Rect(centerX, centerY, centerX+1, centerY+joint), checkColor)
 
 	// 长斜线（右侧）
-	for i := 0; i < size/2; i++ {
-		for j := 0; j < 3; j++ {
-			screen.Set(centerX+i, centerY-i+j, checkColor)
-		}
+	for i := 1; i < size/2; i++ {
+		list.FillRect(image.Rect(centerX+i, centerY-i, centerX+i+1, centerY-i+3), checkColor)
 	}
 }
 
-// drawText 绘制文本标签
-func (c *CheckBoxWidget) drawText(screen *ebiten.Image, x, y int) {
+// paintText 记录文本标签
+func (c *CheckBoxWidget) paintText(list *DrawList, x, y int) {
 	face := basicfont.Face7x13
-	textColor := color.RGBA{
+	textColor := RGBA{
 		R: c.TextColor.R,
 		G: c.TextColor.G,
 		B: c.TextColor.B,
@@ -215,18 +198,20 @@ func (c *CheckBoxWidget) drawText(screen *ebiten.Image, x, y int) {
 		textColor.A = 128
 	}
 
-	text.Draw(screen, c.Text, face, x, y+4, textColor)
+	list.DrawText(c.Text, face, x, y+4, textColor)
 }
 
 // Toggle 切换选中状态
 func (c *CheckBoxWidget) Toggle() {
 	if c.Enabled {
 		c.Checked = !c.Checked
+		c.MarkDirty()
 	}
 }
 
 // SetChecked 设置选中状态
 func (c *CheckBoxWidget) SetChecked(checked bool) {
+	c.MarkDirty()
 	c.Checked = checked
 }
 
@@ -2,10 +2,8 @@ package ui
 
 import (
 	"image"
-	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text"
 	"golang.org/x/image/font/basicfont"
 )
 
@@ -52,6 +50,9 @@ type ComboBoxWidget struct {
 	IsExpanded bool `json:"isExpanded"` // 是否展开
 	HoverIndex int  `json:"hoverIndex"` // 当前悬停项索引 (-1表示未悬停)
 	Enabled    bool `json:"enabled"`    // 是否启用
+
+	// 展开时推入弹出层的下拉列表（绘制在所有普通控件之上，点击外部关闭）
+	popup *Popup
 }
 
 // NewComboBox 创建下拉选择框
@@ -106,28 +107,179 @@ func (c *ComboBoxWidget) Draw(screen *ebiten.Image, parentX, parentY, parentWidt
 
 	// 计算响应式尺寸
 	width, height := c.CalculateSize(parentWidth, parentHeight, localX, localY)
+	drawPainted(screen, c, image.Rect(x, y, x+width, y+height))
+}
+
+// Paint 记录主框、当前文本和下拉箭头（下拉列表在弹出层中绘制）
+func (c *ComboBoxWidget) Paint(list *DrawList, bounds image.Rectangle) {
+	x, y, width, height := bounds.Min.X, bounds.Min.Y, bounds.Dx(), bounds.Dy()
 
 	// 绘制主框背景
-	c.drawMainBox(screen, x, y, width, height)
+	c.paintMainBox(list, x, y, width, height)
 
 	// 绘制边框
-	c.drawBorder(screen, x, y, width, height)
+	c.paintBorder(list, x, y, width, height)
 
-	// 绘制当前选中文本或占位符
-	c.drawCurrentText(screen, x, y, width, height)
+	// 绘制当前选中文本或占位符（在内容区域内）
+	content := c.ContentRect(bounds)
+	c.paintCurrentText(list, content.Min.X, content.Min.Y, content.Dx(), content.Dy())
 
 	// 绘制下拉箭头
-	c.drawArrow(screen, x, y, width, height)
+	c.paintArrow(list, x, y, width, height)
+}
+
+// visibleItemCount 下拉列表中显示的选项数量
+func (c *ComboBoxWidget) visibleItemCount() int {
+	return min(len(c.Items), c.MaxVisibleItems)
+}
+
+// dropdownRect 计算下拉列表的位置：默认在主框下方，视口下方放不下而上方放得下时翻转到主框上方
+func (c *ComboBoxWidget) dropdownRect(anchor, viewport image.Rectangle) image.Rectangle {
+	height := c.visibleItemCount() * c.ItemHeight
+	rect := image.Rect(anchor.Min.X, anchor.Max.Y, anchor.Max.X, anchor.Max.Y+height)
+	if !viewport.Empty() && rect.Max.Y > viewport.Max.Y && anchor.Min.Y-height >= viewport.Min.Y {
+		rect = image.Rect(anchor.Min.X, anchor.Min.Y-height, anchor.Max.X, anchor.Min.Y)
+	}
+	return rect
+}
+
+// IsDropdownOpen 下拉列表是否在弹出层中打开
+func (c *ComboBoxWidget) IsDropdownOpen() bool {
+	return c.popup != nil
+}
+
+// openDropdown 在弹出层中打开下拉列表（位置根据布局过程缓存的主框边界计算）
+func (c *ComboBoxWidget) openDropdown(layers *LayerManager) {
+	anchor, ok := c.GetComputedLayout()
+	if !ok || len(c.Items) == 0 {
+		return
+	}
+	rect := c.dropdownRect(anchor.Bounds, layers.viewport)
+	dropdown := &comboDropdown{
+		BaseWidget: BaseWidget{
+			ID:          c.ID + ".dropdown",
+			Type:        TypeComboBox,
+			X:           rect.Min.X,
+			Y:           rect.Min.Y,
+			Width:       rect.Dx(),
+			Height:      rect.Dy(),
+			Visible:     true,
+			Interactive: true,
+			Opacity:     100,
+		},
+		combo: c,
+	}
 
-	// 如果展开，绘制下拉列表
-	if c.IsExpanded && len(c.Items) > 0 {
-		c.drawDropdown(screen, x, y, width, height)
+	popup := NewPopup(dropdown, LayerPopup)
+	popup.Owner = c
+	popup.OnDismiss = func() {
+		c.IsExpanded = false
+		c.HoverIndex = -1
+		c.popup = nil
+		c.MarkDirty()
 	}
+	c.popup = popup
+	c.IsExpanded = true
+	c.MarkDirty()
+	layers.Push(popup)
 }
 
-// drawMainBox 绘制主框背景
-func (c *ComboBoxWidget) drawMainBox(screen *ebiten.Image, x, y, width, height int) {
-	bgColor := color.RGBA{
+// syncPopup 展开状态不是通过点击改变时（加载时或由脚本设置）打开或关闭弹出层中的下拉列表
+func (c *ComboBoxWidget) syncPopup(layers *LayerManager) {
+	switch {
+	case c.IsExpanded && c.popup == nil:
+		c.openDropdown(layers)
+	case !c.IsExpanded && c.popup != nil:
+		layers.Dismiss(c.popup)
+	}
+}
+
+// reactPointer 点击主框时展开或收起下拉列表
+func (c *ComboBoxWidget) reactPointer(d *InputDispatcher, event WidgetEvent) {
+	if event.Type != EventClick || event.Button != 0 || !c.Enabled {
+		return
+	}
+	if c.popup != nil {
+		d.layers.Dismiss(c.popup)
+		return
+	}
+	c.openDropdown(d.layers)
+}
+
+// comboDropdown 下拉选择框在弹出层中的下拉列表
+type comboDropdown struct {
+	BaseWidget
+	combo *ComboBoxWidget
+}
+
+// Draw 绘制下拉列表
+func (dd *comboDropdown) Draw(screen *ebiten.Image, parentX, parentY, parentWidth, parentHeight int) {
+	if !dd.Visible {
+		return
+	}
+	localX, localY := dd.CalculatePosition(parentWidth, parentHeight)
+	width, height := dd.CalculateSize(parentWidth, parentHeight, localX, localY)
+	x, y := parentX+localX, parentY+localY
+	drawPainted(screen, dd, image.Rect(x, y, x+width, y+height))
+}
+
+// Paint 记录下拉列表
+func (dd *comboDropdown) Paint(list *DrawList, bounds image.Rectangle) {
+	dd.combo.paintDropdown(list, bounds)
+}
+
+// itemAt 获取坐标所在的选项索引（不在选项上时返回-1）
+func (dd *comboDropdown) itemAt(y int) int {
+	box, ok := dd.GetComputedLayout()
+	if !ok || dd.combo.ItemHeight <= 0 || y < box.Bounds.Min.Y {
+		return -1
+	}
+	index := (y - box.Bounds.Min.Y) / dd.combo.ItemHeight
+	if index >= dd.combo.visibleItemCount() {
+		return -1
+	}
+	return index
+}
+
+// reactPointer 悬停时高亮选项，点击时选中选项并关闭下拉列表
+// 选中项改变时向下拉选择框发送change事件（附带selectedIndex和value）
+func (dd *comboDropdown) reactPointer(d *InputDispatcher, event WidgetEvent) {
+	c := dd.combo
+	switch event.Type {
+	case EventMouseEnter, EventHover:
+		c.HoverIndex = dd.itemAt(event.Y)
+	case EventMouseLeave:
+		c.HoverIndex = -1
+	case EventClick:
+		index := dd.itemAt(event.Y)
+		if event.Button != 0 || index < 0 {
+			return
+		}
+		changed := index != c.SelectedIndex
+		c.SelectedIndex = index
+		c.MarkDirty()
+		if c.popup != nil {
+			d.layers.Dismiss(c.popup)
+		}
+		if changed {
+			d.push(WidgetEvent{
+				Type:     EventChange,
+				WidgetID: c.GetID(),
+				Widget:   c,
+				X:        event.X,
+				Y:        event.Y,
+				Data: map[string]interface{}{
+					"selectedIndex": index,
+					"value":         c.Items[index],
+				},
+			})
+		}
+	}
+}
+
+// paintMainBox 记录主框背景
+func (c *ComboBoxWidget) paintMainBox(list *DrawList, x, y, width, height int) {
+	bgColor := RGBA{
 		R: c.BackgroundColor.R,
 		G: c.BackgroundColor.G,
 		B: c.BackgroundColor.B,
@@ -139,21 +291,16 @@ func (c *ComboBoxWidget) drawMainBox(screen *ebiten.Image, x, y, width, height i
 		bgColor.A = 128
 	}
 
-	bounds := image.Rect(x, y, x+width, y+height)
-	for py := bounds.Min.Y; py < bounds.Min.Y+height && py < bounds.Max.Y; py++ {
-		for px := bounds.Min.X; px < bounds.Min.X+width && px < bounds.Max.X; px++ {
-			screen.Set(px, py, bgColor)
-		}
-	}
+	list.FillRect(image.Rect(x, y, x+width, y+height), bgColor)
 }
 
-// drawBorder 绘制边框
-func (c *ComboBoxWidget) drawBorder(screen *ebiten.Image, x, y, width, height int) {
+// paintBorder 记录边框
+func (c *ComboBoxWidget) paintBorder(list *DrawList, x, y, width, height int) {
 	if c.BorderWidth <= 0 {
 		return
 	}
 
-	borderColor := color.RGBA{
+	borderColor := RGBA{
 		R: c.BorderColor.R,
 		G: c.BorderColor.G,
 		B: c.BorderColor.B,
@@ -161,30 +308,13 @@ func (c *ComboBoxWidget) drawBorder(screen *ebiten.Image, x, y, width, height in
 	}
 
 	// 绘制四条边
-	for i := 0; i < c.BorderWidth; i++ {
-		// 上边
-		for px := x; px < x+width; px++ {
-			screen.Set(px, y+i, borderColor)
-		}
-		// 下边
-		for px := x; px < x+width; px++ {
-			screen.Set(px, y+height-1-i, borderColor)
-		}
-		// 左边
-		for py := y; py < y+height; py++ {
-			screen.Set(x+i, py, borderColor)
-		}
-		// 右边
-		for py := y; py < y+height; py++ {
-			screen.Set(x+width-1-i, py, borderColor)
-		}
-	}
+	list.StrokeRect(image.Rect(x, y, x+width, y+height), c.BorderWidth, borderColor)
 }
 
-// drawCurrentText 绘制当前选中文本或占位符
-func (c *ComboBoxWidget) drawCurrentText(screen *ebiten.Image, x, y, width, height int) {
+// paintCurrentText 记录当前选中文本或占位符
+func (c *ComboBoxWidget) paintCurrentText(list *DrawList, x, y, width, height int) {
 	var displayText string
-	textColor := color.RGBA{
+	textColor := RGBA{
 		R: c.TextColor.R,
 		G: c.TextColor.G,
 		B: c.TextColor.B,
@@ -208,12 +338,12 @@ func (c *ComboBoxWidget) drawCurrentText(screen *ebiten.Image, x, y, width, heig
 	textX := x + 8
 	textY := y + height/2 + 4
 
-	text.Draw(screen, displayText, face, textX, textY, textColor)
+	list.DrawText(displayText, face, textX, textY, textColor)
 }
 
-// drawArrow 绘制下拉箭头
-func (c *ComboBoxWidget) drawArrow(screen *ebiten.Image, x, y, width, height int) {
-	arrowColor := color.RGBA{
+// paintArrow 记录下拉箭头
+func (c *ComboBoxWidget) paintArrow(list *DrawList, x, y, width, height int) {
+	arrowColor := RGBA{
 		R: c.ArrowColor.R,
 		G: c.ArrowColor.G,
 		B: c.ArrowColor.B,
@@ -232,61 +362,48 @@ func (c *ComboBoxWidget) drawArrow(screen *ebiten.Image, x, y, width, height int
 	if c.IsExpanded {
 		// 向上箭头
 		for i := 0; i < 4; i++ {
-			for j := -i; j <= i; j++ {
-				screen.Set(arrowX+j, arrowY-i, arrowColor)
-			}
+			list.FillRect(image.Rect(arrowX-i, arrowY-i, arrowX+i+1, arrowY-i+1), arrowColor)
 		}
 	} else {
 		// 向下箭头
 		for i := 0; i < 4; i++ {
-			for j := -i; j <= i; j++ {
-				screen.Set(arrowX+j, arrowY+i, arrowColor)
-			}
+			list.FillRect(image.Rect(arrowX-i, arrowY+i, arrowX+i+1, arrowY+i+1), arrowColor)
 		}
 	}
 }
 
-// drawDropdown 绘制下拉列表
-func (c *ComboBoxWidget) drawDropdown(screen *ebiten.Image, x, y, width, height int) {
-	// 下拉框在主框下方
-	dropdownY := y + height
-	visibleCount := min(len(c.Items), c.MaxVisibleItems)
+// paintDropdown 在指定区域记录下拉列表
+func (c *ComboBoxWidget) paintDropdown(list *DrawList, rect image.Rectangle) {
+	x, width := rect.Min.X, rect.Dx()
+	dropdownY := rect.Min.Y
+	visibleCount := c.visibleItemCount()
 	dropdownHeight := visibleCount * c.ItemHeight
 
 	// 绘制下拉框背景
-	dropdownBg := color.RGBA{
+	dropdownBg := RGBA{
 		R: c.DropdownBgColor.R,
 		G: c.DropdownBgColor.G,
 		B: c.DropdownBgColor.B,
 		A: c.DropdownBgColorAlpha,
 	}
 
-	bounds := image.Rect(x, dropdownY, x+width, dropdownY+dropdownHeight)
-	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
-		for px := bounds.Min.X; px < bounds.Max.X; px++ {
-			screen.Set(px, py, dropdownBg)
-		}
-	}
+	list.FillRect(image.Rect(x, dropdownY, x+width, dropdownY+dropdownHeight), dropdownBg)
 
 	// 绘制边框
-	borderColor := color.RGBA{
+	borderColor := RGBA{
 		R: c.BorderColor.R,
 		G: c.BorderColor.G,
 		B: c.BorderColor.B,
 		A: c.BorderColorAlpha,
 	}
-	// 左、右、下边框
-	for py := dropdownY; py < dropdownY+dropdownHeight; py++ {
-		screen.Set(x, py, borderColor)
-		screen.Set(x+width-1, py, borderColor)
-	}
-	for px := x; px < x+width; px++ {
-		screen.Set(px, dropdownY+dropdownHeight-1, borderColor)
-	}
+	// 左、右、下边框（互不重叠）
+	list.FillRect(image.Rect(x, dropdownY, x+1, dropdownY+dropdownHeight), borderColor)
+	list.FillRect(image.Rect(x+width-1, dropdownY, x+width, dropdownY+dropdownHeight), borderColor)
+	list.FillRect(image.Rect(x+1, dropdownY+dropdownHeight-1, x+width-1, dropdownY+dropdownHeight), borderColor)
 
 	// 绘制选项
 	face := basicfont.Face7x13
-	textColor := color.RGBA{
+	textColor := RGBA{
 		R: c.TextColor.R,
 		G: c.TextColor.G,
 		B: c.TextColor.B,
@@ -298,69 +415,52 @@ func (c *ComboBoxWidget) drawDropdown(screen *ebiten.Image, x, y, width, height
 
 		// 绘制选中项或悬停项背景
 		if i == c.SelectedIndex {
-			selectedBg := color.RGBA{
+			selectedBg := RGBA{
 				R: c.SelectedBgColor.R,
 				G: c.SelectedBgColor.G,
 				B: c.SelectedBgColor.B,
 				A: c.SelectedBgColorAlpha,
 			}
-			for py := itemY; py < itemY+c.ItemHeight; py++ {
-				for px := x; px < x+width; px++ {
-					screen.Set(px, py, selectedBg)
-				}
-			}
+			list.FillRect(image.Rect(x, itemY, x+width, itemY+c.ItemHeight), selectedBg)
 		} else if i == c.HoverIndex {
-			hoverBg := color.RGBA{
+			hoverBg := RGBA{
 				R: c.HoverBgColor.R,
 				G: c.HoverBgColor.G,
 				B: c.HoverBgColor.B,
 				A: c.HoverBgColorAlpha,
 			}
-			for py := itemY; py < itemY+c.ItemHeight; py++ {
-				for px := x; px < x+width; px++ {
-					screen.Set(px, py, hoverBg)
-				}
-			}
+			list.FillRect(image.Rect(x, itemY, x+width, itemY+c.ItemHeight), hoverBg)
 		}
 
 		// 绘制项文本
 		itemText := c.Items[i]
 		textX := x + 8
 		textY := itemY + c.ItemHeight/2 + 4
-		text.Draw(screen, itemText, face, textX, textY, textColor)
+		list.DrawText(itemText, face, textX, textY, textColor)
 
 		// 绘制分隔线
 		if i < visibleCount-1 {
-			for px := x; px < x+width; px++ {
-				screen.Set(px, itemY+c.ItemHeight-1, borderColor)
-			}
+			list.FillRect(image.Rect(x, itemY+c.ItemHeight-1, x+width, itemY+c.ItemHeight), borderColor)
 		}
 	}
 }
 
-// drawPlaceholder 绘制占位符效果（用于编辑器预览）
-func (c *ComboBoxWidget) drawPlaceholder(screen *ebiten.Image, x, y, width, height int) {
+// paintPlaceholder 记录占位符效果（用于编辑器预览）
+func (c *ComboBoxWidget) paintPlaceholder(list *DrawList, x, y, width, height int) {
 	// 绘制主框
-	c.drawMainBox(screen, x, y, width, height)
-	c.drawBorder(screen, x, y, width, height)
+	c.paintMainBox(list, x, y, width, height)
+	c.paintBorder(list, x, y, width, height)
 
 	// 显示示例文本
 	face := basicfont.Face7x13
-	placeholderColor := color.RGBA{R: 150, G: 150, B: 150, A: 255}
+	placeholderColor := RGBA{R: 150, G: 150, B: 150, A: 255}
 
 	if len(c.Items) > 0 && c.SelectedIndex >= 0 && c.SelectedIndex < len(c.Items) {
-		text.Draw(screen, c.Items[c.SelectedIndex], face, x+8, y+height/2+4, placeholderColor)
+		list.DrawText(c.Items[c.SelectedIndex], face, x+8, y+height/2+4, placeholderColor)
 	} else {
-		text.Draw(screen, c.PlaceholderText, face, x+8, y+height/2+4, placeholderColor)
+		list.DrawText(c.PlaceholderText, face, x+8, y+height/2+4, placeholderColor)
 	}
 
 	// 绘制箭头
-	c.drawArrow(screen, x, y, width, height)
-}
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
+	c.paintArrow(list, x, y, width, height)
 }
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// CursorController 鼠标指针形状控制接口
+// 分发器通过该接口设置指针形状，测试时可以注入记录调用的实现
+type CursorController interface {
+	SetCursorShape(shape ebiten.CursorShapeType)
+}
+
+// EbitenCursorController 基于ebiten的指针形状控制
+type EbitenCursorController struct{}
+
+func (EbitenCursorController) SetCursorShape(shape ebiten.CursorShapeType) {
+	ebiten.SetCursorShape(shape)
+}
+
+// cursorShapes cursor属性值到指针形状的映射（与CSS cursor取值一致）
+var cursorShapes = map[string]ebiten.CursorShapeType{
+	"default":     ebiten.CursorShapeDefault,
+	"text":        ebiten.CursorShapeText,
+	"crosshair":   ebiten.CursorShapeCrosshair,
+	"pointer":     ebiten.CursorShapePointer,
+	"ew-resize":   ebiten.CursorShapeEWResize,
+	"ns-resize":   ebiten.CursorShapeNSResize,
+	"nesw-resize": ebiten.CursorShapeNESWResize,
+	"nwse-resize": ebiten.CursorShapeNWSEResize,
+	"move":        ebiten.CursorShapeMove,
+	"not-allowed": ebiten.CursorShapeNotAllowed,
+}
+
+// ParseCursorShape 将cursor属性值转换为指针形状
+func ParseCursorShape(name string) (ebiten.CursorShapeType, bool) {
+	shape, ok := cursorShapes[name]
+	return shape, ok
+}
+
+// WidgetCursorShape 获取控件的指针形状
+// 优先使用cursor属性；未设置时按钮类控件使用手形，文本输入框使用I形，其他使用默认指针
+func WidgetCursorShape(widget Widget) ebiten.CursorShapeType {
+	if widget == nil {
+		return ebiten.CursorShapeDefault
+	}
+	if c, ok := widget.(interface{ GetCursor() string }); ok {
+		if shape, ok := ParseCursorShape(c.GetCursor()); ok {
+			return shape
+		}
+	}
+	if !isWidgetEnabled(widget) {
+		return ebiten.CursorShapeDefault
+	}
+
+	switch widget.(type) {
+	case *ButtonWidget, *CheckBoxWidget, *RadioButtonWidget, *ComboBoxWidget, *comboDropdown, *SliderWidget:
+		return ebiten.CursorShapePointer
+	case *TextInputWidget:
+		return ebiten.CursorShapeText
+	}
+	return ebiten.CursorShapeDefault
+}
+
+// SetCursorController 设置指针形状控制（nil表示不管理指针形状）
+func (d *InputDispatcher) SetCursorController(controller CursorController) {
+	d.cursorController = controller
+	d.cursorSet = false
+}
+
+// updateCursor 根据最上层悬停控件设置指针形状（形状变化时才调用控制接口）
+func (d *InputDispatcher) updateCursor(target Widget) {
+	if d.cursorController == nil {
+		return
+	}
+	shape := WidgetCursorShape(target)
+	if d.drag.active {
+		shape = ebiten.CursorShapeMove
+	}
+	if d.cursorSet && shape == d.cursorShape {
+		return
+	}
+	d.cursorShape = shape
+	d.cursorSet = true
+	d.cursorController.SetCursorShape(shape)
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// mockCursorController 记录设置的指针形状
+type mockCursorController struct {
+	shapes []ebiten.CursorShapeType
+}
+
+func (m *mockCursorController) SetCursorShape(shape ebiten.CursorShapeType) {
+	m.shapes = append(m.shapes, shape)
+}
+
+func (m *mockCursorController) last() ebiten.CursorShapeType {
+	if len(m.shapes) == 0 {
+		return -1
+	}
+	return m.shapes[len(m.shapes)-1]
+}
+
+// TestCursor_DefaultShapes 测试按控件类型选择默认指针形状
+func TestCursor_DefaultShapes(t *testing.T) {
+	btn := NewButton("btn")
+	btn.X, btn.Y = 10, 10
+	input := NewTextInput("input")
+	input.X, input.Y = 200, 10
+
+	src := newMockInputSource()
+	d := NewInputDispatcher(src, nil)
+	d.SetRoots([]Widget{btn, input})
+	cursor := &mockCursorController{}
+	d.SetCursorController(cursor)
+
+	tests := []struct {
+		x, y int
+		want ebiten.CursorShapeType
+	}{
+		{20, 20, ebiten.CursorShapePointer},
+		{210, 20, ebiten.CursorShapeText},
+		{600, 600, ebiten.CursorShapeDefault},
+	}
+	for _, tt := range tests {
+		src.x, src.y = tt.x, tt.y
+		d.Update()
+		if got := cursor.last(); got != tt.want {
+			t.Errorf("At (%d,%d): expected cursor %v, got %v", tt.x, tt.y, tt.want, got)
+		}
+	}
+}
+
+// TestCursor_PropertyOverride 测试cursor属性覆盖默认形状，禁用控件使用默认指针
+func TestCursor_PropertyOverride(t *testing.T) {
+	btn := NewButton("btn")
+	btn.X, btn.Y = 10, 10
+	btn.Cursor = "ew-resize"
+
+	src := newMockInputSource()
+	d := NewInputDispatcher(src, nil)
+	d.SetRoots([]Widget{btn})
+	cursor := &mockCursorController{}
+	d.SetCursorController(cursor)
+
+	src.x, src.y = 20, 20
+	d.Update()
+	if got := cursor.last(); got != ebiten.CursorShapeEWResize {
+		t.Errorf("Expected ew-resize cursor, got %v", got)
+	}
+
+	btn.Cursor = ""
+	btn.Enabled = false
+	d.Update()
+	if got := cursor.last(); got != ebiten.CursorShapeDefault {
+		t.Errorf("Expected default cursor on disabled button, got %v", got)
+	}
+}
+
+// TestCursor_OnlyOnChange 测试形状不变时不重复设置
+func TestCursor_OnlyOnChange(t *testing.T) {
+	d, src, eq := newDispatcherFixture()
+	defer eq.Close()
+	cursor := &mockCursorController{}
+	d.SetCursorController(cursor)
+
+	src.x, src.y = 20, 20
+	d.Update()
+	src.x, src.y = 25, 22
+	d.Update()
+	src.x, src.y = 210, 20
+	d.Update()
+
+	if len(cursor.shapes) != 1 {
+		t.Errorf("Expected a single SetCursorShape call, got %v", cursor.shapes)
+	}
+}
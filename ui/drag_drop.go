@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// dragThreshold 按下后移动超过该距离（像素）才开始拖拽
+const dragThreshold = 4
+
+// DragPayload 拖拽携带的数据
+type DragPayload struct {
+	Type   string                 // 数据类型（与dropTarget的acceptTypes匹配）
+	Source Widget                 // 拖拽源控件
+	Data   map[string]interface{} // 附加数据（例如GridView的index和item）
+
+	// 拖拽影像在源控件中的区域（绝对坐标），用于绘制拖拽影像
+	Bounds image.Rectangle
+}
+
+// toMap 转换为事件附加数据
+func (p *DragPayload) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"type":     p.Type,
+		"sourceId": p.Source.GetID(),
+		"data":     p.Data,
+	}
+}
+
+// dragSource 自定义拖拽数据的控件（例如GridView按单元格拖拽）
+// 返回false表示该位置不能开始拖拽
+type dragSource interface {
+	BeginDrag(x, y int, bounds image.Rectangle) (*DragPayload, bool)
+}
+
+// dropReceiver 自定义放置行为的控件（例如GridView交换/移动项）
+type dropReceiver interface {
+	AcceptDrop(payload *DragPayload, x, y int, bounds image.Rectangle) map[string]interface{}
+}
+
+// dragState 拖拽状态
+type dragState struct {
+	pending        bool // 已在可拖拽控件上按下，等待越过阈值
+	active         bool
+	startX, startY int
+	source         Widget
+	payload        *DragPayload
+	over           Widget // 当前悬停的放置目标
+	offsetX        int    // 光标相对拖拽影像左上角的偏移
+	offsetY        int
+	x, y           int
+	ghost          *ebiten.Image // 拖拽影像（首次绘制覆盖层时截取）
+}
+
+// IsDragging 是否正在拖拽
+func (d *InputDispatcher) IsDragging() bool {
+	return d.drag.active
+}
+
+// beginDragCandidate 左键按下时记录可能的拖拽源
+func (d *InputDispatcher) beginDragCandidate(target Widget, x, y int) {
+	d.drag = dragState{}
+	source := findDraggable(target)
+	if source == nil {
+		return
+	}
+	d.drag = dragState{pending: true, source: source, startX: x, startY: y}
+}
+
+// updateDrag 处理拖拽开始和拖拽移动
+func (d *InputDispatcher) updateDrag(x, y int, mods Modifiers) {
+	if !d.buttonDown[0] {
+		return
+	}
+
+	if d.drag.pending {
+		dx, dy := x-d.drag.startX, y-d.drag.startY
+		if dx*dx+dy*dy < dragThreshold*dragThreshold {
+			return
+		}
+		d.startDrag(mods)
+		if !d.drag.active {
+			return
+		}
+	}
+
+	if !d.drag.active || (x == d.drag.x && y == d.drag.y) {
+		return
+	}
+	d.drag.x, d.drag.y = x, y
+
+	target := d.dropTargetAt(x, y, d.drag.payload)
+	d.drag.over = target
+	if target != nil {
+		d.pushDrag(EventDragOver, target, d.drag.payload, x, y, mods, nil)
+	}
+}
+
+// startDrag 越过阈值后真正开始拖拽
+func (d *InputDispatcher) startDrag(mods Modifiers) {
+	source := d.drag.source
+	d.drag.pending = false
+
+	bounds := d.widgetAbsBounds(source)
+	var payload *DragPayload
+	if s, ok := source.(dragSource); ok {
+		p, ok := s.BeginDrag(d.drag.startX, d.drag.startY, bounds)
+		if !ok {
+			d.drag = dragState{}
+			return
+		}
+		payload = p
+	} else {
+		payload = &DragPayload{Bounds: bounds}
+		if t, ok := source.(interface{ GetDragType() string }); ok {
+			payload.Type = t.GetDragType()
+		}
+	}
+	payload.Source = source
+	if payload.Data == nil {
+		payload.Data = make(map[string]interface{})
+	}
+
+	d.drag.active = true
+	d.drag.payload = payload
+	d.drag.offsetX = d.drag.startX - payload.Bounds.Min.X
+	d.drag.offsetY = d.drag.startY - payload.Bounds.Min.Y
+	d.drag.x, d.drag.y = d.drag.startX, d.drag.startY
+
+	// 拖拽开始后不再产生click
+	d.pressTarget[0] = nil
+
+	d.pushDrag(EventDragStart, source, payload, d.drag.startX, d.drag.startY, mods, nil)
+}
+
+// finishDrag 左键抬起时结束拖拽，返回是否处于拖拽中
+func (d *InputDispatcher) finishDrag(x, y int, mods Modifiers) bool {
+	state := d.drag
+	d.drag = dragState{}
+	if !state.active {
+		return false
+	}
+	if state.ghost != nil {
+		state.ghost.Dispose()
+	}
+
+	dropped := false
+	if target := d.dropTargetAt(x, y, state.payload); target != nil {
+		var extra map[string]interface{}
+		if r, ok := target.(dropReceiver); ok {
+			extra = r.AcceptDrop(state.payload, x, y, d.widgetAbsBounds(target))
+		}
+		d.pushDrag(EventDrop, target, state.payload, x, y, mods, extra)
+		dropped = true
+	}
+
+	d.pushDrag(EventDragEnd, state.source, state.payload, x, y, mods, map[string]interface{}{
+		"dropped": dropped,
+	})
+	return true
+}
+
+// dropTargetAt 查找指定坐标下接受该数据类型的最上层放置目标
+func (d *InputDispatcher) dropTargetAt(x, y int, payload *DragPayload) Widget {
+	// 与命中测试相同的层叠顺序和裁剪规则
+	viewport := image.Rect(0, 0, d.viewportWidth, d.viewportHeight)
+	return buildStackOrder(d.roots, viewport, viewport).topmost(image.Pt(x, y), func(e stackEntry) bool {
+		return acceptsPayload(e.widget, payload)
+	})
+}
+
+// acceptsPayload 判断控件是否接受该拖拽数据
+func acceptsPayload(widget Widget, payload *DragPayload) bool {
+	t, ok := widget.(interface{ AcceptsDrop(payloadType string) bool })
+	return ok && t.AcceptsDrop(payload.Type)
+}
+
+// findDraggable 判断命中控件是否可拖拽
+func findDraggable(target Widget) Widget {
+	if target == nil {
+		return nil
+	}
+	if t, ok := target.(interface{ IsDraggable() bool }); ok && t.IsDraggable() {
+		return target
+	}
+	return nil
+}
+
+// widgetAbsBounds 计算控件当前的绝对边界
+func (d *InputDispatcher) widgetAbsBounds(widget Widget) image.Rectangle {
+	viewport := image.Rect(0, 0, d.viewportWidth, d.viewportHeight)
+	bounds := make(map[Widget]image.Rectangle)
+	collectWidgetBounds(d.roots, viewport, bounds)
+	return bounds[widget]
+}
+
+// pushDrag 推送拖拽事件（附带拖拽数据）
+func (d *InputDispatcher) pushDrag(eventType EventType, target Widget, payload *DragPayload, x, y int, mods Modifiers, extra map[string]interface{}) {
+	data := map[string]interface{}{
+		"payload": payload.toMap(),
+	}
+	for k, v := range extra {
+		data[k] = v
+	}
+	d.pushPointer(eventType, target, x, y, 0, mods, data)
+}
+
+// drawDragGhost 在光标处绘制半透明的拖拽影像
+func (d *InputDispatcher) drawDragGhost(screen *ebiten.Image) {
+	bounds := d.drag.payload.Bounds
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	x := float32(d.drag.x - d.drag.offsetX)
+	y := float32(d.drag.y - d.drag.offsetY)
+
+	// 拖拽源区域的截图
+	if src, ok := screen.SubImage(bounds).(*ebiten.Image); ok && d.drag.ghost == nil {
+		d.drag.ghost = ebiten.NewImage(w, h)
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(-bounds.Min.X), float64(-bounds.Min.Y))
+		d.drag.ghost.DrawImage(src, op)
+	}
+	if d.drag.ghost != nil {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(x), float64(y))
+		op.ColorScale.ScaleAlpha(0.6)
+		screen.DrawImage(d.drag.ghost, op)
+	}
+
+	// 可放置时绘制高亮边框
+	outline := color.RGBA{255, 255, 255, 160}
+	if d.drag.over != nil {
+		outline = color.RGBA{80, 200, 120, 220}
+	}
+	vector.StrokeRect(screen, x, y, float32(w), float32(h), 2, outline, false)
+}
@@ -0,0 +1,276 @@
+package ui
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// newDragFixture 创建一个可拖拽按钮和一个放置目标面板
+func newDragFixture() (*InputDispatcher, *mockInputSource, *EventQueue, *ButtonWidget, *PanelWidget) {
+	source := NewButton("source")
+	source.X, source.Y = 10, 10
+	source.Draggable = true
+	source.DragType = "item"
+
+	target := NewPanel("target")
+	target.X, target.Y = 200, 10
+	target.Width, target.Height = 100, 100
+	target.DropTarget = true
+
+	src := newMockInputSource()
+	eq := NewEventQueue()
+	d := NewInputDispatcher(src, eq)
+	d.SetRoots([]Widget{source, target})
+	return d, src, eq, source, target
+}
+
+// dragTo 按下、移动并在目标位置抬起
+func dragTo(d *InputDispatcher, src *mockInputSource, fromX, fromY, toX, toY int) {
+	src.x, src.y = fromX, fromY
+	d.Update()
+	src.buttons[ebiten.MouseButtonLeft] = true
+	d.Update()
+	src.x, src.y = (fromX+toX)/2, (fromY+toY)/2
+	d.Update()
+	src.x, src.y = toX, toY
+	d.Update()
+	src.buttons[ebiten.MouseButtonLeft] = false
+	d.Update()
+}
+
+// filterDragEvents 只保留拖放和点击事件
+func filterDragEvents(events []WidgetEvent) []WidgetEvent {
+	var filtered []WidgetEvent
+	for _, e := range events {
+		switch e.Type {
+		case EventDragStart, EventDragOver, EventDrop, EventDragEnd, EventClick:
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// TestDragDrop_EventSequence 测试拖放事件顺序，拖拽后不产生click
+func TestDragDrop_EventSequence(t *testing.T) {
+	d, src, eq, _, _ := newDragFixture()
+	defer eq.Close()
+
+	dragTo(d, src, 20, 20, 250, 50)
+
+	events := filterDragEvents(drainEvents(eq))
+	got := eventTypes(events, "")
+	want := []EventType{EventDragStart, EventDragOver, EventDrop, EventDragEnd}
+	if !equalEventTypes(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+
+	wantTargets := []string{"source", "target", "target", "source"}
+	for i, e := range events {
+		if e.WidgetID != wantTargets[i] {
+			t.Errorf("Event %s: expected target %s, got %s", e.Type, wantTargets[i], e.WidgetID)
+		}
+	}
+
+	payload, ok := events[2].Data["payload"].(map[string]interface{})
+	if !ok || payload["type"] != "item" || payload["sourceId"] != "source" {
+		t.Errorf("Unexpected drop payload: %v", events[2].Data["payload"])
+	}
+	if events[3].Data["dropped"] != true {
+		t.Errorf("Expected dragend dropped=true, got %v", events[3].Data["dropped"])
+	}
+	if d.IsDragging() {
+		t.Error("Expected drag to be finished")
+	}
+}
+
+// TestDragDrop_ClearsPressedState 测试拖拽结束后拖拽源不再处于按下状态
+func TestDragDrop_ClearsPressedState(t *testing.T) {
+	d, src, eq, source, _ := newDragFixture()
+	defer eq.Close()
+
+	dragTo(d, src, 20, 20, 250, 50)
+
+	if source.State().Has(WidgetStatePressed) {
+		t.Errorf("Expected source not to be pressed after drag, got %s", source.State())
+	}
+	got := stateEvents(drainEvents(eq), "source")
+	want := []string{"hover:on", "pressed:on", "focused:on", "hover:off", "pressed:off"}
+	if !equalStrings(got, want) {
+		t.Errorf("Expected state events %v, got %v", want, got)
+	}
+}
+
+// TestDragDrop_BelowThreshold 测试未越过阈值时仍然是普通点击
+func TestDragDrop_BelowThreshold(t *testing.T) {
+	d, src, eq, _, _ := newDragFixture()
+	defer eq.Close()
+
+	dragTo(d, src, 20, 20, 22, 21)
+
+	got := eventTypes(filterDragEvents(drainEvents(eq)), "")
+	want := []EventType{EventClick}
+	if !equalEventTypes(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestDragDrop_AcceptTypes 测试放置目标按acceptTypes过滤
+func TestDragDrop_AcceptTypes(t *testing.T) {
+	d, src, eq, _, target := newDragFixture()
+	defer eq.Close()
+
+	target.AcceptTypes = []string{"card"}
+	dragTo(d, src, 20, 20, 250, 50)
+
+	events := filterDragEvents(drainEvents(eq))
+	got := eventTypes(events, "")
+	want := []EventType{EventDragStart, EventDragEnd}
+	if !equalEventTypes(got, want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	if events[1].Data["dropped"] != false {
+		t.Errorf("Expected dragend dropped=false, got %v", events[1].Data["dropped"])
+	}
+
+	target.AcceptTypes = []string{"card", "item"}
+	dragTo(d, src, 20, 20, 250, 50)
+	got = eventTypes(filterDragEvents(drainEvents(eq)), "target")
+	want = []EventType{EventDragOver, EventDrop}
+	if !equalEventTypes(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// newDragGrid 创建2列的可拖放网格（单元格40x40，间距0）
+func newDragGrid(id string, x int, names ...string) *GridViewWidget {
+	g := NewGridView(id)
+	g.X, g.Y = x, 0
+	g.Width, g.Height = 80, 80
+	g.Columns = 2
+	g.ItemWidth, g.ItemHeight = 40, 40
+	g.Spacing = 0
+	g.Draggable = true
+	g.DropTarget = true
+	for _, name := range names {
+		g.Items = append(g.Items, map[string]interface{}{"name": name})
+	}
+	return g
+}
+
+func gridNames(g *GridViewWidget) []string {
+	names := make([]string, len(g.Items))
+	for i, item := range g.Items {
+		names[i], _ = item["name"].(string)
+	}
+	return names
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestDragDrop_GridSwap 测试同一网格内交换两个格子
+func TestDragDrop_GridSwap(t *testing.T) {
+	grid := newDragGrid("grid", 0, "a", "b", "c", "d")
+	src := newMockInputSource()
+	eq := NewEventQueue()
+	defer eq.Close()
+	d := NewInputDispatcher(src, eq)
+	d.SetRoots([]Widget{grid})
+
+	// 从格子0拖到格子3
+	dragTo(d, src, 20, 20, 60, 60)
+
+	if want := []string{"d", "b", "c", "a"}; !equalStrings(gridNames(grid), want) {
+		t.Errorf("Expected %v, got %v", want, gridNames(grid))
+	}
+
+	for _, e := range drainEvents(eq) {
+		if e.Type == EventDrop {
+			if e.Data["index"] != 0 || e.Data["targetIndex"] != 3 {
+				t.Errorf("Expected index 0 -> 3, got %v -> %v", e.Data["index"], e.Data["targetIndex"])
+			}
+		}
+	}
+}
+
+// TestDragDrop_GridMove 测试move模式下移动项并顺移其余项
+func TestDragDrop_GridMove(t *testing.T) {
+	grid := newDragGrid("grid", 0, "a", "b", "c", "d")
+	grid.DropMode = "move"
+	src := newMockInputSource()
+	eq := NewEventQueue()
+	defer eq.Close()
+	d := NewInputDispatcher(src, eq)
+	d.SetRoots([]Widget{grid})
+
+	dragTo(d, src, 20, 20, 60, 60)
+
+	if want := []string{"b", "c", "d", "a"}; !equalStrings(gridNames(grid), want) {
+		t.Errorf("Expected %v, got %v", want, gridNames(grid))
+	}
+}
+
+// TestDragDrop_GridAcrossGrids 测试在两个网格之间拖放
+func TestDragDrop_GridAcrossGrids(t *testing.T) {
+	left := newDragGrid("left", 0, "a", "b")
+	right := newDragGrid("right", 100, "x")
+	src := newMockInputSource()
+	eq := NewEventQueue()
+	defer eq.Close()
+	d := NewInputDispatcher(src, eq)
+	d.SetRoots([]Widget{left, right})
+
+	// swap模式下拖到已有项的格子上交换
+	dragTo(d, src, 20, 20, 120, 20)
+	if want := []string{"x", "b"}; !equalStrings(gridNames(left), want) {
+		t.Errorf("left: expected %v, got %v", want, gridNames(left))
+	}
+	if want := []string{"a"}; !equalStrings(gridNames(right), want) {
+		t.Errorf("right: expected %v, got %v", want, gridNames(right))
+	}
+
+	// 拖到空格子上时移动项
+	dragTo(d, src, 60, 20, 160, 60)
+	if want := []string{"x"}; !equalStrings(gridNames(left), want) {
+		t.Errorf("left: expected %v, got %v", want, gridNames(left))
+	}
+	if want := []string{"a", "b"}; !equalStrings(gridNames(right), want) {
+		t.Errorf("right: expected %v, got %v", want, gridNames(right))
+	}
+}
+
+// TestDragDrop_GridDropMarksDirty 测试放置后目标网格和源网格都标记重绘（渲染缓存重新绘制子树）
+func TestDragDrop_GridDropMarksDirty(t *testing.T) {
+	left := newDragGrid("left", 0, "a", "b")
+	right := newDragGrid("right", 100, "x")
+	bounds := image.Rect(0, 0, 80, 80)
+
+	// 同一网格内交换
+	left.clearDirty()
+	left.AcceptDrop(&DragPayload{Source: left, Data: map[string]interface{}{"index": 0}}, 60, 20, bounds)
+	if !left.IsDirty() {
+		t.Error("Expected swap within the grid to mark it dirty")
+	}
+
+	// 跨网格移动
+	left.clearDirty()
+	right.clearDirty()
+	right.AcceptDrop(&DragPayload{Source: left, Data: map[string]interface{}{"index": 0}}, 20, 60, bounds)
+	if !left.IsDirty() || !right.IsDirty() {
+		t.Errorf("Expected both grids to be marked dirty, got source=%v target=%v", left.IsDirty(), right.IsDirty())
+	}
+	if want := []string{"x", "b"}; !equalStrings(gridNames(right), want) {
+		t.Errorf("Expected %v, got %v", want, gridNames(right))
+	}
+}
@@ -0,0 +1,327 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+)
+
+// DrawOp 绘制命令的类型
+type DrawOp int
+
+const (
+	OpFillRect          DrawOp = iota // 填充矩形
+	OpFillRoundedRect                 // 填充圆角矩形
+	OpStrokeRoundedRect               // 圆角矩形描边（向内描边）
+	OpImage                           // 把图像拉伸绘制到矩形
+	OpText                            // 文本（Rect.Min为基线起点）
+	OpPushClip                        // 开始裁剪到矩形（与已有的裁剪区域取交集）
+	OpPopClip                         // 结束最近的裁剪
+	OpPushLayer                       // 开始图层：之后的命令绘制到图层中，结束时整体按透明度合成
+	OpPopLayer                        // 结束最近的图层
+	OpFillGradient                    // 用渐变填充圆角矩形
+	OpBoxShadow                       // 圆角矩形的外阴影或内阴影
+)
+
+var drawOpNames = [...]string{
+	OpFillRect:          "fillRect",
+	OpFillRoundedRect:   "fillRoundedRect",
+	OpStrokeRoundedRect: "strokeRoundedRect",
+	OpImage:             "image",
+	OpText:              "text",
+	OpPushClip:          "pushClip",
+	OpPopClip:           "popClip",
+	OpPushLayer:         "pushLayer",
+	OpPopLayer:          "popLayer",
+	OpFillGradient:      "fillGradient",
+	OpBoxShadow:         "boxShadow",
+}
+
+func (op DrawOp) String() string {
+	if op >= 0 && int(op) < len(drawOpNames) {
+		return drawOpNames[op]
+	}
+	return fmt.Sprintf("DrawOp(%d)", int(op))
+}
+
+// DrawCommand 一条绘制命令（坐标均为目标图像上的绝对坐标，颜色为非预乘alpha）
+type DrawCommand struct {
+	Op       DrawOp
+	Rect     image.Rectangle
+	Color    RGBA
+	Radius   int             // 圆角半径
+	Width    int             // 描边宽度
+	Image    image.Image     // 源图像（*ebiten.Image或任意image.Image）
+	Source   image.Rectangle // 源图像中绘制的区域（为空时绘制整个图像）
+	Opacity  int             // 图像和图层的透明度（0-100）
+	Text     string
+	Face     font.Face
+	Gradient Gradient  // 渐变（OpFillGradient）
+	Shadow   BoxShadow // 阴影（OpBoxShadow）
+}
+
+// String 命令的简短描述（用于调试和测试）
+func (c DrawCommand) String() string {
+	switch c.Op {
+	case OpFillRect:
+		return fmt.Sprintf("fillRect %v %v", c.Rect, c.Color)
+	case OpFillRoundedRect:
+		return fmt.Sprintf("fillRoundedRect %v r=%d %v", c.Rect, c.Radius, c.Color)
+	case OpStrokeRoundedRect:
+		return fmt.Sprintf("strokeRoundedRect %v r=%d w=%d %v", c.Rect, c.Radius, c.Width, c.Color)
+	case OpImage:
+		return fmt.Sprintf("image %v opacity=%d", c.Rect, c.Opacity)
+	case OpText:
+		return fmt.Sprintf("text %q at %v %v", c.Text, c.Rect.Min, c.Color)
+	case OpPushClip:
+		return fmt.Sprintf("pushClip %v", c.Rect)
+	case OpPushLayer:
+		return fmt.Sprintf("pushLayer %v opacity=%d", c.Rect, c.Opacity)
+	case OpFillGradient:
+		return fmt.Sprintf("fillGradient %v r=%d %s stops=%d", c.Rect, c.Radius, c.Gradient.Type, len(c.Gradient.Stops))
+	case OpBoxShadow:
+		return fmt.Sprintf("boxShadow %v r=%d %+v", c.Rect, c.Radius, c.Shadow)
+	}
+	return c.Op.String()
+}
+
+// DrawList 记录的绘制命令列表
+// 控件把自身外观记录为与后端无关的命令，由ebiten后端绘制到屏幕，或由纯Go后端绘制到*image.RGBA（无需GPU）
+type DrawList struct {
+	Commands []DrawCommand
+}
+
+// Reset 清空命令（保留容量以便复用）
+func (l *DrawList) Reset() {
+	for i := range l.Commands {
+		l.Commands[i] = DrawCommand{}
+	}
+	l.Commands = l.Commands[:0]
+}
+
+// Len 命令数量
+func (l *DrawList) Len() int {
+	return len(l.Commands)
+}
+
+func (l *DrawList) add(cmd DrawCommand) {
+	l.Commands = append(l.Commands, cmd)
+}
+
+// FillRect 填充矩形（颜色完全透明或矩形为空时不记录）
+func (l *DrawList) FillRect(r image.Rectangle, c RGBA) {
+	if c.A == 0 || r.Empty() {
+		return
+	}
+	l.add(DrawCommand{Op: OpFillRect, Rect: r, Color: c})
+}
+
+// StrokeRect 在矩形内侧绘制指定宽度的边框（四条边互不重叠，半透明颜色不会在角上叠加）
+func (l *DrawList) StrokeRect(r image.Rectangle, width int, c RGBA) {
+	if width <= 0 || c.A == 0 || r.Empty() {
+		return
+	}
+	width = min(width, (min(r.Dx(), r.Dy())+1)/2)
+	l.FillRect(image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+width), c)
+	l.FillRect(image.Rect(r.Min.X, r.Max.Y-width, r.Max.X, r.Max.Y), c)
+	l.FillRect(image.Rect(r.Min.X, r.Min.Y+width, r.Min.X+width, r.Max.Y-width), c)
+	l.FillRect(image.Rect(r.Max.X-width, r.Min.Y+width, r.Max.X, r.Max.Y-width), c)
+}
+
+// FillRoundedRect 填充圆角矩形（半径不大于短边的一半，半径为0时等同FillRect）
+func (l *DrawList) FillRoundedRect(r image.Rectangle, radius int, c RGBA) {
+	if radius <= 0 {
+		l.FillRect(r, c)
+		return
+	}
+	if c.A == 0 || r.Empty() {
+		return
+	}
+	l.add(DrawCommand{Op: OpFillRoundedRect, Rect: r, Radius: radius, Color: c})
+}
+
+// StrokeRoundedRect 在圆角矩形内侧描边（半径为0时等同StrokeRect）
+func (l *DrawList) StrokeRoundedRect(r image.Rectangle, radius, width int, c RGBA) {
+	if radius <= 0 {
+		l.StrokeRect(r, width, c)
+		return
+	}
+	if width <= 0 || c.A == 0 || r.Empty() {
+		return
+	}
+	l.add(DrawCommand{Op: OpStrokeRoundedRect, Rect: r, Radius: radius, Width: width, Color: c})
+}
+
+// FillCircle 填充以(centerX, centerY)为中心、直径为2*radius+1像素的圆
+func (l *DrawList) FillCircle(centerX, centerY, radius int, c RGBA) {
+	l.FillRoundedRect(circleRect(centerX, centerY, radius), radius, c)
+}
+
+// StrokeCircle 在圆的内侧描边
+func (l *DrawList) StrokeCircle(centerX, centerY, radius, width int, c RGBA) {
+	l.StrokeRoundedRect(circleRect(centerX, centerY, radius), radius, width, c)
+}
+
+// circleRect 圆的外接矩形
+func circleRect(centerX, centerY, radius int) image.Rectangle {
+	return image.Rect(centerX-radius, centerY-radius, centerX+radius+1, centerY+radius+1)
+}
+
+// DrawImage 把图像拉伸绘制到矩形（透明度0-100）
+func (l *DrawList) DrawImage(img image.Image, r image.Rectangle, opacity int) {
+	l.DrawSubImage(img, image.Rectangle{}, r, opacity)
+}
+
+// DrawSubImage 把图像中src区域（相对于图像的Bounds）拉伸绘制到矩形，src为空时绘制整个图像
+func (l *DrawList) DrawSubImage(img image.Image, src, r image.Rectangle, opacity int) {
+	if img == nil || opacity <= 0 || r.Empty() {
+		return
+	}
+	if e, ok := img.(*ebiten.Image); ok && e == nil {
+		return
+	}
+	l.add(DrawCommand{Op: OpImage, Rect: r, Image: img, Source: src, Opacity: min(opacity, 100)})
+}
+
+// DrawText 以(x, y)为基线起点绘制单行文本
+func (l *DrawList) DrawText(s string, face font.Face, x, y int, c RGBA) {
+	if s == "" || c.A == 0 || face == nil {
+		return
+	}
+	l.add(DrawCommand{Op: OpText, Rect: image.Rectangle{Min: image.Pt(x, y), Max: image.Pt(x, y)}, Text: s, Face: face, Color: c})
+}
+
+// PushClip 之后的命令裁剪到矩形内（与已有的裁剪区域取交集），必须与PopClip成对使用
+func (l *DrawList) PushClip(r image.Rectangle) {
+	l.add(DrawCommand{Op: OpPushClip, Rect: r})
+}
+
+// PopClip 结束最近的PushClip
+func (l *DrawList) PopClip() {
+	l.add(DrawCommand{Op: OpPopClip})
+}
+
+// PushLayer 之后的命令绘制到覆盖矩形的图层中，PopLayer时整体按透明度（0-100）合成
+// 用于控件整体透明度：背景和文本重叠的部分不会各自透出下方内容
+func (l *DrawList) PushLayer(r image.Rectangle, opacity int) {
+	l.add(DrawCommand{Op: OpPushLayer, Rect: r, Opacity: max(0, min(opacity, 100))})
+}
+
+// PopLayer 结束最近的PushLayer
+func (l *DrawList) PopLayer() {
+	l.add(DrawCommand{Op: OpPopLayer})
+}
+
+// pushGroup 开始控件内容组：透明度低于100时使用图层整体合成，否则只裁剪到控件边界
+func pushGroup(list *DrawList, bounds image.Rectangle, opacity int) {
+	if opacity < 100 {
+		list.PushLayer(bounds, opacity)
+	} else {
+		list.PushClip(bounds)
+	}
+}
+
+// popGroup 结束pushGroup开始的内容组
+func popGroup(list *DrawList, opacity int) {
+	if opacity < 100 {
+		list.PopLayer()
+	} else {
+		list.PopClip()
+	}
+}
+
+// DrawBackend 执行绘制命令列表的后端
+type DrawBackend interface {
+	Execute(list *DrawList)
+}
+
+// widgetPainter 能把自身外观记录为绘制命令的控件（所有内置控件都实现了该接口）
+// Paint只记录控件自身（不包括子控件），bounds为控件在目标上的绝对边界
+type widgetPainter interface {
+	Paint(list *DrawList, bounds image.Rectangle)
+}
+
+// foregroundPainter 在子控件之上还有外观需要绘制的控件（例如滚动面板的滚动条）
+type foregroundPainter interface {
+	PaintForeground(list *DrawList, bounds image.Rectangle)
+}
+
+// RecordWidgets 按层叠顺序把控件树记录为绘制命令（与DrawWidgets的绘制顺序和裁剪相同）
+// 使用布局过程缓存的边界，调用前需要先布局；没有实现绘制命令的自定义控件会被跳过
+func RecordWidgets(list *DrawList, roots []Widget, viewportWidth, viewportHeight int) {
+	viewport := image.Rect(0, 0, viewportWidth, viewportHeight)
+	recordChildren(list, roots, viewport)
+
+	for _, e := range buildStackOrder(roots, viewport, viewport) {
+		if e.overlay {
+			recordWidget(list, e.widget, e.parent)
+		}
+	}
+}
+
+// recordChildren 按层叠顺序记录子控件（覆盖层控件由RecordWidgets在最后记录）
+func recordChildren(list *DrawList, children []Widget, content image.Rectangle) {
+	for _, child := range sortedByZ(children) {
+		if !isOverlay(child) {
+			recordWidget(list, child, content)
+		}
+	}
+}
+
+// recordWidget 记录控件及其子树
+func recordWidget(list *DrawList, widget Widget, parent image.Rectangle) {
+	if !widget.IsVisible() {
+		return
+	}
+	bounds := widgetBounds(widget, parent)
+	if p, ok := widget.(widgetPainter); ok {
+		p.Paint(list, bounds)
+	}
+
+	if children := widget.GetChildren(); len(children) > 0 {
+		clip := clipsChildren(widget)
+		if clip {
+			list.PushClip(bounds)
+		}
+		recordChildren(list, children, widgetContentBounds(widget, bounds))
+		if clip {
+			list.PopClip()
+		}
+	}
+
+	if p, ok := widget.(foregroundPainter); ok {
+		p.PaintForeground(list, bounds)
+	}
+}
+
+// paintList 控件绘制时复用的命令列表（绘制在主线程上进行，Paint不会嵌套调用Draw）
+var paintList DrawList
+
+// paintBackend 控件绘制时使用的ebiten后端
+var paintBackend = NewEbitenBackend(nil)
+
+// drawPainted 把控件自身的外观记录为命令并用ebiten后端绘制到screen
+func drawPainted(screen *ebiten.Image, p widgetPainter, bounds image.Rectangle) {
+	paintList.Reset()
+	p.Paint(&paintList, bounds)
+	if paintList.Len() == 0 {
+		return
+	}
+	paintBackend.Target = screen
+	paintBackend.Execute(&paintList)
+	paintBackend.Target = nil
+}
+
+// drawForeground 绘制控件在子控件之上的外观
+func drawForeground(screen *ebiten.Image, p foregroundPainter, bounds image.Rectangle) {
+	paintList.Reset()
+	p.PaintForeground(&paintList, bounds)
+	if paintList.Len() == 0 {
+		return
+	}
+	paintBackend.Target = screen
+	paintBackend.Execute(&paintList)
+	paintBackend.Target = nil
+}
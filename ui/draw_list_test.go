@@ -0,0 +1,454 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font/basicfont"
+)
+
+// updateGolden 重新生成golden图像：go test -run Golden -update
+var updateGolden = flag.Bool("update", false, "rewrite testdata/golden/*.png with the current rendering")
+
+// checkGolden 将渲染结果与testdata/golden/<name>.png逐像素比较
+func checkGolden(t *testing.T, name string, got *image.RGBA) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".png")
+
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := png.Encode(f, got); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("missing golden image %s (run with -update to create it): %v", path, err)
+	}
+	defer f.Close()
+	want, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("decode %s: %v", path, err)
+	}
+
+	if want.Bounds() != got.Bounds() {
+		t.Fatalf("%s: size mismatch: want %v, got %v", name, want.Bounds(), got.Bounds())
+	}
+	diff, first := 0, image.Point{}
+	for y := got.Bounds().Min.Y; y < got.Bounds().Max.Y; y++ {
+		for x := got.Bounds().Min.X; x < got.Bounds().Max.X; x++ {
+			if color.NRGBAModel.Convert(want.At(x, y)) != color.NRGBAModel.Convert(got.At(x, y)) {
+				if diff == 0 {
+					first = image.Pt(x, y)
+				}
+				diff++
+			}
+		}
+	}
+	if diff > 0 {
+		t.Errorf("%s: %d pixels differ from golden image, first at %v: want %v, got %v",
+			name, diff, first, want.At(first.X, first.Y), got.At(first.X, first.Y))
+	}
+}
+
+// renderGolden 布局并用纯Go后端渲染控件树，与golden图像比较
+func renderGolden(t *testing.T, name string, width, height int, sources map[*ebiten.Image]image.Image, roots ...Widget) {
+	t.Helper()
+	PerformLayout(roots, width, height)
+	checkGolden(t, name, RenderToRGBA(roots, width, height, sources))
+}
+
+// ops 提取命令类型序列
+func ops(list *DrawList) []DrawOp {
+	result := make([]DrawOp, len(list.Commands))
+	for i, cmd := range list.Commands {
+		result[i] = cmd.Op
+	}
+	return result
+}
+
+// TestDrawList_ButtonWithOpacityUsesLayer 测试半透明按钮记录为图层包围的背景和文本
+func TestDrawList_ButtonWithOpacityUsesLayer(t *testing.T) {
+	b := NewButton("b")
+	b.Text = "OK"
+	b.Opacity = 50
+
+	var list DrawList
+	b.Paint(&list, image.Rect(10, 10, 110, 40))
+
+	want := []DrawOp{OpPushLayer, OpFillRect, OpText, OpPopLayer}
+	if got := ops(&list); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected ops %v, got %v", want, got)
+	}
+	if list.Commands[0].Opacity != 50 || list.Commands[0].Rect != image.Rect(10, 10, 110, 40) {
+		t.Errorf("unexpected layer command: %v", list.Commands[0])
+	}
+
+	// 不透明时只裁剪
+	list.Reset()
+	b.Opacity = 100
+	b.Paint(&list, image.Rect(10, 10, 110, 40))
+	if got := ops(&list); got[0] != OpPushClip || got[len(got)-1] != OpPopClip {
+		t.Errorf("expected opaque button to be wrapped in a clip, got %v", got)
+	}
+}
+
+// TestDrawList_SkipsInvisibleAndEmpty 测试透明颜色、空矩形和零宽描边不产生命令
+func TestDrawList_SkipsInvisibleAndEmpty(t *testing.T) {
+	var list DrawList
+	list.FillRect(image.Rect(0, 0, 10, 10), RGBA{255, 0, 0, 0})
+	list.FillRect(image.Rect(5, 5, 5, 10), RGBA{255, 0, 0, 255})
+	list.StrokeRect(image.Rect(0, 0, 10, 10), 0, RGBA{255, 0, 0, 255})
+	list.FillRoundedRect(image.Rect(0, 0, 10, 10), 4, RGBA{})
+	if list.Len() != 0 {
+		t.Errorf("expected no commands, got %v", list.Commands)
+	}
+
+	// 半径为0的圆角矩形退化为矩形
+	list.FillRoundedRect(image.Rect(0, 0, 10, 10), 0, RGBA{1, 2, 3, 255})
+	list.StrokeRoundedRect(image.Rect(0, 0, 10, 10), 0, 2, RGBA{1, 2, 3, 255})
+	want := []DrawOp{OpFillRect, OpFillRect, OpFillRect, OpFillRect, OpFillRect}
+	if got := ops(&list); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected ops %v, got %v", want, got)
+	}
+}
+
+// TestDrawList_RecordWidgetsClipsChildren 测试开启裁剪的容器在子控件外记录裁剪
+func TestDrawList_RecordWidgetsClipsChildren(t *testing.T) {
+	panel := NewPanel("p")
+	panel.Width, panel.Height = 100, 100
+	panel.ClipChildren = true
+	panel.BackgroundColor = RGBA{255, 255, 255, 255}
+	panel.BackgroundAlpha = 255
+	child := NewPanel("c")
+	child.X, child.Y, child.Width, child.Height = 50, 50, 100, 100
+	child.BackgroundColor = RGBA{255, 0, 0, 255}
+	child.BackgroundAlpha = 255
+	panel.AddChild(child)
+
+	roots := []Widget{panel}
+	PerformLayout(roots, 200, 200)
+
+	var list DrawList
+	RecordWidgets(&list, roots, 200, 200)
+
+	got := ops(&list)
+	var clips int
+	for _, op := range got {
+		if op == OpPushClip {
+			clips++
+		}
+	}
+	// 两个面板自身的内容组各一个裁剪，加上子控件的裁剪
+	if clips != 3 {
+		t.Fatalf("expected 3 clips, got %d: %v", clips, list.Commands)
+	}
+	if list.Commands[len(list.Commands)-1].Op != OpPopClip {
+		t.Errorf("expected children clip to be closed last, got %v", got)
+	}
+}
+
+// TestSoftwareBackend_ClipAndLayer 测试纯Go后端的裁剪和图层合成
+func TestSoftwareBackend_ClipAndLayer(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	var list DrawList
+	list.PushClip(image.Rect(0, 0, 5, 10))
+	list.FillRect(image.Rect(0, 0, 10, 10), RGBA{255, 0, 0, 255})
+	list.PopClip()
+	list.PushLayer(image.Rect(0, 5, 10, 10), 50)
+	list.FillRect(image.Rect(0, 0, 10, 10), RGBA{0, 0, 255, 255})
+	list.FillRect(image.Rect(0, 0, 10, 10), RGBA{0, 0, 255, 255}) // 图层内重叠后整体只合成一次
+	list.PopLayer()
+	NewSoftwareBackend(dst).Execute(&list)
+
+	if got := dst.RGBAAt(2, 2); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("expected red inside clip, got %v", got)
+	}
+	if got := dst.RGBAAt(7, 2); got != (color.RGBA{}) {
+		t.Errorf("expected transparent outside clip, got %v", got)
+	}
+	if got := dst.RGBAAt(7, 7); got.B < 125 || got.B > 130 || got.A < 125 || got.A > 130 {
+		t.Errorf("expected half transparent blue in layer, got %v", got)
+	}
+	if got := dst.RGBAAt(2, 7); got.R < 125 || got.R > 130 || got.B < 125 || got.B > 130 || got.A != 255 {
+		t.Errorf("expected blue layer blended over red, got %v", got)
+	}
+}
+
+// TestSoftwareBackend_UnbalancedPushesAreClosed 测试未配对的裁剪和图层在结束时关闭
+func TestSoftwareBackend_UnbalancedPushesAreClosed(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	var list DrawList
+	list.PushLayer(image.Rect(0, 0, 4, 4), 100)
+	list.PushClip(image.Rect(0, 0, 2, 2))
+	list.FillRect(image.Rect(0, 0, 4, 4), RGBA{0, 255, 0, 255})
+	list.PopClip()
+	list.PopClip() // 多余的出栈被忽略
+	backend := NewSoftwareBackend(dst)
+	backend.Execute(&list)
+
+	if got := dst.RGBAAt(1, 1); got != (color.RGBA{0, 255, 0, 255}) {
+		t.Errorf("expected layer to be composited, got %v", got)
+	}
+	if len(backend.stack) != 0 {
+		t.Errorf("expected surface stack to be empty, got %d", len(backend.stack))
+	}
+	if loadEffectShader() == nil {
+		t.Errorf("expected gradient and shadow shader to compile")
+	}
+}
+
+// TestSoftwareBackend_ShapeStrokeStaysInside 测试圆角描边在边界内侧，中心保持透明
+func TestSoftwareBackend_ShapeStrokeStaysInside(t *testing.T) {
+	dst := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	var list DrawList
+	list.StrokeRoundedRect(image.Rect(2, 2, 18, 18), 4, 2, RGBA{0, 0, 0, 255})
+	NewSoftwareBackend(dst).Execute(&list)
+
+	if got := dst.RGBAAt(10, 2); got.A != 255 {
+		t.Errorf("expected top edge to be stroked, got %v", got)
+	}
+	if got := dst.RGBAAt(10, 1); got.A != 0 {
+		t.Errorf("expected nothing outside the rect, got %v", got)
+	}
+	if got := dst.RGBAAt(10, 10); got.A != 0 {
+		t.Errorf("expected center to stay transparent, got %v", got)
+	}
+	if got := dst.RGBAAt(2, 2); got.A != 0 {
+		t.Errorf("expected rounded corner to stay transparent, got %v", got)
+	}
+}
+
+// TestEbitenBackend_ExecutesAllOps 测试ebiten后端执行所有类型的命令（包括未配对的入栈）
+func TestEbitenBackend_ExecutesAllOps(t *testing.T) {
+	dst := ebiten.NewImage(40, 40)
+	defer dst.Dispose()
+	src := image.NewRGBA(image.Rect(0, 0, 4, 4))
+
+	var list DrawList
+	list.PushLayer(image.Rect(0, 0, 40, 40), 60)
+	list.FillRect(image.Rect(0, 0, 10, 10), RGBA{255, 0, 0, 255})
+	list.FillRoundedRect(image.Rect(0, 0, 20, 20), 5, RGBA{0, 255, 0, 255})
+	list.PushClip(image.Rect(5, 5, 30, 30))
+	list.StrokeRoundedRect(image.Rect(0, 0, 30, 30), 6, 2, RGBA{0, 0, 255, 255})
+	list.DrawSubImage(src, image.Rect(0, 0, 2, 2), image.Rect(10, 10, 20, 20), 50)
+	list.DrawText("A", basicfont.Face7x13, 5, 20, RGBA{0, 0, 0, 255})
+	list.FillGradient(image.Rect(0, 0, 30, 30), 6, Gradient{Type: GradientRadial, CenterX: 0.5, CenterY: 0.5, Stops: []GradientStop{
+		{Offset: 0, Color: RGBA{255, 255, 255, 255}}, {Offset: 1, Color: RGBA{0, 0, 0, 0}},
+	}})
+	list.DrawBoxShadow(image.Rect(10, 10, 25, 25), 4, BoxShadow{OffsetY: 2, Blur: 4, Color: RGBA{0, 0, 0, 128}})
+	list.DrawBoxShadow(image.Rect(10, 10, 25, 25), 4, BoxShadow{Blur: 4, Color: RGBA{0, 0, 0, 128}, Inset: true})
+	list.PushClip(image.Rect(100, 100, 120, 120)) // 空裁剪区域
+	list.FillRect(image.Rect(0, 0, 40, 40), RGBA{0, 0, 0, 255})
+
+	backend := NewEbitenBackend(dst)
+	backend.Execute(&list)
+	backend.ReleaseSources()
+
+	if len(backend.stack) != 0 {
+		t.Errorf("expected surface stack to be empty, got %d", len(backend.stack))
+	}
+}
+
+// checkerImage 创建2x2格子的测试源图像
+func checkerImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if (x/2+y/2)%2 == 0 {
+				img.Set(x, y, color.RGBA{200, 60, 60, 255})
+			} else {
+				img.Set(x, y, color.RGBA{60, 60, 200, 255})
+			}
+		}
+	}
+	return img
+}
+
+// TestGolden_Widgets 各控件在常见状态下的golden图像
+func TestGolden_Widgets(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		build  func() []Widget
+	}{
+		{"button", 120, 40, func() []Widget {
+			b := NewButton("b")
+			b.X, b.Y, b.Width, b.Height = 10, 5, 100, 30
+			b.Text = "Button"
+			return []Widget{b}
+		}},
+		{"button_opacity", 120, 40, func() []Widget {
+			b := NewButton("b")
+			b.X, b.Y, b.Width, b.Height = 10, 5, 100, 30
+			b.Text = "Faded"
+			b.Opacity = 40
+			return []Widget{b}
+		}},
+		{"label", 120, 30, func() []Widget {
+			l := NewLabel("l")
+			l.X, l.Y, l.Width, l.Height = 5, 5, 110, 20
+			l.Text = "Hello, label"
+			l.BackgroundColor = RGBA{230, 230, 250, 255}
+			return []Widget{l}
+		}},
+		{"panel_opacity", 100, 100, func() []Widget {
+			back := NewPanel("back")
+			back.Width, back.Height = 100, 100
+			back.BackgroundColor = RGBA{255, 255, 255, 255}
+			back.BackgroundAlpha = 255
+			p := NewPanel("p")
+			p.X, p.Y, p.Width, p.Height = 20, 20, 60, 60
+			p.BackgroundColor = RGBA{0, 100, 200, 255}
+			p.BackgroundAlpha = 255
+			p.Opacity = 50
+			back.AddChild(p)
+			return []Widget{back}
+		}},
+		{"textinput_focused", 160, 40, func() []Widget {
+			in := NewTextInput("in")
+			in.X, in.Y, in.Width, in.Height = 5, 5, 150, 30
+			in.Text = "typing"
+			in.SetFocused(true)
+			in.CursorPos = 3
+			in.CursorVisible = true
+			return []Widget{in}
+		}},
+		{"textinput_placeholder", 160, 40, func() []Widget {
+			in := NewTextInput("in")
+			in.X, in.Y, in.Width, in.Height = 5, 5, 150, 30
+			in.PlaceholderText = "Search..."
+			return []Widget{in}
+		}},
+		{"checkbox_checked", 140, 30, func() []Widget {
+			c := NewCheckBox("c", 5, 5, 130, 20)
+			c.Text = "Remember me"
+			c.Checked = true
+			return []Widget{c}
+		}},
+		{"radiobutton_selected", 140, 30, func() []Widget {
+			r := NewRadioButton("r", 5, 5, 130, 20)
+			r.Text = "Option A"
+			r.Selected = true
+			return []Widget{r}
+		}},
+		{"slider_horizontal", 160, 40, func() []Widget {
+			s := NewSlider("s", 5, 5, 150, 30)
+			s.Value = 30
+			s.ShowValue = true
+			return []Widget{s}
+		}},
+		{"slider_vertical", 40, 160, func() []Widget {
+			s := NewSlider("s", 5, 5, 30, 150)
+			s.Orientation = SliderOrientationVertical
+			s.Value = 70
+			return []Widget{s}
+		}},
+		{"combobox_expanded", 160, 140, func() []Widget {
+			c := NewComboBox("c", 5, 5, 150, 30)
+			c.Items = []string{"Apple", "Banana", "Cherry"}
+			c.SelectedIndex = 1
+			c.IsExpanded = true
+			// 展开的下拉列表在弹出层中打开，与主框一起渲染
+			layers := NewLayerManager()
+			PerformLayout([]Widget{c}, 160, 140)
+			layers.layout(image.Rect(0, 0, 160, 140))
+			syncPopups([]Widget{c}, layers)
+			return []Widget{c, c.popup.Content}
+		}},
+		{"listview_placeholder", 160, 120, func() []Widget {
+			l := NewListView("l")
+			l.X, l.Y, l.Width, l.Height = 5, 5, 150, 110
+			return []Widget{l}
+		}},
+		{"gridview_placeholder", 160, 120, func() []Widget {
+			g := NewGridView("g")
+			g.X, g.Y, g.Width, g.Height = 5, 5, 150, 110
+			return []Widget{g}
+		}},
+		{"tableview_placeholder", 200, 150, func() []Widget {
+			tv := NewTableView("t")
+			tv.X, tv.Y, tv.Width, tv.Height = 5, 5, 190, 140
+			return []Widget{tv}
+		}},
+		{"tableview_rows", 200, 150, func() []Widget {
+			tv := NewTableView("t")
+			tv.X, tv.Y, tv.Width, tv.Height = 5, 5, 190, 140
+			tv.Columns = []TableColumn{{Key: "name", Label: "Name", Width: 100}, {Key: "qty", Label: "Qty", Width: 60}}
+			tv.Items = []map[string]interface{}{
+				{"name": "Apple", "qty": 3},
+				{"name": "Banana", "qty": 12},
+				{"name": "Cherry", "qty": 7},
+			}
+			tv.SortColumn = "qty"
+			return []Widget{tv}
+		}},
+		{"scrollpanel", 120, 120, func() []Widget {
+			s := NewScrollPanel("s")
+			s.X, s.Y, s.Width, s.Height = 10, 10, 100, 100
+			s.BackgroundColor = RGBA{240, 240, 240, 255}
+			s.BackgroundAlpha = 255
+			s.ScrollY = 30
+			for i := 0; i < 4; i++ {
+				c := NewPanel(fmt.Sprintf("c%d", i))
+				c.Y, c.Width, c.Height = i*50, 80, 40
+				c.BackgroundColor = RGBA{uint8(60 * i), 120, 200, 255}
+				c.BackgroundAlpha = 255
+				s.AddChild(c)
+			}
+			return []Widget{s}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderGolden(t, tt.name, tt.width, tt.height, nil, tt.build()...)
+		})
+	}
+}
+
+// TestGolden_ImageScaleModes 图片控件各缩放模式的golden图像（使用CPU侧源图像）
+func TestGolden_ImageScaleModes(t *testing.T) {
+	src := checkerImage(16, 8)
+	img := ebiten.NewImageFromImage(src)
+	defer img.Dispose()
+	sources := map[*ebiten.Image]image.Image{img: src}
+
+	for _, mode := range []string{"fit", "fill", "stretch", "none"} {
+		t.Run(mode, func(t *testing.T) {
+			w := NewImage("img")
+			w.X, w.Y, w.Width, w.Height = 4, 4, 40, 40
+			w.ScaleMode = mode
+			w.SetImage(img)
+			renderGolden(t, "image_"+mode, 48, 48, sources, w)
+		})
+	}
+}
+
+// TestGolden_SampleLayout 示例布局文件的golden图像
+func TestGolden_SampleLayout(t *testing.T) {
+	loader := NewLoader()
+	roots, err := loader.LoadFromFile(filepath.Join("examples", "viewer", "sample_layout.json"))
+	if err != nil {
+		t.Fatalf("failed to load sample layout: %v", err)
+	}
+	renderGolden(t, "sample_layout", 1280, 720, loader.ImageSources(), roots...)
+}
@@ -8,15 +8,41 @@ import (
 type EventType string
 
 const (
-	EventClick     EventType = "click"
-	EventHover     EventType = "hover"
-	EventMouseDown EventType = "mousedown"
-	EventMouseUp   EventType = "mouseup"
-	EventFocus     EventType = "focus"
-	EventBlur      EventType = "blur"
-	EventChange    EventType = "change"
-	EventSubmit    EventType = "submit"
-	EventKeyPress  EventType = "keypress"
+	EventClick      EventType = "click"
+	EventHover      EventType = "hover"
+	EventMouseDown  EventType = "mousedown"
+	EventMouseUp    EventType = "mouseup"
+	EventMouseEnter EventType = "mouseenter"
+	EventMouseLeave EventType = "mouseleave"
+	EventWheel      EventType = "wheel"
+	EventFocus      EventType = "focus"
+	EventBlur       EventType = "blur"
+	EventChange     EventType = "change"
+	EventSubmit     EventType = "submit"
+	EventCancel     EventType = "cancel"
+	EventKeyDown    EventType = "keydown"
+	EventKeyUp      EventType = "keyup"
+	EventKeyPress   EventType = "keypress"
+	EventScroll     EventType = "scroll"
+
+	// 交互状态变化（悬停、按下、焦点、禁用、选中）
+	EventStateChange EventType = "statechange"
+
+	// 拖放
+	EventDragStart EventType = "dragstart"
+	EventDragOver  EventType = "dragover"
+	EventDrop      EventType = "drop"
+	EventDragEnd   EventType = "dragend"
+
+	// 触摸与手势
+	EventTouchStart EventType = "touchstart"
+	EventTouchMove  EventType = "touchmove"
+	EventTouchEnd   EventType = "touchend"
+	EventTap        EventType = EventType(GestureTap)
+	EventDoubleTap  EventType = EventType(GestureDoubleTap)
+	EventLongPress  EventType = EventType(GestureLongPress)
+	EventSwipe      EventType = EventType(GestureSwipe)
+	EventPinch      EventType = EventType(GesturePinch)
 )
 
 // WidgetEvent 控件事件
@@ -7,11 +7,11 @@ import (
 	"image/color"
 	"log"
 	"os"
-	"sort"
-	"time"
+	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/packing/EbitenStudio/ui"
 )
 
@@ -22,37 +22,69 @@ const (
 
 // Game 游戏主结构
 type Game struct {
-	width          int
-	height         int
-	currentWidth   int // 当前窗口宽度
-	currentHeight  int // 当前窗口高度
-	renderer       *ui.Renderer
-	loader         *ui.Loader
-	rootPanel      *ui.PanelWidget
-	widgets        []ui.Widget
-	scriptEngine   *ui.ScriptEngine
-	eventQueue     *ui.EventQueue
-	commandQueue   *ui.CommandQueue
-	isMousePressed bool // 鼠标按下状态
+	width         int
+	height        int
+	currentWidth  int // 当前窗口宽度
+	currentHeight int // 当前窗口高度
+	renderCache   *ui.RenderCache
+	loader        *ui.Loader
+	widgets       []ui.Widget
+	scriptEngine  *ui.ScriptEngine
+	eventQueue    *ui.EventQueue
+	commandQueue  *ui.CommandQueue
+	dispatcher    *ui.InputDispatcher
+	input         ui.InputResult // 最近一帧的输入消耗情况
+	scaler        *ui.ScreenScaler
+	scaleMode     string      // 命令行指定的缩放模式（优先于.ui文件中的scaleMode）
+	themes        []*ui.Theme // 命令行指定的主题（F2循环切换）
+	themeIndex    int
 }
 
 // NewGame 创建游戏实例
-func NewGame(layoutFile string) (*Game, error) {
+func NewGame(layoutFile, scaleMode string, themeFiles []string) (*Game, error) {
 	g := &Game{
 		width:         defaultWidth,
 		height:        defaultHeight,
 		currentWidth:  defaultWidth,
 		currentHeight: defaultHeight,
-		renderer:      ui.NewRenderer(),
+		renderCache:   ui.NewRenderCache(),
 		loader:        ui.NewLoader(),
 		eventQueue:    ui.NewEventQueue(),
 		commandQueue:  ui.NewCommandQueue(),
+		scaleMode:     scaleMode,
+	}
+
+	// 设计分辨率缩放（默认不缩放，加载布局后更新设计尺寸和模式）
+	g.scaler = ui.NewScreenScaler(ui.ScaleModeNone, g.width, g.height)
+	if err := g.applyScaleMode(""); err != nil {
+		return nil, err
 	}
 
 	// 初始化脚本引擎
 	engineConfig := ui.DefaultScriptEngineConfig()
 	g.scriptEngine = ui.NewScriptEngine(g.eventQueue, g.commandQueue, engineConfig)
 
+	// 初始化输入分发器（读取ebiten输入并生成控件事件）
+	// 输入坐标先从屏幕坐标转换回设计坐标，命中测试与绘制保持一致
+	g.dispatcher = ui.NewInputDispatcher(ui.NewScaledInputSource(ui.EbitenInputSource{}, g.scaler), g.eventQueue)
+	g.dispatcher.SetNavigationSource(ui.NewEbitenNavigationSource())
+	g.dispatcher.SetCursorController(ui.EbitenCursorController{})
+
+	// 脚本通过getBounds读取分发器每帧计算的布局结果
+	g.scriptEngine.SetLayoutEngine(g.dispatcher.Layout())
+
+	// 加载主题（第一个主题在加载布局时应用）
+	for _, file := range themeFiles {
+		theme, err := g.loader.LoadTheme(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load theme: %w", err)
+		}
+		g.themes = append(g.themes, theme)
+	}
+	if len(g.themes) > 0 {
+		g.loader.SetTheme(g.themes[0])
+	}
+
 	// 加载UI布局
 	if layoutFile != "" {
 		if err := g.loadLayout(layoutFile); err != nil {
@@ -98,6 +130,13 @@ func (g *Game) loadLayout(filename string) error {
 
 	log.Printf("Loaded layout size: width=%d, height=%d", g.width, g.height)
 
+	// 缩放模式：命令行参数优先，其次是.ui文件中的scaleMode
+	g.scaler.DesignWidth, g.scaler.DesignHeight = g.width, g.height
+	fileMode, _ := layoutData["scaleMode"].(string)
+	if err := g.applyScaleMode(fileMode); err != nil {
+		return err
+	}
+
 	// 使用Loader的LoadFromFile方法，自动处理pak资源包
 	widgets, err := g.loader.LoadFromFile(filename)
 	if err != nil {
@@ -105,6 +144,7 @@ func (g *Game) loadLayout(filename string) error {
 	}
 
 	g.widgets = widgets
+	g.dispatcher.SetRoots(widgets)
 
 	// 加载脚本并注册到引擎
 	scripts := g.loader.GetScripts()
@@ -154,29 +194,64 @@ func (g *Game) detectHandlers(widgetID string, widgetType ui.WidgetType) map[ui.
 	switch widgetType {
 	case ui.TypeButton:
 		handlers[ui.EventClick] = widgetID + ".onClick"
-		// 可以添加其他事件，如 hover
 	case ui.TypeTextInput:
 		handlers[ui.EventClick] = widgetID + ".onClick"
 		handlers[ui.EventChange] = widgetID + ".onChange"
+		handlers[ui.EventKeyDown] = widgetID + ".onKeyDown"
+		handlers[ui.EventKeyUp] = widgetID + ".onKeyUp"
+		handlers[ui.EventKeyPress] = widgetID + ".onKeyPress"
+	case ui.TypeComboBox:
+		handlers[ui.EventClick] = widgetID + ".onClick"
+		handlers[ui.EventChange] = widgetID + ".onChange"
 	default:
 		// 默认至少支持点击事件
 		handlers[ui.EventClick] = widgetID + ".onClick"
 	}
 
+	// 所有控件通用的指针事件（脚本未定义对应函数时会被忽略）
+	handlers[ui.EventMouseDown] = widgetID + ".onMouseDown"
+	handlers[ui.EventMouseUp] = widgetID + ".onMouseUp"
+	handlers[ui.EventMouseEnter] = widgetID + ".onMouseEnter"
+	handlers[ui.EventMouseLeave] = widgetID + ".onMouseLeave"
+	handlers[ui.EventHover] = widgetID + ".onHover"
+	handlers[ui.EventWheel] = widgetID + ".onWheel"
+	handlers[ui.EventScroll] = widgetID + ".onScroll"
+	handlers[ui.EventFocus] = widgetID + ".onFocus"
+	handlers[ui.EventBlur] = widgetID + ".onBlur"
+	handlers[ui.EventStateChange] = widgetID + ".onStateChange"
+	handlers[ui.EventCancel] = widgetID + ".onCancel"
+	handlers[ui.EventDragStart] = widgetID + ".onDragStart"
+	handlers[ui.EventDragOver] = widgetID + ".onDragOver"
+	handlers[ui.EventDrop] = widgetID + ".onDrop"
+	handlers[ui.EventDragEnd] = widgetID + ".onDragEnd"
+	handlers[ui.EventTouchStart] = widgetID + ".onTouchStart"
+	handlers[ui.EventTouchMove] = widgetID + ".onTouchMove"
+	handlers[ui.EventTouchEnd] = widgetID + ".onTouchEnd"
+	handlers[ui.EventTap] = widgetID + ".onTap"
+	handlers[ui.EventDoubleTap] = widgetID + ".onDoubleTap"
+	handlers[ui.EventLongPress] = widgetID + ".onLongPress"
+	handlers[ui.EventSwipe] = widgetID + ".onSwipe"
+	handlers[ui.EventPinch] = widgetID + ".onPinch"
+
 	return handlers
 }
 
+// nextTheme 切换到下一个主题（运行时重新应用样式）
+func (g *Game) nextTheme() {
+	if len(g.themes) < 2 {
+		return
+	}
+	g.themeIndex = (g.themeIndex + 1) % len(g.themes)
+	theme := g.themes[g.themeIndex]
+	ui.SetTheme(g.widgets, theme)
+	log.Printf("[Viewer] Switched to theme %q", theme.Name)
+}
+
 // Update 更新游戏状态
 func (g *Game) Update() error {
-	// 检测鼠标点击事件
-	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-		if !g.isMousePressed {
-			g.isMousePressed = true
-			mx, my := ebiten.CursorPosition()
-			g.handleMouseClick(mx, my)
-		}
-	} else {
-		g.isMousePressed = false
+	// 文本输入框获得焦点时不响应快捷键（使用上一帧的输入消耗情况）
+	if !g.input.TextInputActive && inpututil.IsKeyJustPressed(ebiten.KeyF2) {
+		g.nextTheme()
 	}
 
 	// 更新所有控件
@@ -195,59 +270,10 @@ func (g *Game) Update() error {
 		g.executeCommand(cmd)
 	}
 
-	return nil
-}
-
-// handleMouseClick 处理鼠标点击，触发对应的脚本事件
-func (g *Game) handleMouseClick(x, y int) {
-	log.Printf("[Viewer] handleMouseClick called at (%d, %d)", x, y)
-
-	// 查找被点击的控件
-	clicked := g.findClickedWidget(g.widgets, x, y)
-	if clicked != nil {
-		widgetID := clicked.GetID()
-		log.Printf("[Viewer] Widget clicked: %s at (%d, %d)", widgetID, x, y)
-
-		// 推送点击事件到事件队列
-		event := ui.WidgetEvent{
-			Type:      ui.EventClick,
-			WidgetID:  widgetID,
-			Widget:    clicked,
-			X:         x,
-			Y:         y,
-			Button:    0,
-			Timestamp: time.Now(),
-		}
-		g.eventQueue.Push(event)
-		log.Printf("[Viewer] Event pushed to queue for widget %s", widgetID)
-	}
-}
+	// 布局并分发输入事件（按下/抬起/点击/进入/离开/滚轮/按键）
+	// 放在控件和命令更新之后，使本帧绘制和命中测试使用同一次布局的结果
+	g.input = g.dispatcher.Update()
 
-// findClickedWidget 递归查找被点击的控件
-func (g *Game) findClickedWidget(widgets []ui.Widget, x, y int) ui.Widget {
-	// 按 z-index 倒序排序（高 z-index 优先）
-	sortedWidgets := make([]ui.Widget, len(widgets))
-	copy(sortedWidgets, widgets)
-	sort.Slice(sortedWidgets, func(i, j int) bool {
-		return sortedWidgets[i].GetZIndex() > sortedWidgets[j].GetZIndex()
-	})
-
-	for _, widget := range sortedWidgets {
-		// 先检查子控件
-		children := widget.GetChildren()
-		if found := g.findClickedWidget(children, x, y); found != nil {
-			return found
-		}
-
-		// 再检查当前控件
-		wx, wy := widget.GetX(), widget.GetY()
-		ww, wh := widget.GetWidth(), widget.GetHeight()
-		if x >= wx && x < wx+ww &&
-			y >= wy && y < wy+wh &&
-			widget.IsVisible() && widget.IsInteractive() {
-			return widget
-		}
-	}
 	return nil
 }
 
@@ -275,14 +301,58 @@ func (g *Game) executeCommand(cmd ui.WidgetCommand) {
 		if value, ok := cmd.Value.(bool); ok {
 			widget.SetVisible(value)
 		}
+	case ui.CommandFocus:
+		if !g.dispatcher.FocusManager().Focus(widget) {
+			log.Printf("[Viewer] Widget %s cannot take focus", cmd.WidgetID)
+		}
+	case ui.CommandBlur:
+		g.dispatcher.FocusManager().BlurByID(cmd.WidgetID)
 	case ui.CommandSetProperty:
 		// 通用属性设置
 		log.Printf("[Viewer] Set property %s on %s", cmd.Property, cmd.WidgetID)
+		g.setProperty(widget, cmd.Property, cmd.Value)
 	default:
 		log.Printf("[Viewer] Unknown command type: %s", cmd.Type)
 	}
 }
 
+// setProperty 设置控件的通用属性
+func (g *Game) setProperty(widget ui.Widget, property string, value interface{}) {
+	switch property {
+	case "tooltip":
+		if setter, ok := widget.(interface{ SetTooltip(string) }); ok {
+			if text, ok := value.(string); ok {
+				setter.SetTooltip(text)
+			}
+		}
+	case "tooltipTemplate":
+		if setter, ok := widget.(interface{ SetTooltipTemplate(string) }); ok {
+			if id, ok := value.(string); ok {
+				setter.SetTooltipTemplate(id)
+			}
+		}
+	case "cursor":
+		if setter, ok := widget.(interface{ SetCursor(string) }); ok {
+			if cursor, ok := value.(string); ok {
+				setter.SetCursor(cursor)
+			}
+		}
+	case "scrollTo", "scrollBy":
+		// 通过分发器滚动，滚动位置改变时脚本收到scroll事件
+		panel, ok := widget.(*ui.ScrollPanelWidget)
+		pos, isPos := value.([]float64)
+		if !ok || !isPos || len(pos) != 2 {
+			return
+		}
+		x, y := pos[0], pos[1]
+		if property == "scrollBy" {
+			x += panel.ScrollX
+			y += panel.ScrollY
+		}
+		g.dispatcher.ScrollTo(panel, x, y)
+	}
+}
+
 // findWidgetByID 递归查找控件
 func (g *Game) findWidgetByID(id string) ui.Widget {
 	for _, widget := range g.widgets {
@@ -306,25 +376,45 @@ func (g *Game) findWidgetRecursive(widget ui.Widget, id string) ui.Widget {
 	return nil
 }
 
+// applyScaleMode 设置缩放模式（命令行参数优先于fileMode）
+func (g *Game) applyScaleMode(fileMode string) error {
+	name := g.scaleMode
+	if name == "" {
+		name = fileMode
+	}
+	if name == "" {
+		return nil
+	}
+	mode, ok := ui.ParseScaleMode(name)
+	if !ok {
+		return fmt.Errorf("unknown scale mode: %s", name)
+	}
+	log.Printf("Scale mode: %s", mode)
+	g.scaler.Mode = mode
+	return nil
+}
+
 // Draw 绘制游戏画面
 func (g *Game) Draw(screen *ebiten.Image) {
-	// 清空屏幕
+	// 清空屏幕（缩放模式下的黑边区域）
 	screen.Fill(color.RGBA{R: 30, G: 30, B: 30, A: 255})
 
-	// 按z-index排序控件（z-index小的先绘制，在底层）
-	sortedWidgets := make([]ui.Widget, len(g.widgets))
-	copy(sortedWidgets, g.widgets)
-	sort.Slice(sortedWidgets, func(i, j int) bool {
-		return sortedWidgets[i].GetZIndex() < sortedWidgets[j].GetZIndex()
-	})
-
-	// 绘制所有控件
-	screenWidth := screen.Bounds().Dx()
-	screenHeight := screen.Bounds().Dy()
-	for _, widget := range sortedWidgets {
-		widget.Draw(screen, 0, 0, screenWidth, screenHeight)
+	// 控件绘制在设计坐标的画布上，最后按缩放模式绘制到屏幕
+	canvas := g.scaler.Canvas(screen)
+	if canvas != screen {
+		canvas.Fill(color.RGBA{R: 30, G: 30, B: 30, A: 255})
 	}
 
+	// 按层叠顺序绘制所有控件（与命中测试使用相同的z-index顺序，覆盖层控件最后绘制）
+	// 外观没有变化的容器子树直接合成上一帧缓存的离屏图像
+	viewportWidth, viewportHeight := g.scaler.Viewport()
+	g.renderCache.Draw(canvas, g.widgets, viewportWidth, viewportHeight)
+
+	// 覆盖层：弹出层（下拉列表等）、模态层及其遮罩、拖拽影像、提示层和调试层
+	g.dispatcher.DrawOverlay(canvas)
+
+	g.scaler.Present(screen, canvas)
+
 	// 显示FPS
 	ebitenutil.DebugPrint(screen, fmt.Sprintf("FPS: %.2f", ebiten.ActualTPS()))
 }
@@ -338,9 +428,15 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 		g.currentHeight = outsideHeight
 	}
 
-	// 返回当前窗口尺寸作为逻辑分辨率（不缩放）
-	// 锚点系统会根据screen的尺寸自动计算控件位置
-	return outsideWidth, outsideHeight
+	// 按设备缩放系数渲染到物理像素，HiDPI屏幕上保持清晰
+	g.scaler.DeviceScale = ebiten.DeviceScaleFactor()
+	screenWidth, screenHeight := g.scaler.Layout(outsideWidth, outsideHeight)
+
+	// 命中测试使用与绘制相同的视口尺寸
+	// 不缩放时视口为窗口尺寸，锚点系统根据视口自动计算控件位置；其他模式下视口为设计尺寸
+	g.dispatcher.SetViewport(g.scaler.Viewport())
+
+	return screenWidth, screenHeight
 }
 
 func main() {
@@ -348,8 +444,12 @@ func main() {
 	// 静默模式
 	var silentMode bool
 	var layoutFile string
+	var scaleMode string
+	var themeFiles string
 	flag.BoolVar(&silentMode, "silent", false, "Enable silent mode (suppress logs)")
 	flag.StringVar(&layoutFile, "layout", "", "Path to UI layout file (.ui or .json)")
+	flag.StringVar(&scaleMode, "scale", "", "Scale mode relative to the layout size: none, fit, fill, stretch, integer")
+	flag.StringVar(&themeFiles, "theme", "", "Comma-separated theme files; F2 switches between them at runtime")
 	flag.Parse()
 
 	if silentMode {
@@ -363,7 +463,11 @@ func main() {
 	}
 
 	// 创建游戏实例
-	game, err := NewGame(layoutFile)
+	var themes []string
+	if themeFiles != "" {
+		themes = strings.Split(themeFiles, ",")
+	}
+	game, err := NewGame(layoutFile, scaleMode, themes)
 	if err != nil {
 		log.Fatalf("Failed to create game: %v", err)
 	}
@@ -0,0 +1,355 @@
+package ui
+
+import (
+	"image"
+	"math"
+)
+
+// FlexBasisContent 使用内容尺寸作为主轴基础尺寸
+const FlexBasisContent = -1
+
+// FlexItem 弹性布局子项属性
+type FlexItem struct {
+	Grow      float64
+	Shrink    float64
+	Basis     int
+	AlignSelf string
+}
+
+// FlexOptions 弹性布局容器参数
+type FlexOptions struct {
+	Direction  string // "row" 或 "column"
+	Gap        int
+	Justify    string
+	AlignItems string
+	Wrap       bool
+}
+
+// layoutTarget 可以由父容器设置布局边界的控件（所有嵌入BaseWidget的控件都实现了该接口）
+type layoutTarget interface {
+	SetLayoutBounds(rect image.Rectangle)
+	ClearLayoutBounds()
+}
+
+// sizeLimiter 具有最小/最大尺寸约束的控件（所有嵌入BaseWidget的控件都实现了该接口）
+type sizeLimiter interface {
+	sizeLimits() (minSize, maxSize image.Point)
+}
+
+// contentSizer 能够根据内容（例如文本）计算首选尺寸的控件
+type contentSizer interface {
+	ContentSize() (int, int)
+}
+
+// flexEntry 参与布局的子控件
+type flexEntry struct {
+	target layoutTarget
+	item   FlexItem
+	margin Spacing
+	basis  float64 // 主轴基础尺寸（含外边距，已按最小/最大尺寸约束）
+	cross  float64 // 交叉轴首选尺寸（含外边距）
+	main   float64 // 计算后的主轴尺寸（含外边距）
+
+	// 外边距和控件的最小/最大尺寸约束（不含外边距，为0的约束不生效）
+	marginMain, marginCross float64
+	minMain, maxMain        float64
+	minCross, maxCross      float64
+}
+
+// clampMain 按子控件的最小/最大尺寸约束限制主轴尺寸（含外边距）
+func (e *flexEntry) clampMain(size float64) float64 {
+	return clampFlexSize(size, e.marginMain, e.minMain, e.maxMain)
+}
+
+// clampCross 按子控件的最小/最大尺寸约束限制交叉轴尺寸（含外边距）
+func (e *flexEntry) clampCross(size float64) float64 {
+	return clampFlexSize(size, e.marginCross, e.minCross, e.maxCross)
+}
+
+// clampFlexSize 将扣除外边距后的尺寸限制在[minSize, maxSize]内且不小于0
+func clampFlexSize(size, margin, minSize, maxSize float64) float64 {
+	size -= margin
+	if maxSize > 0 {
+		size = math.Min(size, maxSize)
+	}
+	if minSize > 0 {
+		size = math.Max(size, minSize)
+	}
+	return math.Max(size, 0) + margin
+}
+
+// clearLayoutBounds 清除子控件的布局边界（容器切换回绝对定位时使用）
+func clearLayoutBounds(children []Widget) {
+	for _, child := range children {
+		if t, ok := child.(layoutTarget); ok {
+			t.ClearLayoutBounds()
+		}
+	}
+}
+
+// preferredSize 获取控件的首选尺寸
+// 优先使用测量阶段的结果，否则设计尺寸为0的方向使用内容尺寸
+func preferredSize(widget Widget) (int, int) {
+	if m, ok := widget.(interface{ MeasuredSize() (image.Point, bool) }); ok {
+		if size, ok := m.MeasuredSize(); ok {
+			return size.X, size.Y
+		}
+	}
+	width, height := widget.GetWidth(), widget.GetHeight()
+	if width > 0 && height > 0 {
+		return width, height
+	}
+	if c, ok := widget.(contentSizer); ok {
+		cw, ch := c.ContentSize()
+		if width <= 0 {
+			width = cw
+		}
+		if height <= 0 {
+			height = ch
+		}
+	}
+	return width, height
+}
+
+// LayoutFlex 按弹性布局排列子控件
+// 计算结果以相对容器左上角的局部边界写入每个可见子控件；不可见的子控件不占用空间
+func LayoutFlex(children []Widget, opts FlexOptions, width, height int) {
+	column := opts.Direction == "column"
+	mainSize, crossSize := float64(width), float64(height)
+	if column {
+		mainSize, crossSize = crossSize, mainSize
+	}
+	gap := float64(opts.Gap)
+
+	entries := newFlexEntries(children, column)
+	lines := splitFlexLines(entries, opts.Wrap, mainSize, gap)
+
+	crossPos := 0.0
+	for _, line := range lines {
+		// 单行时行高等于容器高度，换行时取该行子控件的最大交叉尺寸
+		lineCross := crossSize
+		if opts.Wrap {
+			lineCross = 0
+			for _, e := range line {
+				lineCross = math.Max(lineCross, e.cross)
+			}
+		}
+
+		free := resolveFlexLine(line, mainSize, gap)
+		pos, spacing := justifyOffsets(opts.Justify, free, len(line))
+		for _, e := range line {
+			crossLen, crossOffset := alignCross(e, opts.AlignItems, lineCross)
+
+			mainStart := math.Round(pos)
+			mainEnd := math.Round(pos + e.main)
+			crossStart := math.Round(crossPos + crossOffset)
+			crossEnd := math.Round(crossPos + crossOffset + crossLen)
+
+			rect := image.Rect(int(mainStart), int(crossStart), int(mainEnd), int(crossEnd))
+			if column {
+				rect = image.Rect(int(crossStart), int(mainStart), int(crossEnd), int(mainEnd))
+			}
+			e.target.SetLayoutBounds(e.margin.Inset(rect))
+
+			pos += e.main + gap + spacing
+		}
+		crossPos += lineCross + gap
+	}
+}
+
+// newFlexEntries 收集参与布局的可见子控件及其主轴基础尺寸和交叉轴首选尺寸
+// 子控件的外边距计入尺寸，排列后再从分配到的区域中扣除
+func newFlexEntries(children []Widget, column bool) []*flexEntry {
+	var entries []*flexEntry
+	for _, child := range children {
+		target, ok := child.(layoutTarget)
+		if !ok || !child.IsVisible() {
+			continue
+		}
+		entry := &flexEntry{target: target, margin: child.GetMargin()}
+		if f, ok := child.(interface{ GetFlexItem() FlexItem }); ok {
+			entry.item = f.GetFlexItem()
+		}
+
+		pw, ph := preferredSize(child)
+		if column {
+			pw, ph = ph, pw
+		}
+		entry.basis = float64(pw)
+		entry.cross = float64(ph)
+		switch {
+		case entry.item.Basis > 0:
+			entry.basis = float64(entry.item.Basis)
+		case entry.item.Basis == FlexBasisContent:
+			if c, ok := child.(contentSizer); ok {
+				cw, ch := c.ContentSize()
+				if column {
+					cw = ch
+				}
+				entry.basis = float64(cw)
+			}
+		}
+
+		marginMain, marginCross := entry.margin.Horizontal(), entry.margin.Vertical()
+		if column {
+			marginMain, marginCross = marginCross, marginMain
+		}
+		entry.marginMain, entry.marginCross = float64(marginMain), float64(marginCross)
+		if l, ok := child.(sizeLimiter); ok {
+			minSize, maxSize := l.sizeLimits()
+			if column {
+				minSize.X, minSize.Y = minSize.Y, minSize.X
+				maxSize.X, maxSize.Y = maxSize.Y, maxSize.X
+			}
+			entry.minMain, entry.maxMain = float64(minSize.X), float64(maxSize.X)
+			entry.minCross, entry.maxCross = float64(minSize.Y), float64(maxSize.Y)
+		}
+		entry.basis = entry.clampMain(entry.basis + entry.marginMain)
+		entry.cross += entry.marginCross
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// MeasureFlex 计算弹性布局容器的首选尺寸（所有子控件排成一行时的尺寸）
+func MeasureFlex(children []Widget, opts FlexOptions) image.Point {
+	column := opts.Direction == "column"
+	entries := newFlexEntries(children, column)
+
+	main, cross := 0.0, 0.0
+	for i, e := range entries {
+		main += e.basis
+		if i > 0 {
+			main += float64(opts.Gap)
+		}
+		cross = math.Max(cross, e.cross)
+	}
+	if column {
+		return image.Pt(int(math.Ceil(cross)), int(math.Ceil(main)))
+	}
+	return image.Pt(int(math.Ceil(main)), int(math.Ceil(cross)))
+}
+
+// splitFlexLines 按主轴尺寸分行（不换行时所有子控件在同一行）
+func splitFlexLines(entries []*flexEntry, wrap bool, mainSize, gap float64) [][]*flexEntry {
+	if !wrap {
+		return [][]*flexEntry{entries}
+	}
+
+	var lines [][]*flexEntry
+	var line []*flexEntry
+	used := 0.0
+	for _, e := range entries {
+		next := used + e.basis
+		if len(line) > 0 {
+			next += gap
+		}
+		if len(line) > 0 && next > mainSize {
+			lines = append(lines, line)
+			line = nil
+			next = e.basis
+		}
+		line = append(line, e)
+		used = next
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// resolveFlexLine 按grow/shrink计算一行中各子控件的主轴尺寸，返回剩余空间
+// 分配后超出最小/最大尺寸约束的子控件固定为约束后的尺寸，剩余空间重新分配给其余可伸缩的子控件
+func resolveFlexLine(line []*flexEntry, mainSize, gap float64) float64 {
+	frozen := make([]bool, len(line))
+	for _, e := range line {
+		e.main = e.basis
+	}
+
+	for {
+		used := gap * float64(max(len(line)-1, 0))
+		totalGrow, totalShrink := 0.0, 0.0
+		for i, e := range line {
+			used += e.main
+			if !frozen[i] {
+				totalGrow += e.item.Grow
+				totalShrink += e.item.Shrink * e.basis
+			}
+		}
+		free := mainSize - used
+
+		var share func(e *flexEntry) float64
+		switch {
+		case free > 0 && totalGrow > 0:
+			share = func(e *flexEntry) float64 { return free * e.item.Grow / totalGrow }
+		case free < 0 && totalShrink > 0:
+			share = func(e *flexEntry) float64 { return free * e.item.Shrink * e.basis / totalShrink }
+		default:
+			return free
+		}
+
+		clamped := false
+		for i, e := range line {
+			if frozen[i] {
+				continue
+			}
+			want := e.main + share(e)
+			if size := e.clampMain(want); math.Abs(size-want) > 1e-9 {
+				e.main = size
+				frozen[i] = true
+				clamped = true
+			}
+		}
+		if !clamped {
+			for i, e := range line {
+				if !frozen[i] {
+					e.main += share(e)
+				}
+			}
+			return 0
+		}
+	}
+}
+
+// justifyOffsets 根据主轴对齐方式计算起始位置和子控件之间的额外间距
+func justifyOffsets(justify string, free float64, count int) (float64, float64) {
+	if free <= 0 || count == 0 {
+		return 0, 0
+	}
+	switch justify {
+	case "end":
+		return free, 0
+	case "center":
+		return free / 2, 0
+	case "space-between":
+		if count == 1 {
+			return 0, 0
+		}
+		return 0, free / float64(count-1)
+	case "space-around":
+		spacing := free / float64(count)
+		return spacing / 2, spacing
+	case "space-evenly":
+		spacing := free / float64(count+1)
+		return spacing, spacing
+	}
+	return 0, 0
+}
+
+// alignCross 计算子控件在行内的交叉轴尺寸和偏移
+func alignCross(e *flexEntry, alignItems string, lineCross float64) (float64, float64) {
+	align := alignItems
+	if e.item.AlignSelf != "" {
+		align = e.item.AlignSelf
+	}
+
+	switch align {
+	case "stretch":
+		return e.clampCross(lineCross), 0
+	case "center":
+		return e.cross, (lineCross - e.cross) / 2
+	case "end":
+		return e.cross, lineCross - e.cross
+	}
+	return e.cross, 0
+}
@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"image"
+	"testing"
+)
+
+// newFlexChild 创建指定设计尺寸的子控件
+func newFlexChild(id string, width, height int) *PanelWidget {
+	p := NewPanel(id)
+	p.Width, p.Height = width, height
+	return p
+}
+
+// layoutRect 获取子控件的布局边界
+func layoutRect(t *testing.T, w Widget) image.Rectangle {
+	t.Helper()
+	rect, ok := w.(interface {
+		GetLayoutBounds() (image.Rectangle, bool)
+	}).GetLayoutBounds()
+	if !ok {
+		t.Fatalf("%s has no layout bounds", w.GetID())
+	}
+	return rect
+}
+
+// TestFlexLayout_Justify 测试主轴对齐方式
+func TestFlexLayout_Justify(t *testing.T) {
+	tests := []struct {
+		justify string
+		wantX   []int
+	}{
+		{"start", []int{0, 60}},
+		{"end", []int{140, 200}},
+		{"center", []int{70, 130}},
+		{"space-between", []int{0, 200}},
+		{"space-around", []int{35, 165}},
+		{"space-evenly", []int{47, 153}},
+	}
+	for _, tt := range tests {
+		a := newFlexChild("a", 50, 20)
+		b := newFlexChild("b", 50, 20)
+		LayoutFlex([]Widget{a, b}, FlexOptions{Gap: 10, Justify: tt.justify}, 250, 100)
+
+		gotX := []int{layoutRect(t, a).Min.X, layoutRect(t, b).Min.X}
+		if gotX[0] != tt.wantX[0] || gotX[1] != tt.wantX[1] {
+			t.Errorf("%s: expected x %v, got %v", tt.justify, tt.wantX, gotX)
+		}
+	}
+}
+
+// TestFlexLayout_GrowShrink 测试按比例分配剩余空间和收缩
+func TestFlexLayout_GrowShrink(t *testing.T) {
+	a := newFlexChild("a", 50, 20)
+	b := newFlexChild("b", 50, 20)
+	b.FlexGrow = 1
+	c := newFlexChild("c", 50, 20)
+	c.FlexGrow = 3
+	LayoutFlex([]Widget{a, b, c}, FlexOptions{}, 230, 100)
+
+	if got := layoutRect(t, a).Dx(); got != 50 {
+		t.Errorf("a: expected width 50, got %d", got)
+	}
+	if got := layoutRect(t, b); got != image.Rect(50, 0, 120, 20) {
+		t.Errorf("b: expected (50,0)-(120,20), got %v", got)
+	}
+	if got := layoutRect(t, c); got != image.Rect(120, 0, 230, 20) {
+		t.Errorf("c: expected (120,0)-(230,20), got %v", got)
+	}
+
+	// 空间不足时按shrink*basis收缩，shrink为0的子控件保持原尺寸
+	a = newFlexChild("a", 100, 20)
+	b = newFlexChild("b", 100, 20)
+	b.FlexShrink = 1
+	c = newFlexChild("c", 200, 20)
+	c.FlexShrink = 1
+	LayoutFlex([]Widget{a, b, c}, FlexOptions{}, 310, 100)
+	widths := []int{layoutRect(t, a).Dx(), layoutRect(t, b).Dx(), layoutRect(t, c).Dx()}
+	if widths[0] != 100 || widths[1] != 70 || widths[2] != 140 {
+		t.Errorf("Expected widths [100 70 140], got %v", widths)
+	}
+}
+
+// TestFlexLayout_GrowShrinkRespectsConstraints 测试伸缩结果受最小/最大尺寸约束，多出的空间分配给其余子控件
+func TestFlexLayout_GrowShrinkRespectsConstraints(t *testing.T) {
+	a := newFlexChild("a", 50, 20)
+	a.FlexGrow = 1
+	a.MaxWidth = 80
+	a.MaxHeight = 30
+	b := newFlexChild("b", 50, 20)
+	b.FlexGrow = 1
+	LayoutFlex([]Widget{a, b}, FlexOptions{AlignItems: "stretch"}, 300, 100)
+
+	if got := layoutRect(t, a); got != image.Rect(0, 0, 80, 30) {
+		t.Errorf("a: expected width and stretched height clamped to (0,0)-(80,30), got %v", got)
+	}
+	if got := layoutRect(t, b); got != image.Rect(80, 0, 300, 100) {
+		t.Errorf("b: expected remaining space (80,0)-(300,100), got %v", got)
+	}
+
+	// 所有可伸缩的子控件都达到最大尺寸时，剩余空间按主轴对齐方式分配
+	LayoutFlex([]Widget{a}, FlexOptions{Justify: "end"}, 200, 100)
+	if got := layoutRect(t, a); got != image.Rect(120, 0, 200, 20) {
+		t.Errorf("a: expected (120,0)-(200,20), got %v", got)
+	}
+
+	// 收缩不小于最小尺寸
+	a = newFlexChild("a", 100, 20)
+	a.FlexShrink = 1
+	a.MinWidth = 90
+	b = newFlexChild("b", 100, 20)
+	b.FlexShrink = 1
+	LayoutFlex([]Widget{a, b}, FlexOptions{}, 150, 100)
+	if widths := []int{layoutRect(t, a).Dx(), layoutRect(t, b).Dx()}; widths[0] != 90 || widths[1] != 60 {
+		t.Errorf("Expected widths [90 60], got %v", widths)
+	}
+}
+
+// TestFlexLayout_ColumnAlign 测试纵向布局的交叉轴对齐
+func TestFlexLayout_ColumnAlign(t *testing.T) {
+	a := newFlexChild("a", 50, 20)
+	b := newFlexChild("b", 50, 20)
+	b.AlignSelf = "center"
+	c := newFlexChild("c", 50, 20)
+	c.AlignSelf = "end"
+	LayoutFlex([]Widget{a, b, c}, FlexOptions{Direction: "column", Gap: 5, AlignItems: "stretch"}, 200, 300)
+
+	if got := layoutRect(t, a); got != image.Rect(0, 0, 200, 20) {
+		t.Errorf("a: expected stretched (0,0)-(200,20), got %v", got)
+	}
+	if got := layoutRect(t, b); got != image.Rect(75, 25, 125, 45) {
+		t.Errorf("b: expected centered (75,25)-(125,45), got %v", got)
+	}
+	if got := layoutRect(t, c); got != image.Rect(150, 50, 200, 70) {
+		t.Errorf("c: expected end (150,50)-(200,70), got %v", got)
+	}
+}
+
+// TestFlexLayout_Wrap 测试换行
+func TestFlexLayout_Wrap(t *testing.T) {
+	var children []Widget
+	for _, id := range []string{"a", "b", "c", "d"} {
+		children = append(children, newFlexChild(id, 60, 30))
+	}
+	children[3].(*PanelWidget).Height = 40
+	LayoutFlex(children, FlexOptions{Gap: 10, Wrap: true}, 150, 200)
+
+	want := []image.Rectangle{
+		image.Rect(0, 0, 60, 30),
+		image.Rect(70, 0, 130, 30),
+		image.Rect(0, 40, 60, 70),
+		image.Rect(70, 40, 130, 80),
+	}
+	for i, child := range children {
+		if got := layoutRect(t, child); got != want[i] {
+			t.Errorf("%s: expected %v, got %v", child.GetID(), want[i], got)
+		}
+	}
+}
+
+// TestFlexLayout_ContentSize 测试内容尺寸随文本变化重新计算，不可见子控件不占空间
+func TestFlexLayout_ContentSize(t *testing.T) {
+	panel := NewPanel("row")
+	panel.Layout = "flex"
+	panel.Gap = 4
+
+	label := NewLabel("label")
+	label.Text = "Hi"
+	label.FlexBasis = FlexBasisContent
+	hidden := newFlexChild("hidden", 100, 20)
+	hidden.Visible = false
+	btn := NewButton("btn")
+	btn.Width = 80
+	panel.AddChild(label)
+	panel.AddChild(hidden)
+	panel.AddChild(btn)
+
+	panel.LayoutChildren(400, 40)
+	short := layoutRect(t, label).Dx()
+	if got := layoutRect(t, btn).Min.X; got != short+4 {
+		t.Errorf("Expected button right after label at %d, got %d", short+4, got)
+	}
+
+	label.Text = "A much longer caption"
+	panel.LayoutChildren(400, 40)
+	long := layoutRect(t, label).Dx()
+	if long <= short {
+		t.Fatalf("Expected label to grow with its text, got %d -> %d", short, long)
+	}
+	if got := layoutRect(t, btn).Min.X; got != long+4 {
+		t.Errorf("Expected button to move to %d, got %d", long+4, got)
+	}
+}
+
+// TestFlexLayout_HitTest 测试命中测试使用布局后的边界
+func TestFlexLayout_HitTest(t *testing.T) {
+	panel := NewPanel("row")
+	panel.X, panel.Y = 100, 100
+	panel.Width, panel.Height = 300, 50
+	panel.Layout = "flex"
+	panel.Justify = "end"
+
+	btn := NewButton("btn")
+	btn.X, btn.Y = 0, 0
+	btn.Width, btn.Height = 80, 30
+	panel.AddChild(btn)
+
+	if hit := HitTest([]Widget{panel}, 800, 600, 110, 110); hit != nil {
+		t.Errorf("Expected no hit at the design position, got %s", hit.GetID())
+	}
+	if hit := HitTest([]Widget{panel}, 800, 600, 390, 110); hit != btn {
+		t.Errorf("Expected btn at its flex position, got %v", hit)
+	}
+
+	// 切换回绝对定位后恢复设计位置
+	panel.Layout = ""
+	if hit := HitTest([]Widget{panel}, 800, 600, 110, 110); hit != btn {
+		t.Errorf("Expected btn at its design position after clearing layout, got %v", hit)
+	}
+}
+
+// TestFlexLayout_Margins 测试外边距计入子控件的占用空间
+func TestFlexLayout_Margins(t *testing.T) {
+	a := newFlexChild("a", 50, 20)
+	a.Margin = Spacing{Top: 2, Right: 10, Bottom: 2, Left: 5}
+	b := newFlexChild("b", 50, 20)
+	b.FlexGrow = 1
+	b.Margin = Spacing{Left: 5}
+	LayoutFlex([]Widget{a, b}, FlexOptions{AlignItems: "stretch"}, 200, 40)
+
+	if got, want := layoutRect(t, a), image.Rect(5, 2, 55, 38); got != want {
+		t.Errorf("expected a %v, got %v", want, got)
+	}
+	if got, want := layoutRect(t, b), image.Rect(70, 0, 200, 40); got != want {
+		t.Errorf("expected b %v, got %v", want, got)
+	}
+
+	// 测量时同样计入外边距
+	if got, want := MeasureFlex([]Widget{a, b}, FlexOptions{}), image.Pt(120, 24); got != want {
+		t.Errorf("expected measured size %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,244 @@
+package ui
+
+import (
+	"sort"
+	"time"
+)
+
+// focusReceiver 需要感知焦点变化的控件（例如TextInput切换编辑状态）
+type focusReceiver interface {
+	SetFocused(focused bool)
+}
+
+// IsFocusable 判断控件当前是否可以获得焦点
+// 可见、可交互、已启用，并且是可聚焦的控件类型或显式设置了正的tabIndex
+func IsFocusable(widget Widget) bool {
+	if widget == nil || !widget.IsVisible() || !widget.IsInteractive() || !isWidgetEnabled(widget) {
+		return false
+	}
+
+	switch widget.GetType() {
+	case TypeButton, TypeTextInput, TypeCheckBox, TypeRadioButton, TypeSlider,
+		TypeComboBox, TypeListView, TypeGridView, TypeTableView:
+		return true
+	}
+	return tabIndexOf(widget) > 0
+}
+
+// tabIndexOf 获取控件的tabIndex（未嵌入BaseWidget的控件视为0）
+func tabIndexOf(widget Widget) int {
+	if t, ok := widget.(interface{ GetTabIndex() int }); ok {
+		return t.GetTabIndex()
+	}
+	return 0
+}
+
+// FocusManager 焦点管理器
+// 持有唯一的焦点控件，负责Tab顺序切换并向事件队列推送focus/blur事件
+type FocusManager struct {
+	eventQueue *EventQueue
+	roots      []Widget
+	focused    Widget
+
+	now func() time.Time
+}
+
+// NewFocusManager 创建焦点管理器
+func NewFocusManager(eventQueue *EventQueue) *FocusManager {
+	return &FocusManager{
+		eventQueue: eventQueue,
+		now:        time.Now,
+	}
+}
+
+// SetRoots 设置参与焦点导航的顶层控件（会静默清除当前焦点）
+func (f *FocusManager) SetRoots(widgets []Widget) {
+	f.roots = widgets
+	if f.focused != nil {
+		setWidgetState(f.focused, WidgetStateFocused, false, nil)
+		setWidgetFocused(f.focused, false)
+		f.focused = nil
+	}
+}
+
+// GetFocused 获取当前焦点控件
+func (f *FocusManager) GetFocused() Widget {
+	return f.focused
+}
+
+// Focus 将焦点移动到指定控件
+// 控件不可聚焦时返回false，焦点保持不变
+func (f *FocusManager) Focus(widget Widget) bool {
+	if !IsFocusable(widget) {
+		return false
+	}
+	if widget == f.focused {
+		return true
+	}
+
+	previous := f.focused
+	if previous != nil {
+		f.push(EventBlur, previous, widget)
+		setWidgetState(previous, WidgetStateFocused, false, f.pushEvent)
+		setWidgetFocused(previous, false)
+	}
+
+	f.focused = widget
+	f.push(EventFocus, widget, previous)
+	setWidgetState(widget, WidgetStateFocused, true, f.pushEvent)
+	setWidgetFocused(widget, true)
+	return true
+}
+
+// FocusByID 按ID查找控件并设置焦点
+func (f *FocusManager) FocusByID(id string) bool {
+	return f.Focus(findWidgetByID(f.roots, id))
+}
+
+// Blur 清除当前焦点
+func (f *FocusManager) Blur() {
+	if f.focused == nil {
+		return
+	}
+	previous := f.focused
+	f.focused = nil
+	f.push(EventBlur, previous, nil)
+	setWidgetState(previous, WidgetStateFocused, false, f.pushEvent)
+	setWidgetFocused(previous, false)
+}
+
+// BlurByID 当指定控件持有焦点时清除焦点
+func (f *FocusManager) BlurByID(id string) {
+	if f.focused != nil && f.focused.GetID() == id {
+		f.Blur()
+	}
+}
+
+// Validate 焦点控件变为不可聚焦（隐藏、禁用等）时清除焦点，每帧调用一次
+func (f *FocusManager) Validate() {
+	if f.focused != nil && !IsFocusable(f.focused) {
+		f.Blur()
+	}
+}
+
+// FocusNext 按Tab顺序移动到下一个控件（循环）
+func (f *FocusManager) FocusNext() Widget {
+	return f.move(1)
+}
+
+// FocusPrevious 按Tab顺序移动到上一个控件（循环）
+func (f *FocusManager) FocusPrevious() Widget {
+	return f.move(-1)
+}
+
+// move 在Tab顺序中移动焦点
+func (f *FocusManager) move(step int) Widget {
+	order := f.TabOrder()
+	if len(order) == 0 {
+		return f.focused
+	}
+
+	index := -1
+	for i, w := range order {
+		if w == f.focused {
+			index = i
+			break
+		}
+	}
+
+	var next int
+	switch {
+	case index >= 0:
+		next = (index + step + len(order)) % len(order)
+	case step > 0:
+		next = 0
+	default:
+		next = len(order) - 1
+	}
+
+	f.Focus(order[next])
+	return f.focused
+}
+
+// TabOrder 返回Tab导航顺序
+// 正的tabIndex按升序排在前面，其余按文档顺序；负的tabIndex不参与Tab导航
+func (f *FocusManager) TabOrder() []Widget {
+	var candidates []Widget
+	collectFocusable(f.roots, &candidates)
+
+	order := candidates[:0]
+	for _, w := range candidates {
+		if tabIndexOf(w) >= 0 {
+			order = append(order, w)
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := tabIndexOf(order[i]), tabIndexOf(order[j])
+		if a > 0 && b > 0 {
+			return a < b
+		}
+		return a > 0 && b == 0
+	})
+	return order
+}
+
+// collectFocusable 按文档顺序收集可聚焦控件（跳过隐藏的子树）
+func collectFocusable(widgets []Widget, out *[]Widget) {
+	for _, w := range widgets {
+		if !w.IsVisible() {
+			continue
+		}
+		if IsFocusable(w) {
+			*out = append(*out, w)
+		}
+		collectFocusable(w.GetChildren(), out)
+	}
+}
+
+// push 推送焦点事件
+func (f *FocusManager) push(eventType EventType, widget, related Widget) {
+	if f.eventQueue == nil {
+		return
+	}
+	data := make(map[string]interface{}, 1)
+	if related != nil {
+		data["relatedTarget"] = related.GetID()
+	}
+	f.eventQueue.Push(WidgetEvent{
+		Type:      eventType,
+		WidgetID:  widget.GetID(),
+		Widget:    widget,
+		Timestamp: f.now(),
+		Data:      data,
+	})
+}
+
+// pushEvent 补全时间戳并推送事件（用于焦点状态的statechange事件）
+func (f *FocusManager) pushEvent(event WidgetEvent) {
+	if f.eventQueue == nil {
+		return
+	}
+	event.Timestamp = f.now()
+	f.eventQueue.Push(event)
+}
+
+// setWidgetFocused 通知控件焦点变化（在焦点状态更新之后调用）
+func setWidgetFocused(widget Widget, focused bool) {
+	if r, ok := widget.(focusReceiver); ok {
+		r.SetFocused(focused)
+	}
+}
+
+// findWidgetByID 在控件树中按ID查找控件
+func findWidgetByID(widgets []Widget, id string) Widget {
+	for _, w := range widgets {
+		if w.GetID() == id {
+			return w
+		}
+		if found := findWidgetByID(w.GetChildren(), id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// newFocusFixture 创建包含多个可聚焦控件的面板
+// 文档顺序：input1, btn, label, input2
+func newFocusFixture() (*PanelWidget, *TextInputWidget, *ButtonWidget, *TextInputWidget) {
+	panel := NewPanel("panel")
+	input1 := NewTextInput("input1")
+	input1.X, input1.Y = 10, 10
+	btn := NewButton("btn")
+	btn.X, btn.Y = 10, 60
+	label := NewLabel("label")
+	label.X, label.Y = 10, 110
+	input2 := NewTextInput("input2")
+	input2.X, input2.Y = 10, 160
+
+	panel.AddChild(input1)
+	panel.AddChild(btn)
+	panel.AddChild(label)
+	panel.AddChild(input2)
+	return panel, input1, btn, input2
+}
+
+func widgetIDs(widgets []Widget) []string {
+	ids := make([]string, len(widgets))
+	for i, w := range widgets {
+		ids[i] = w.GetID()
+	}
+	return ids
+}
+
+// TestFocusManager_SingleFocus 测试同一时间只有一个控件获得焦点
+func TestFocusManager_SingleFocus(t *testing.T) {
+	panel, input1, _, input2 := newFocusFixture()
+	eq := NewEventQueue()
+	defer eq.Close()
+
+	fm := NewFocusManager(eq)
+	fm.SetRoots([]Widget{panel})
+
+	fm.Focus(input1)
+	fm.Focus(input2)
+
+	if input1.Focused {
+		t.Error("input1 should have lost focus")
+	}
+	if !input2.Focused || !input2.HasState(StateFocused) {
+		t.Error("input2 should be focused and editing")
+	}
+	if fm.GetFocused() != input2 {
+		t.Errorf("Expected input2 focused, got %v", fm.GetFocused())
+	}
+
+	events := drainEvents(eq)
+	var got []string
+	for _, e := range events {
+		got = append(got, string(e.Type)+":"+e.WidgetID)
+	}
+	want := []string{"focus:input1", "statechange:input1", "blur:input1", "statechange:input1", "focus:input2", "statechange:input2"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Event %d: expected %s, got %s", i, want[i], got[i])
+		}
+	}
+	if events[4].Data["relatedTarget"] != "input1" {
+		t.Errorf("Expected relatedTarget input1, got %v", events[4].Data["relatedTarget"])
+	}
+}
+
+// TestFocusManager_TabOrder 测试Tab顺序（正tabIndex优先，其余按文档顺序）
+func TestFocusManager_TabOrder(t *testing.T) {
+	panel, input1, btn, input2 := newFocusFixture()
+	fm := NewFocusManager(nil)
+	fm.SetRoots([]Widget{panel})
+
+	// 文档顺序，标签不可聚焦
+	order := widgetIDs(fm.TabOrder())
+	want := []string{"input1", "btn", "input2"}
+	if len(order) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("Position %d: expected %s, got %s", i, want[i], order[i])
+		}
+	}
+
+	// 显式tabIndex
+	input2.TabIndex = 1
+	btn.TabIndex = 2
+	input1.TabIndex = -1
+	order = widgetIDs(fm.TabOrder())
+	want = []string{"input2", "btn"}
+	if len(order) != len(want) || order[0] != want[0] || order[1] != want[1] {
+		t.Errorf("Expected %v, got %v", want, order)
+	}
+}
+
+// TestFocusManager_NextPrevious 测试循环切换焦点并跳过禁用和隐藏控件
+func TestFocusManager_NextPrevious(t *testing.T) {
+	panel, input1, btn, input2 := newFocusFixture()
+	fm := NewFocusManager(nil)
+	fm.SetRoots([]Widget{panel})
+
+	if fm.FocusNext() != input1 {
+		t.Error("First Tab should focus input1")
+	}
+	if fm.FocusNext() != btn {
+		t.Error("Second Tab should focus btn")
+	}
+	if fm.FocusPrevious() != input1 {
+		t.Error("Shift+Tab should go back to input1")
+	}
+	if fm.FocusPrevious() != input2 {
+		t.Error("Shift+Tab should wrap to input2")
+	}
+
+	btn.SetEnabled(false)
+	fm.Focus(input1)
+	if fm.FocusNext() != input2 {
+		t.Error("Tab should skip disabled btn")
+	}
+
+	// 焦点控件被隐藏后Validate会清除焦点
+	input2.Visible = false
+	fm.Validate()
+	if fm.GetFocused() != nil {
+		t.Error("Hidden widget should lose focus")
+	}
+	if input2.Focused {
+		t.Error("input2.Focused should be false after validate")
+	}
+}
+
+// TestFocusManager_ByID 测试脚本命令使用的按ID聚焦和失焦
+func TestFocusManager_ByID(t *testing.T) {
+	panel, input1, _, _ := newFocusFixture()
+	fm := NewFocusManager(nil)
+	fm.SetRoots([]Widget{panel})
+
+	if !fm.FocusByID("input1") || !input1.Focused {
+		t.Error("FocusByID should focus input1")
+	}
+	if fm.FocusByID("label") {
+		t.Error("Label should not be focusable")
+	}
+	if fm.FocusByID("missing") {
+		t.Error("Missing widget should not be focusable")
+	}
+
+	fm.BlurByID("btn")
+	if fm.GetFocused() != input1 {
+		t.Error("BlurByID on another widget should keep focus")
+	}
+	fm.BlurByID("input1")
+	if fm.GetFocused() != nil || input1.Focused {
+		t.Error("BlurByID should clear focus")
+	}
+}
+
+// TestInputDispatcher_ClickAndTabFocus 测试点击聚焦、点击空白失焦以及Tab切换
+func TestInputDispatcher_ClickAndTabFocus(t *testing.T) {
+	panel, input1, btn, input2 := newFocusFixture()
+	src := newMockInputSource()
+	eq := NewEventQueue()
+	defer eq.Close()
+	d := NewInputDispatcher(src, eq)
+	d.SetRoots([]Widget{panel})
+	fm := d.FocusManager()
+
+	click := func(x, y int) {
+		src.x, src.y = x, y
+		src.buttons[ebiten.MouseButtonLeft] = true
+		d.Update()
+		src.buttons[ebiten.MouseButtonLeft] = false
+		d.Update()
+	}
+	press := func(keys ...ebiten.Key) {
+		src.keys = keys
+		d.Update()
+		src.keys = nil
+		d.Update()
+	}
+
+	click(20, 20)
+	if fm.GetFocused() != input1 || !input1.Focused {
+		t.Fatal("Clicking input1 should focus it")
+	}
+
+	click(20, 170)
+	if fm.GetFocused() != input2 || input1.Focused {
+		t.Error("Clicking input2 should move focus from input1")
+	}
+
+	// 点击空白区域失焦
+	click(300, 250)
+	if fm.GetFocused() != nil || input2.Focused {
+		t.Error("Clicking empty area should blur")
+	}
+
+	press(ebiten.KeyTab)
+	if fm.GetFocused() != input1 {
+		t.Errorf("Tab should focus input1, got %v", fm.GetFocused())
+	}
+	press(ebiten.KeyTab)
+	if fm.GetFocused() != btn {
+		t.Errorf("Tab should focus btn, got %v", fm.GetFocused())
+	}
+	press(ebiten.KeyShiftLeft, ebiten.KeyTab)
+	if fm.GetFocused() != input1 {
+		t.Errorf("Shift+Tab should focus input1, got %v", fm.GetFocused())
+	}
+
+	// 键盘事件发往焦点控件
+	drainEvents(eq)
+	press(ebiten.KeyA)
+	for _, e := range drainEvents(eq) {
+		if e.WidgetID != "input1" {
+			t.Errorf("Key event %s sent to %s, expected input1", e.Type, e.WidgetID)
+		}
+	}
+}
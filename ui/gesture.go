@@ -0,0 +1,288 @@
+package ui
+
+import (
+	"math"
+	"time"
+)
+
+// GestureType 手势类型
+type GestureType string
+
+const (
+	GestureTap       GestureType = "tap"
+	GestureDoubleTap GestureType = "doubletap"
+	GestureLongPress GestureType = "longpress"
+	GestureSwipe     GestureType = "swipe"
+	GesturePinch     GestureType = "pinch"
+)
+
+// GestureConfig 手势识别阈值配置
+type GestureConfig struct {
+	TapMaxDuration       time.Duration // 点击最长按下时间
+	TapMaxMovement       float64       // 点击/长按允许的最大移动距离（像素）
+	DoubleTapInterval    time.Duration // 双击两次点击的最大间隔
+	DoubleTapMaxDistance float64       // 双击两次点击位置的最大距离
+	LongPressDuration    time.Duration // 长按触发时间
+	SwipeMinDistance     float64       // 滑动最小距离
+	SwipeMinVelocity     float64       // 滑动最小速度（像素/秒）
+	PinchMinScaleDelta   float64       // 两次缩放事件之间的最小比例变化
+}
+
+// DefaultGestureConfig 返回默认手势配置
+func DefaultGestureConfig() GestureConfig {
+	return GestureConfig{
+		TapMaxDuration:       300 * time.Millisecond,
+		TapMaxMovement:       10,
+		DoubleTapInterval:    300 * time.Millisecond,
+		DoubleTapMaxDistance: 30,
+		LongPressDuration:    500 * time.Millisecond,
+		SwipeMinDistance:     50,
+		SwipeMinVelocity:     300,
+		PinchMinScaleDelta:   0.02,
+	}
+}
+
+// Gesture 识别出的手势
+type Gesture struct {
+	Type GestureType
+	X, Y int // 手势起点（双指缩放为起始中点）
+
+	// 滑动
+	Direction string  // "left", "right", "up", "down"
+	DeltaX    float64 // 起点到终点的位移
+	DeltaY    float64
+	VelocityX float64 // 像素/秒
+	VelocityY float64
+
+	// 缩放
+	Scale   float64 // 相对起始两指距离的比例
+	CenterX int     // 当前两指中点
+	CenterY int
+}
+
+// gestureTouch 单个触点的跟踪状态
+type gestureTouch struct {
+	startX, startY float64
+	x, y           float64
+	startTime      time.Time
+	moved          bool // 超出点击允许的移动范围
+	longPressed    bool // 已触发长按
+	cancelled      bool // 参与了双指缩放，不再识别单指手势
+}
+
+// gesturePinch 双指缩放状态
+type gesturePinch struct {
+	ids            [2]int
+	startDistance  float64
+	lastScale      float64
+	startX, startY int
+}
+
+// GestureRecognizer 手势识别器
+// 由触摸开始/移动/结束轨迹驱动，与输入来源无关，可以使用合成轨迹测试
+type GestureRecognizer struct {
+	config  GestureConfig
+	touches map[int]*gestureTouch
+	order   []int // 按开始顺序排列的触点ID
+	pinch   *gesturePinch
+
+	// 上一次点击（用于双击判断）
+	lastTapTime time.Time
+	lastTapX    float64
+	lastTapY    float64
+	hasLastTap  bool
+}
+
+// NewGestureRecognizer 创建手势识别器
+func NewGestureRecognizer(config GestureConfig) *GestureRecognizer {
+	return &GestureRecognizer{
+		config:  config,
+		touches: make(map[int]*gestureTouch),
+	}
+}
+
+// Config 获取当前配置
+func (r *GestureRecognizer) Config() GestureConfig {
+	return r.config
+}
+
+// SetConfig 修改阈值配置
+func (r *GestureRecognizer) SetConfig(config GestureConfig) {
+	r.config = config
+}
+
+// Begin 触点按下
+func (r *GestureRecognizer) Begin(id, x, y int, t time.Time) []Gesture {
+	r.touches[id] = &gestureTouch{
+		startX:    float64(x),
+		startY:    float64(y),
+		x:         float64(x),
+		y:         float64(y),
+		startTime: t,
+	}
+	r.order = append(r.order, id)
+
+	// 第二个触点按下时开始双指缩放
+	if r.pinch == nil && len(r.order) == 2 {
+		a, b := r.touches[r.order[0]], r.touches[r.order[1]]
+		a.cancelled = true
+		b.cancelled = true
+		cx, cy := midpoint(a, b)
+		r.pinch = &gesturePinch{
+			ids:           [2]int{r.order[0], r.order[1]},
+			startDistance: touchDistance(a, b),
+			lastScale:     1,
+			startX:        int(cx),
+			startY:        int(cy),
+		}
+	}
+	return nil
+}
+
+// Move 触点移动
+func (r *GestureRecognizer) Move(id, x, y int, t time.Time) []Gesture {
+	touch, ok := r.touches[id]
+	if !ok {
+		return nil
+	}
+	touch.x, touch.y = float64(x), float64(y)
+	if math.Hypot(touch.x-touch.startX, touch.y-touch.startY) > r.config.TapMaxMovement {
+		touch.moved = true
+	}
+
+	if r.pinch != nil && (id == r.pinch.ids[0] || id == r.pinch.ids[1]) {
+		return r.updatePinch()
+	}
+	return r.Update(t)
+}
+
+// End 触点抬起
+func (r *GestureRecognizer) End(id, x, y int, t time.Time) []Gesture {
+	touch, ok := r.touches[id]
+	if !ok {
+		return nil
+	}
+	gestures := r.Move(id, x, y, t)
+
+	delete(r.touches, id)
+	for i, tid := range r.order {
+		if tid == id {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+	if r.pinch != nil && (id == r.pinch.ids[0] || id == r.pinch.ids[1]) {
+		r.pinch = nil
+	}
+
+	if touch.cancelled || touch.longPressed {
+		return gestures
+	}
+
+	duration := t.Sub(touch.startTime)
+	dx := touch.x - touch.startX
+	dy := touch.y - touch.startY
+	distance := math.Hypot(dx, dy)
+
+	// 点击 / 双击
+	if !touch.moved && duration <= r.config.TapMaxDuration {
+		tap := Gesture{Type: GestureTap, X: int(touch.startX), Y: int(touch.startY)}
+		gestures = append(gestures, tap)
+
+		if r.hasLastTap && t.Sub(r.lastTapTime) <= r.config.DoubleTapInterval &&
+			math.Hypot(touch.startX-r.lastTapX, touch.startY-r.lastTapY) <= r.config.DoubleTapMaxDistance {
+			tap.Type = GestureDoubleTap
+			gestures = append(gestures, tap)
+			r.hasLastTap = false
+		} else {
+			r.lastTapTime = t
+			r.lastTapX, r.lastTapY = touch.startX, touch.startY
+			r.hasLastTap = true
+		}
+		return gestures
+	}
+
+	// 滑动
+	seconds := duration.Seconds()
+	if seconds <= 0 || distance < r.config.SwipeMinDistance {
+		return gestures
+	}
+	vx, vy := dx/seconds, dy/seconds
+	if math.Hypot(vx, vy) < r.config.SwipeMinVelocity {
+		return gestures
+	}
+	return append(gestures, Gesture{
+		Type:      GestureSwipe,
+		X:         int(touch.startX),
+		Y:         int(touch.startY),
+		Direction: swipeDirection(dx, dy),
+		DeltaX:    dx,
+		DeltaY:    dy,
+		VelocityX: vx,
+		VelocityY: vy,
+	})
+}
+
+// Update 推进时间（用于长按检测），每帧调用一次
+func (r *GestureRecognizer) Update(t time.Time) []Gesture {
+	var gestures []Gesture
+	for _, id := range r.order {
+		touch := r.touches[id]
+		if touch.cancelled || touch.moved || touch.longPressed {
+			continue
+		}
+		if t.Sub(touch.startTime) >= r.config.LongPressDuration {
+			touch.longPressed = true
+			gestures = append(gestures, Gesture{
+				Type: GestureLongPress,
+				X:    int(touch.startX),
+				Y:    int(touch.startY),
+			})
+		}
+	}
+	return gestures
+}
+
+// updatePinch 两指距离变化超过阈值时产生缩放手势
+func (r *GestureRecognizer) updatePinch() []Gesture {
+	a, b := r.touches[r.pinch.ids[0]], r.touches[r.pinch.ids[1]]
+	if r.pinch.startDistance <= 0 {
+		return nil
+	}
+	scale := touchDistance(a, b) / r.pinch.startDistance
+	if math.Abs(scale-r.pinch.lastScale) < r.config.PinchMinScaleDelta {
+		return nil
+	}
+	r.pinch.lastScale = scale
+	cx, cy := midpoint(a, b)
+	return []Gesture{{
+		Type:    GesturePinch,
+		X:       r.pinch.startX,
+		Y:       r.pinch.startY,
+		Scale:   scale,
+		CenterX: int(cx),
+		CenterY: int(cy),
+	}}
+}
+
+// swipeDirection 按主方向返回滑动方向
+func swipeDirection(dx, dy float64) string {
+	if math.Abs(dx) >= math.Abs(dy) {
+		if dx < 0 {
+			return "left"
+		}
+		return "right"
+	}
+	if dy < 0 {
+		return "up"
+	}
+	return "down"
+}
+
+func touchDistance(a, b *gestureTouch) float64 {
+	return math.Hypot(a.x-b.x, a.y-b.y)
+}
+
+func midpoint(a, b *gestureTouch) (float64, float64) {
+	return (a.x + b.x) / 2, (a.y + b.y) / 2
+}
@@ -0,0 +1,269 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// touchStep 合成触摸轨迹中的一步
+type touchStep struct {
+	at    time.Duration // 相对轨迹起点的时间
+	phase string        // "begin", "move", "end", "tick"
+	id    int
+	x, y  int
+}
+
+// runTrace 回放合成触摸轨迹并收集识别出的手势
+func runTrace(r *GestureRecognizer, steps []touchStep) []Gesture {
+	start := time.Unix(1000, 0)
+	var gestures []Gesture
+	for _, s := range steps {
+		t := start.Add(s.at)
+		switch s.phase {
+		case "begin":
+			gestures = append(gestures, r.Begin(s.id, s.x, s.y, t)...)
+		case "move":
+			gestures = append(gestures, r.Move(s.id, s.x, s.y, t)...)
+		case "end":
+			gestures = append(gestures, r.End(s.id, s.x, s.y, t)...)
+		case "tick":
+			gestures = append(gestures, r.Update(t)...)
+		}
+	}
+	return gestures
+}
+
+func gestureTypes(gestures []Gesture) []GestureType {
+	types := make([]GestureType, len(gestures))
+	for i, g := range gestures {
+		types[i] = g.Type
+	}
+	return types
+}
+
+func equalGestureTypes(a, b []GestureType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestGestureRecognizer_Traces 表驱动的合成轨迹测试
+func TestGestureRecognizer_Traces(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name  string
+		steps []touchStep
+		want  []GestureType
+	}{
+		{
+			name: "tap",
+			steps: []touchStep{
+				{0, "begin", 1, 100, 100},
+				{100 * ms, "end", 1, 102, 101},
+			},
+			want: []GestureType{GestureTap},
+		},
+		{
+			name: "slow release is not a tap",
+			steps: []touchStep{
+				{0, "begin", 1, 100, 100},
+				{400 * ms, "end", 1, 100, 100},
+			},
+			want: nil,
+		},
+		{
+			name: "double tap",
+			steps: []touchStep{
+				{0, "begin", 1, 100, 100},
+				{80 * ms, "end", 1, 100, 100},
+				{200 * ms, "begin", 2, 105, 98},
+				{280 * ms, "end", 2, 105, 98},
+			},
+			want: []GestureType{GestureTap, GestureTap, GestureDoubleTap},
+		},
+		{
+			name: "two taps too far apart in time",
+			steps: []touchStep{
+				{0, "begin", 1, 100, 100},
+				{80 * ms, "end", 1, 100, 100},
+				{800 * ms, "begin", 2, 100, 100},
+				{880 * ms, "end", 2, 100, 100},
+			},
+			want: []GestureType{GestureTap, GestureTap},
+		},
+		{
+			name: "long press fires once and suppresses tap",
+			steps: []touchStep{
+				{0, "begin", 1, 100, 100},
+				{300 * ms, "tick", 0, 0, 0},
+				{600 * ms, "tick", 0, 0, 0},
+				{700 * ms, "tick", 0, 0, 0},
+				{800 * ms, "end", 1, 100, 100},
+			},
+			want: []GestureType{GestureLongPress},
+		},
+		{
+			name: "moving cancels long press",
+			steps: []touchStep{
+				{0, "begin", 1, 100, 100},
+				{100 * ms, "move", 1, 140, 100},
+				{600 * ms, "tick", 0, 0, 0},
+			},
+			want: nil,
+		},
+		{
+			name: "swipe",
+			steps: []touchStep{
+				{0, "begin", 1, 100, 100},
+				{50 * ms, "move", 1, 160, 105},
+				{100 * ms, "end", 1, 250, 110},
+			},
+			want: []GestureType{GestureSwipe},
+		},
+		{
+			name: "slow drag is not a swipe",
+			steps: []touchStep{
+				{0, "begin", 1, 100, 100},
+				{1000 * ms, "end", 1, 200, 100},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewGestureRecognizer(DefaultGestureConfig())
+			got := gestureTypes(runTrace(r, tt.steps))
+			if !equalGestureTypes(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestGestureRecognizer_SwipeDirectionVelocity 测试滑动方向和速度
+func TestGestureRecognizer_SwipeDirectionVelocity(t *testing.T) {
+	r := NewGestureRecognizer(DefaultGestureConfig())
+	gestures := runTrace(r, []touchStep{
+		{0, "begin", 1, 200, 300},
+		{200 * time.Millisecond, "end", 1, 190, 100},
+	})
+	if len(gestures) != 1 || gestures[0].Type != GestureSwipe {
+		t.Fatalf("Expected one swipe, got %v", gestureTypes(gestures))
+	}
+	g := gestures[0]
+	if g.Direction != "up" {
+		t.Errorf("Expected direction up, got %s", g.Direction)
+	}
+	if g.VelocityY != -1000 {
+		t.Errorf("Expected velocityY -1000, got %f", g.VelocityY)
+	}
+}
+
+// TestGestureRecognizer_Pinch 测试双指缩放并取消单指手势
+func TestGestureRecognizer_Pinch(t *testing.T) {
+	r := NewGestureRecognizer(DefaultGestureConfig())
+	gestures := runTrace(r, []touchStep{
+		{0, "begin", 1, 100, 100},
+		{10 * time.Millisecond, "begin", 2, 200, 100},
+		{50 * time.Millisecond, "move", 1, 50, 100},
+		{60 * time.Millisecond, "move", 2, 250, 100},
+		{100 * time.Millisecond, "end", 1, 50, 100},
+		{110 * time.Millisecond, "end", 2, 250, 100},
+	})
+
+	want := []GestureType{GesturePinch, GesturePinch}
+	if !equalGestureTypes(gestureTypes(gestures), want) {
+		t.Fatalf("Expected %v, got %v", want, gestureTypes(gestures))
+	}
+	if gestures[1].Scale != 2 {
+		t.Errorf("Expected final scale 2, got %f", gestures[1].Scale)
+	}
+	if gestures[1].X != 150 || gestures[1].CenterX != 150 {
+		t.Errorf("Expected pinch centered at x=150, got start %d center %d", gestures[1].X, gestures[1].CenterX)
+	}
+}
+
+// TestGestureRecognizer_Config 测试阈值可配置
+func TestGestureRecognizer_Config(t *testing.T) {
+	config := DefaultGestureConfig()
+	config.LongPressDuration = 100 * time.Millisecond
+	r := NewGestureRecognizer(config)
+
+	got := gestureTypes(runTrace(r, []touchStep{
+		{0, "begin", 1, 10, 10},
+		{150 * time.Millisecond, "tick", 0, 0, 0},
+	}))
+	if !equalGestureTypes(got, []GestureType{GestureLongPress}) {
+		t.Errorf("Expected long press with shorter threshold, got %v", got)
+	}
+}
+
+// TestInputDispatcher_TouchTap 测试触摸经分发器产生touch事件、tap和click
+func TestInputDispatcher_TouchTap(t *testing.T) {
+	d, src, eq := newDispatcherFixture()
+	defer eq.Close()
+
+	clock := time.Unix(1000, 0)
+	d.now = func() time.Time { return clock }
+	src.x, src.y = -100, -100
+	d.Update()
+	drainEvents(eq)
+
+	src.touches[ebiten.TouchID(7)] = [2]int{20, 20}
+	d.Update()
+	clock = clock.Add(50 * time.Millisecond)
+	delete(src.touches, ebiten.TouchID(7))
+	d.Update()
+
+	got := eventTypes(drainEvents(eq), "btn1")
+	want := []EventType{EventTouchStart, EventStateChange, EventFocus, EventStateChange, EventTouchEnd, EventStateChange, EventTap, EventClick}
+	if !equalEventTypes(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestInputDispatcher_TouchSwipe 测试滑动手势事件携带方向和速度
+func TestInputDispatcher_TouchSwipe(t *testing.T) {
+	d, src, eq := newDispatcherFixture()
+	defer eq.Close()
+
+	clock := time.Unix(1000, 0)
+	d.now = func() time.Time { return clock }
+	src.x, src.y = -100, -100
+
+	id := ebiten.TouchID(1)
+	src.touches[id] = [2]int{15, 20}
+	d.Update()
+	clock = clock.Add(50 * time.Millisecond)
+	src.touches[id] = [2]int{90, 20}
+	d.Update()
+	clock = clock.Add(50 * time.Millisecond)
+	delete(src.touches, id)
+	d.Update()
+
+	var swipe *WidgetEvent
+	for _, e := range drainEvents(eq) {
+		if e.Type == EventSwipe {
+			e := e
+			swipe = &e
+		}
+	}
+	if swipe == nil {
+		t.Fatal("Expected swipe event")
+	}
+	if swipe.WidgetID != "btn1" || swipe.Data["direction"] != "right" {
+		t.Errorf("Expected right swipe on btn1, got %v on %s", swipe.Data["direction"], swipe.WidgetID)
+	}
+	if v, _ := swipe.Data["velocityX"].(float64); v != 750 {
+		t.Errorf("Expected velocityX 750, got %v", swipe.Data["velocityX"])
+	}
+}
@@ -0,0 +1,307 @@
+package ui
+
+import (
+	"image"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// TrackKind 网格轨道类型
+type TrackKind int
+
+const (
+	TrackFixed    TrackKind = iota // 固定像素
+	TrackFraction                  // fr：按比例分配剩余空间
+	TrackAuto                      // auto：取该轨道内子控件的最大首选尺寸
+)
+
+// GridTrack 网格轨道（行或列）定义
+type GridTrack struct {
+	Kind  TrackKind
+	Value float64 // 像素或fr系数
+}
+
+// ParseGridTrack 解析轨道定义："120"、"120px"、"1fr"、"2.5fr"或"auto"
+func ParseGridTrack(s string) (GridTrack, bool) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "auto":
+		return GridTrack{Kind: TrackAuto}, true
+	case strings.HasSuffix(s, "fr"):
+		value, err := strconv.ParseFloat(strings.TrimSuffix(s, "fr"), 64)
+		if err != nil || value < 0 {
+			return GridTrack{}, false
+		}
+		return GridTrack{Kind: TrackFraction, Value: value}, true
+	}
+	value, err := strconv.ParseFloat(strings.TrimSuffix(s, "px"), 64)
+	if err != nil || value < 0 {
+		return GridTrack{}, false
+	}
+	return GridTrack{Kind: TrackFixed, Value: value}, true
+}
+
+// ParseGridTracks 解析轨道列表，忽略无法识别的定义
+func ParseGridTracks(defs []string) []GridTrack {
+	tracks := make([]GridTrack, 0, len(defs))
+	for _, def := range defs {
+		if track, ok := ParseGridTrack(def); ok {
+			tracks = append(tracks, track)
+		}
+	}
+	return tracks
+}
+
+// GridItem 网格布局子项属性
+// Row/Column从1开始，为0时按行优先顺序自动放入下一个空闲单元格；Span为0时视为1
+type GridItem struct {
+	Row, Column         int
+	RowSpan, ColumnSpan int
+	JustifySelf         string // 单元格内水平对齐，覆盖容器的justifyItems
+	AlignSelf           string // 单元格内垂直对齐，覆盖容器的alignItems
+}
+
+// GridOptions 网格布局容器参数
+type GridOptions struct {
+	Columns      []GridTrack // 为空时只有一列1fr
+	Rows         []GridTrack // 子控件超出定义的行时追加auto行
+	Gap          int
+	JustifyItems string // 单元格内水平对齐："start"（默认）、"center"、"end"、"stretch"
+	AlignItems   string // 单元格内垂直对齐
+}
+
+// gridEntry 参与网格布局的子控件
+type gridEntry struct {
+	target           layoutTarget
+	item             GridItem
+	margin           Spacing
+	row, col         int // 从0开始
+	rowSpan, colSpan int
+	width, height    float64 // 首选尺寸（含外边距）
+}
+
+// LayoutGrid 按网格布局排列子控件
+// 计算结果以相对容器左上角的局部边界写入每个可见子控件
+func LayoutGrid(children []Widget, opts GridOptions, width, height int) {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = []GridTrack{{Kind: TrackFraction, Value: 1}}
+	}
+
+	entries := placeGridItems(children, len(columns))
+
+	rows := opts.Rows
+	for _, e := range entries {
+		for len(rows) < e.row+e.rowSpan {
+			rows = append(rows, GridTrack{Kind: TrackAuto})
+		}
+	}
+
+	gap := float64(opts.Gap)
+	colSizes := resolveGridTracks(columns, float64(width), gap, entries, func(e *gridEntry) (int, int, float64) {
+		return e.col, e.colSpan, e.width
+	})
+	rowSizes := resolveGridTracks(rows, float64(height), gap, entries, func(e *gridEntry) (int, int, float64) {
+		return e.row, e.rowSpan, e.height
+	})
+	colStarts := trackStarts(colSizes, gap)
+	rowStarts := trackStarts(rowSizes, gap)
+
+	for _, e := range entries {
+		cellX := colStarts[e.col]
+		cellY := rowStarts[e.row]
+		cellW := colStarts[e.col+e.colSpan-1] + colSizes[e.col+e.colSpan-1] - cellX
+		cellH := rowStarts[e.row+e.rowSpan-1] + rowSizes[e.row+e.rowSpan-1] - cellY
+
+		justify := opts.JustifyItems
+		if e.item.JustifySelf != "" {
+			justify = e.item.JustifySelf
+		}
+		align := opts.AlignItems
+		if e.item.AlignSelf != "" {
+			align = e.item.AlignSelf
+		}
+		w, dx := alignInCell(justify, e.width, cellW)
+		h, dy := alignInCell(align, e.height, cellH)
+
+		x0, y0 := math.Round(cellX+dx), math.Round(cellY+dy)
+		x1, y1 := math.Round(cellX+dx+w), math.Round(cellY+dy+h)
+		e.target.SetLayoutBounds(e.margin.Inset(image.Rect(int(x0), int(y0), int(x1), int(y1))))
+	}
+}
+
+// MeasureGrid 计算网格布局容器的首选尺寸
+// 测量时fr轨道按auto处理，即取轨道内子控件的最大首选尺寸
+func MeasureGrid(children []Widget, opts GridOptions) image.Point {
+	columns := opts.Columns
+	if len(columns) == 0 {
+		columns = []GridTrack{{Kind: TrackFraction, Value: 1}}
+	}
+	entries := placeGridItems(children, len(columns))
+	rows := opts.Rows
+	for _, e := range entries {
+		for len(rows) < e.row+e.rowSpan {
+			rows = append(rows, GridTrack{Kind: TrackAuto})
+		}
+	}
+
+	gap := float64(opts.Gap)
+	width := measureGridTracks(columns, gap, entries, func(e *gridEntry) (int, int, float64) {
+		return e.col, e.colSpan, e.width
+	})
+	height := measureGridTracks(rows, gap, entries, func(e *gridEntry) (int, int, float64) {
+		return e.row, e.rowSpan, e.height
+	})
+	return image.Pt(int(math.Ceil(width)), int(math.Ceil(height)))
+}
+
+// measureGridTracks 计算轨道的总尺寸（含间距）
+func measureGridTracks(tracks []GridTrack, gap float64, entries []*gridEntry, span func(*gridEntry) (int, int, float64)) float64 {
+	auto := make([]GridTrack, len(tracks))
+	for i, track := range tracks {
+		auto[i] = track
+		if track.Kind == TrackFraction {
+			auto[i] = GridTrack{Kind: TrackAuto}
+		}
+	}
+	total := gap * float64(max(len(tracks)-1, 0))
+	for _, size := range resolveGridTracks(auto, 0, gap, entries, span) {
+		total += size
+	}
+	return total
+}
+
+// placeGridItems 确定每个子控件所在的单元格
+// 显式指定位置的子控件先放置，其余子控件按行优先顺序填入空闲单元格
+func placeGridItems(children []Widget, columnCount int) []*gridEntry {
+	var entries []*gridEntry
+	occupied := make(map[image.Point]bool)
+	occupy := func(e *gridEntry) {
+		for r := e.row; r < e.row+e.rowSpan; r++ {
+			for c := e.col; c < e.col+e.colSpan; c++ {
+				occupied[image.Pt(c, r)] = true
+			}
+		}
+	}
+	fits := func(row, col, rowSpan, colSpan int) bool {
+		for r := row; r < row+rowSpan; r++ {
+			for c := col; c < col+colSpan; c++ {
+				if occupied[image.Pt(c, r)] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	var autoPlaced []*gridEntry
+	for _, child := range children {
+		target, ok := child.(layoutTarget)
+		if !ok || !child.IsVisible() {
+			continue
+		}
+		e := &gridEntry{target: target, margin: child.GetMargin()}
+		if g, ok := child.(interface{ GetGridItem() GridItem }); ok {
+			e.item = g.GetGridItem()
+		}
+		w, h := preferredSize(child)
+		e.width = float64(w + e.margin.Horizontal())
+		e.height = float64(h + e.margin.Vertical())
+
+		e.rowSpan = max(e.item.RowSpan, 1)
+		e.colSpan = min(max(e.item.ColumnSpan, 1), columnCount)
+		entries = append(entries, e)
+
+		if e.item.Row > 0 && e.item.Column > 0 {
+			e.row = e.item.Row - 1
+			e.col = min(e.item.Column-1, columnCount-e.colSpan)
+			occupy(e)
+		} else {
+			autoPlaced = append(autoPlaced, e)
+		}
+	}
+
+	for _, e := range autoPlaced {
+		// 只指定了行或列时在该行/列内查找
+		for cell := 0; ; cell++ {
+			row, col := cell/columnCount, cell%columnCount
+			if e.item.Row > 0 && row != e.item.Row-1 {
+				if row > e.item.Row-1 {
+					// 指定行已满，退回到该行之后继续自动放置
+					e.item.Row = 0
+				}
+				continue
+			}
+			if e.item.Column > 0 && col != e.item.Column-1 {
+				continue
+			}
+			if col+e.colSpan > columnCount || !fits(row, col, e.rowSpan, e.colSpan) {
+				continue
+			}
+			e.row, e.col = row, col
+			occupy(e)
+			break
+		}
+	}
+	return entries
+}
+
+// resolveGridTracks 计算各轨道尺寸
+// 先确定固定和auto轨道，剩余空间按fr系数分配
+func resolveGridTracks(tracks []GridTrack, available, gap float64, entries []*gridEntry, span func(*gridEntry) (int, int, float64)) []float64 {
+	sizes := make([]float64, len(tracks))
+	used := gap * float64(max(len(tracks)-1, 0))
+	totalFr := 0.0
+	for i, track := range tracks {
+		switch track.Kind {
+		case TrackFixed:
+			sizes[i] = track.Value
+		case TrackAuto:
+			// 只考虑不跨轨道的子控件
+			for _, e := range entries {
+				if start, n, size := span(e); start == i && n == 1 {
+					sizes[i] = math.Max(sizes[i], size)
+				}
+			}
+		case TrackFraction:
+			totalFr += track.Value
+			continue
+		}
+		used += sizes[i]
+	}
+
+	if free := available - used; free > 0 && totalFr > 0 {
+		for i, track := range tracks {
+			if track.Kind == TrackFraction {
+				sizes[i] = free * track.Value / totalFr
+			}
+		}
+	}
+	return sizes
+}
+
+// trackStarts 计算各轨道的起始位置
+func trackStarts(sizes []float64, gap float64) []float64 {
+	starts := make([]float64, len(sizes))
+	pos := 0.0
+	for i, size := range sizes {
+		starts[i] = pos
+		pos += size + gap
+	}
+	return starts
+}
+
+// alignInCell 计算子控件在单元格内一个方向上的尺寸和偏移
+func alignInCell(align string, preferred, cell float64) (float64, float64) {
+	size := math.Min(preferred, cell)
+	switch align {
+	case "stretch":
+		return cell, 0
+	case "center":
+		return size, (cell - size) / 2
+	case "end":
+		return size, cell - size
+	}
+	return size, 0
+}
@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"image"
+	"testing"
+)
+
+// TestParseGridTrack 测试轨道定义解析
+func TestParseGridTrack(t *testing.T) {
+	tests := []struct {
+		def  string
+		want GridTrack
+		ok   bool
+	}{
+		{"120", GridTrack{Kind: TrackFixed, Value: 120}, true},
+		{"80px", GridTrack{Kind: TrackFixed, Value: 80}, true},
+		{"1fr", GridTrack{Kind: TrackFraction, Value: 1}, true},
+		{"2.5fr", GridTrack{Kind: TrackFraction, Value: 2.5}, true},
+		{"auto", GridTrack{Kind: TrackAuto}, true},
+		{"wide", GridTrack{}, false},
+		{"-1fr", GridTrack{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseGridTrack(tt.def)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("%q: expected %v (%v), got %v (%v)", tt.def, tt.want, tt.ok, got, ok)
+		}
+	}
+}
+
+// TestGridLayout_Tracks 测试固定、auto和fr轨道
+func TestGridLayout_Tracks(t *testing.T) {
+	label := newFlexChild("label", 90, 20)
+	field := newFlexChild("field", 50, 20)
+	unit := newFlexChild("unit", 30, 20)
+
+	opts := GridOptions{
+		Columns: ParseGridTracks([]string{"auto", "1fr", "2fr", "40"}),
+		Gap:     10,
+	}
+	LayoutGrid([]Widget{label, field, unit}, opts, 400, 100)
+
+	// auto=90，剩余 400-90-40-30(间距) = 240，1fr=80，2fr=160
+	want := []image.Rectangle{
+		image.Rect(0, 0, 90, 20),
+		image.Rect(100, 0, 150, 20),
+		image.Rect(190, 0, 220, 20),
+	}
+	for i, w := range []Widget{label, field, unit} {
+		if got := layoutRect(t, w); got != want[i] {
+			t.Errorf("%s: expected %v, got %v", w.GetID(), want[i], got)
+		}
+	}
+}
+
+// TestGridLayout_PlacementAndSpans 测试显式位置、跨行列和自动放置
+func TestGridLayout_PlacementAndSpans(t *testing.T) {
+	header := newFlexChild("header", 10, 10)
+	header.GridRow, header.GridColumn = 1, 1
+	header.GridColumnSpan = 3
+	side := newFlexChild("side", 10, 10)
+	side.GridRow, side.GridColumn = 2, 1
+	side.GridRowSpan = 2
+	a := newFlexChild("a", 10, 10)
+	b := newFlexChild("b", 10, 10)
+	c := newFlexChild("c", 10, 10)
+
+	opts := GridOptions{
+		Columns:      ParseGridTracks([]string{"100", "100", "100"}),
+		Rows:         ParseGridTracks([]string{"50", "50", "50"}),
+		JustifyItems: "stretch",
+		AlignItems:   "stretch",
+	}
+	LayoutGrid([]Widget{header, side, a, b, c}, opts, 300, 150)
+
+	want := map[Widget]image.Rectangle{
+		header: image.Rect(0, 0, 300, 50),
+		side:   image.Rect(0, 50, 100, 150),
+		a:      image.Rect(100, 50, 200, 100),
+		b:      image.Rect(200, 50, 300, 100),
+		c:      image.Rect(100, 100, 200, 150),
+	}
+	for w, rect := range want {
+		if got := layoutRect(t, w); got != rect {
+			t.Errorf("%s: expected %v, got %v", w.GetID(), rect, got)
+		}
+	}
+}
+
+// TestGridLayout_CellAlignment 测试单元格内对齐和隐式auto行
+func TestGridLayout_CellAlignment(t *testing.T) {
+	center := newFlexChild("center", 40, 20)
+	center.JustifySelf = "center"
+	center.AlignSelf = "center"
+	end := newFlexChild("end", 40, 20)
+	end.JustifySelf = "end"
+	end.AlignSelf = "end"
+	tall := newFlexChild("tall", 40, 60)
+
+	opts := GridOptions{Columns: ParseGridTracks([]string{"100", "100"})}
+	LayoutGrid([]Widget{center, end, tall}, opts, 200, 500)
+
+	// 未定义的行为auto，第一行高度取行内最大首选高度（20）
+	if got := layoutRect(t, center); got != image.Rect(30, 0, 70, 20) {
+		t.Errorf("center: expected (30,0)-(70,20), got %v", got)
+	}
+	if got := layoutRect(t, end); got != image.Rect(160, 0, 200, 20) {
+		t.Errorf("end: expected (160,0)-(200,20), got %v", got)
+	}
+	if got := layoutRect(t, tall); got != image.Rect(0, 20, 40, 80) {
+		t.Errorf("tall: expected (0,20)-(40,80), got %v", got)
+	}
+}
+
+// TestGridLayout_Panel 测试面板的grid布局模式和轨道列表解析
+func TestGridLayout_Panel(t *testing.T) {
+	panel := NewPanel("settings")
+	panel.Layout = "grid"
+	panel.GridColumns = parseTrackList("120 1fr")
+	panel.GridRows = parseTrackList([]interface{}{"30", 40.0})
+	panel.Gap = 8
+	panel.JustifyItems = "stretch"
+
+	name := NewLabel("nameLabel")
+	input := NewTextInput("nameInput")
+	panel.AddChild(name)
+	panel.AddChild(input)
+
+	panel.LayoutChildren(400, 200)
+	if got := layoutRect(t, input); got.Min.X != 128 || got.Dx() != 272 {
+		t.Errorf("Expected input to fill the 1fr column, got %v", got)
+	}
+	if len(panel.GridRows) != 2 || panel.GridRows[1] != "40" {
+		t.Errorf("Expected rows [30 40], got %v", panel.GridRows)
+	}
+}
+
+// TestGridLayout_Margins 测试外边距参与单元格内的对齐和auto轨道尺寸
+func TestGridLayout_Margins(t *testing.T) {
+	a := newFlexChild("a", 40, 20)
+	a.Margin = Spacing{Top: 5, Right: 5, Bottom: 5, Left: 5}
+	b := newFlexChild("b", 40, 20)
+	opts := GridOptions{Columns: ParseGridTracks([]string{"auto", "auto"}), Gap: 10}
+	LayoutGrid([]Widget{a, b}, opts, 300, 100)
+
+	// 第一列宽50（40+外边距），行高30
+	if got, want := layoutRect(t, a), image.Rect(5, 5, 45, 25); got != want {
+		t.Errorf("expected a %v, got %v", want, got)
+	}
+	if got, want := layoutRect(t, b), image.Rect(60, 0, 100, 20); got != want {
+		t.Errorf("expected b %v, got %v", want, got)
+	}
+}
@@ -3,11 +3,8 @@ package ui
 import (
 	"fmt"
 	"image"
-	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text"
-	"github.com/hajimehoshi/ebiten/v2/vector"
 	"golang.org/x/image/font/basicfont"
 )
 
@@ -36,6 +33,9 @@ type GridViewWidget struct {
 	BorderColorAlpha     uint8 `json:"borderColorAlpha"`
 	BorderWidth          int   `json:"borderWidth"`
 
+	// 拖放（需同时设置draggable/dropTarget）
+	DropMode string `json:"dropMode"` // "swap" 交换两个格子的项，"move" 移动项并顺移其余项
+
 	// 状态
 	Enabled bool `json:"enabled"`
 }
@@ -44,13 +44,14 @@ type GridViewWidget struct {
 func NewGridView(id string) *GridViewWidget {
 	return &GridViewWidget{
 		BaseWidget: BaseWidget{
-			ID:          id,
-			Type:        TypeGridView,
-			Visible:     true,
-			Interactive: true,
-			Width:       300,
-			Height:      200,
-			Opacity:     100,
+			ID:           id,
+			Type:         TypeGridView,
+			Visible:      true,
+			Interactive:  true,
+			ClipChildren: true,
+			Width:        300,
+			Height:       200,
+			Opacity:      100,
 		},
 		ItemWidth:            80,
 		ItemHeight:           80,
@@ -63,6 +64,7 @@ func NewGridView(id string) *GridViewWidget {
 		BorderColor:          RGBA{200, 200, 200, 255},
 		BorderColorAlpha:     255,
 		BorderWidth:          1,
+		DropMode:             "swap",
 		Enabled:              true,
 		Items:                make([]map[string]interface{}, 0),
 	}
@@ -81,54 +83,38 @@ func (g *GridViewWidget) Draw(screen *ebiten.Image, parentX, parentY, parentWidt
 	absX := parentX + localX
 	absY := parentY + localY
 
-	// 创建子图像作为裁剪区域
-	subImg := screen.SubImage(image.Rect(absX, absY, absX+renderWidth, absY+renderHeight)).(*ebiten.Image)
+	drawPainted(screen, g, image.Rect(absX, absY, absX+renderWidth, absY+renderHeight))
+
+	// 绘制子控件（暂不支持，因为GridView使用模板）
+	// g.BaseWidget.DrawChildren(screen, absX, absY, renderWidth, renderHeight)
+}
+
+// Paint 记录背景、网格项（或占位符）和边框，全部裁剪到网格边界内
+func (g *GridViewWidget) Paint(list *DrawList, bounds image.Rectangle) {
+	list.PushClip(bounds)
 
 	// 绘制背景
-	g.drawBackground(subImg, 0, 0, renderWidth, renderHeight)
+	list.FillRect(bounds, RGBA{R: g.BackgroundColor.R, G: g.BackgroundColor.G, B: g.BackgroundColor.B, A: g.BackgroundColorAlpha})
 
-	// 绘制项
+	// 绘制项（在内容区域内）
+	content := g.ContentRect(bounds)
 	if g.ItemTemplate != nil && len(g.Items) > 0 {
-		g.drawItems(subImg, 0, 0, renderWidth, renderHeight)
+		g.paintItems(list, content.Min.X, content.Min.Y, content.Dx(), content.Dy())
 	} else {
 		// 如果没有模板，绘制占位符
-		g.drawPlaceholder(subImg, 0, 0, renderWidth, renderHeight)
+		g.paintPlaceholder(list, content.Min.X, content.Min.Y, content.Dx(), content.Dy())
 	}
 
 	// 绘制边框
-	g.drawBorder(subImg, 0, 0, renderWidth, renderHeight)
-
-	// 绘制子控件（暂不支持，因为GridView使用模板）
-	// g.BaseWidget.DrawChildren(screen, absX, absY, renderWidth, renderHeight)
-}
-
-// drawBackground 绘制背景
-func (g *GridViewWidget) drawBackground(screen *ebiten.Image, x, y, width, height int) {
-	bgColor := RGBA{R: g.BackgroundColor.R, G: g.BackgroundColor.G, B: g.BackgroundColor.B, A: g.BackgroundColorAlpha}
-	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(height), bgColor.ToColor(), false)
-}
-
-// drawBorder 绘制边框
-func (g *GridViewWidget) drawBorder(screen *ebiten.Image, x, y, width, height int) {
-	if g.BorderWidth <= 0 {
-		return
+	if g.BorderWidth > 0 {
+		list.StrokeRect(bounds, g.BorderWidth, RGBA{R: g.BorderColor.R, G: g.BorderColor.G, B: g.BorderColor.B, A: g.BorderColorAlpha})
 	}
 
-	borderColor := RGBA{R: g.BorderColor.R, G: g.BorderColor.G, B: g.BorderColor.B, A: g.BorderColorAlpha}
-	bw := float32(g.BorderWidth)
-
-	// 上边
-	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), bw, borderColor.ToColor(), false)
-	// 下边
-	vector.DrawFilledRect(screen, float32(x), float32(y+height)-bw, float32(width), bw, borderColor.ToColor(), false)
-	// 左边
-	vector.DrawFilledRect(screen, float32(x), float32(y), bw, float32(height), borderColor.ToColor(), false)
-	// 右边
-	vector.DrawFilledRect(screen, float32(x+width)-bw, float32(y), bw, float32(height), borderColor.ToColor(), false)
+	list.PopClip()
 }
 
-// drawPlaceholder 绘制占位符（无数据时）
-func (g *GridViewWidget) drawPlaceholder(screen *ebiten.Image, x, y, width, height int) {
+// paintPlaceholder 记录占位符（无数据时）
+func (g *GridViewWidget) paintPlaceholder(list *DrawList, x, y, width, height int) {
 	// 计算网格布局
 	cols := g.Columns
 	if cols <= 0 {
@@ -159,24 +145,19 @@ func (g *GridViewWidget) drawPlaceholder(screen *ebiten.Image, x, y, width, heig
 			break
 		}
 
-		// 项背景
-		itemColor := color.RGBA{240, 240, 240, 255}
-		vector.DrawFilledRect(screen, float32(cellX), float32(cellY), float32(cellWidth), float32(cellHeight), itemColor, false)
-
-		// 项边框
-		vector.DrawFilledRect(screen, float32(cellX), float32(cellY), float32(cellWidth), 1, color.RGBA{200, 200, 200, 255}, false)
-		vector.DrawFilledRect(screen, float32(cellX), float32(cellY+cellHeight-1), float32(cellWidth), 1, color.RGBA{200, 200, 200, 255}, false)
-		vector.DrawFilledRect(screen, float32(cellX), float32(cellY), 1, float32(cellHeight), color.RGBA{200, 200, 200, 255}, false)
-		vector.DrawFilledRect(screen, float32(cellX+cellWidth-1), float32(cellY), 1, float32(cellHeight), color.RGBA{200, 200, 200, 255}, false)
+		// 项背景和边框
+		cell := image.Rect(cellX, cellY, cellX+cellWidth, cellY+cellHeight)
+		list.FillRect(cell, RGBA{240, 240, 240, 255})
+		list.StrokeRect(cell, 1, RGBA{200, 200, 200, 255})
 
 		// 示例文本
 		placeholderText := fmt.Sprintf("Item %d", i+1)
-		text.Draw(screen, placeholderText, basicfont.Face7x13, cellX+cellWidth/2-20, cellY+cellHeight/2+5, color.RGBA{100, 100, 100, 255})
+		list.DrawText(placeholderText, basicfont.Face7x13, cellX+cellWidth/2-20, cellY+cellHeight/2+5, RGBA{100, 100, 100, 255})
 	}
 }
 
-// drawItems 绘制数据项（使用模板）
-func (g *GridViewWidget) drawItems(screen *ebiten.Image, x, y, width, height int) {
+// paintItems 记录数据项（使用模板）
+func (g *GridViewWidget) paintItems(list *DrawList, x, y, width, height int) {
 	// 计算网格布局
 	cols := g.Columns
 	if cols <= 0 {
@@ -217,23 +198,18 @@ func (g *GridViewWidget) drawItems(screen *ebiten.Image, x, y, width, height int
 			continue
 		}
 
-		// 项背景
-		itemColor := color.RGBA{250, 250, 250, 255}
-		vector.DrawFilledRect(screen, float32(cellX), float32(cellY), float32(cellWidth), float32(cellHeight), itemColor, false)
-
-		// 项边框
-		vector.DrawFilledRect(screen, float32(cellX), float32(cellY), float32(cellWidth), 1, color.RGBA{220, 220, 220, 255}, false)
-		vector.DrawFilledRect(screen, float32(cellX), float32(cellY+cellHeight-1), float32(cellWidth), 1, color.RGBA{220, 220, 220, 255}, false)
-		vector.DrawFilledRect(screen, float32(cellX), float32(cellY), 1, float32(cellHeight), color.RGBA{220, 220, 220, 255}, false)
-		vector.DrawFilledRect(screen, float32(cellX+cellWidth-1), float32(cellY), 1, float32(cellHeight), color.RGBA{220, 220, 220, 255}, false)
+		// 项背景和边框
+		cell := image.Rect(cellX, cellY, cellX+cellWidth, cellY+cellHeight)
+		list.FillRect(cell, RGBA{250, 250, 250, 255})
+		list.StrokeRect(cell, 1, RGBA{220, 220, 220, 255})
 
 		// 渲染模板
-		g.renderItemTemplate(screen, itemData, cellX, cellY, cellWidth, cellHeight)
+		g.paintItemTemplate(list, itemData, cell)
 	}
 }
 
-// renderItemTemplate 渲染单个项的模板
-func (g *GridViewWidget) renderItemTemplate(screen *ebiten.Image, data map[string]interface{}, x, y, width, height int) {
+// paintItemTemplate 记录单个项的模板
+func (g *GridViewWidget) paintItemTemplate(list *DrawList, data map[string]interface{}, cell image.Rectangle) {
 	if g.ItemTemplate == nil {
 		return
 	}
@@ -260,8 +236,8 @@ func (g *GridViewWidget) renderItemTemplate(screen *ebiten.Image, data map[strin
 		// 应用数据绑定（简单的文本替换）
 		g.applyDataBinding(widget, data)
 
-		// 绘制子控件（相对于项的位置）
-		widget.Draw(screen, x, y, width, height)
+		// 记录子控件（相对于项的位置）
+		recordWidget(list, widget, cell)
 	}
 }
 
@@ -281,3 +257,118 @@ func (g *GridViewWidget) HandleEvent(event interface{}) bool {
 	// TODO: 实现滚动、点击等事件处理
 	return false
 }
+
+// columnCount 获取有效列数
+func (g *GridViewWidget) columnCount() int {
+	if g.Columns <= 0 {
+		return 4
+	}
+	return g.Columns
+}
+
+// CellBounds 计算指定索引的格子在控件内的局部矩形（与paintItems的布局一致）
+func (g *GridViewWidget) CellBounds(index int) image.Rectangle {
+	cols := g.columnCount()
+	col := index % cols
+	row := index / cols
+	x := g.Padding.Left + g.Spacing + col*(g.ItemWidth+g.Spacing)
+	y := g.Padding.Top + g.Spacing + row*(g.ItemHeight+g.Spacing) - g.ScrollY
+	return image.Rect(x, y, x+g.ItemWidth, y+g.ItemHeight)
+}
+
+// CellAt 返回局部坐标所在格子的索引（落在间距中或超出列数时返回-1）
+func (g *GridViewWidget) CellAt(localX, localY int) int {
+	cols := g.columnCount()
+	strideX := g.ItemWidth + g.Spacing
+	strideY := g.ItemHeight + g.Spacing
+	if strideX <= 0 || strideY <= 0 {
+		return -1
+	}
+
+	x := localX - g.Padding.Left - g.Spacing
+	y := localY - g.Padding.Top - g.Spacing + g.ScrollY
+	if x < 0 || y < 0 {
+		return -1
+	}
+	col, row := x/strideX, y/strideY
+	if col >= cols || x%strideX >= g.ItemWidth || y%strideY >= g.ItemHeight {
+		return -1
+	}
+	return row*cols + col
+}
+
+// BeginDrag 从按下位置的格子开始拖拽项
+func (g *GridViewWidget) BeginDrag(x, y int, bounds image.Rectangle) (*DragPayload, bool) {
+	index := g.CellAt(x-bounds.Min.X, y-bounds.Min.Y)
+	if index < 0 || index >= len(g.Items) || g.Items[index] == nil {
+		return nil, false
+	}
+	return &DragPayload{
+		Type: g.DragType,
+		Data: map[string]interface{}{
+			"index": index,
+			"item":  g.Items[index],
+		},
+		Bounds: g.CellBounds(index).Add(bounds.Min),
+	}, true
+}
+
+// AcceptDrop 将拖入的项放到目标格子
+// 来源是GridView时按DropMode交换或移动Items并标记重绘，返回源索引和目标索引
+func (g *GridViewWidget) AcceptDrop(payload *DragPayload, x, y int, bounds image.Rectangle) map[string]interface{} {
+	source, ok := payload.Source.(*GridViewWidget)
+	if !ok {
+		return nil
+	}
+	from, ok := payload.Data["index"].(int)
+	if !ok || from < 0 || from >= len(source.Items) {
+		return nil
+	}
+
+	to := g.CellAt(x-bounds.Min.X, y-bounds.Min.Y)
+	if to < 0 || to >= len(g.Items) {
+		to = len(g.Items)
+		if source == g {
+			to = len(g.Items) - 1
+		}
+	}
+
+	if source == g {
+		g.dropWithin(from, to)
+	} else {
+		g.dropFrom(source, from, to)
+	}
+	return map[string]interface{}{
+		"index":       from,
+		"targetIndex": to,
+	}
+}
+
+// dropWithin 同一网格内交换或移动项
+func (g *GridViewWidget) dropWithin(from, to int) {
+	if from == to {
+		return
+	}
+	g.MarkDirty()
+	if g.DropMode == "move" {
+		item := g.Items[from]
+		g.Items = append(g.Items[:from], g.Items[from+1:]...)
+		g.Items = append(g.Items[:to], append([]map[string]interface{}{item}, g.Items[to:]...)...)
+		return
+	}
+	g.Items[from], g.Items[to] = g.Items[to], g.Items[from]
+}
+
+// dropFrom 从另一个网格拖入项
+// swap模式下目标格子已有项时与源格子交换，否则从源网格移除并插入目标位置
+func (g *GridViewWidget) dropFrom(source *GridViewWidget, from, to int) {
+	item := source.Items[from]
+	g.MarkDirty()
+	source.MarkDirty()
+	if g.DropMode != "move" && to < len(g.Items) {
+		source.Items[from], g.Items[to] = g.Items[to], item
+		return
+	}
+	source.Items = append(source.Items[:from], source.Items[from+1:]...)
+	g.Items = append(g.Items[:to], append([]map[string]interface{}{item}, g.Items[to:]...)...)
+}
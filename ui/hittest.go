@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"image"
+)
+
+// boundsComputer 能够根据父容器计算自身绝对边界的控件
+// 所有嵌入BaseWidget的控件都实现了该接口
+type boundsComputer interface {
+	ComputeBounds(parentX, parentY, parentWidth, parentHeight int) image.Rectangle
+}
+
+// childLayouter 根据自身尺寸排列子控件的容器（例如flex布局的面板）
+type childLayouter interface {
+	LayoutChildren(width, height int)
+}
+
+// widgetBounds 获取控件在父容器中的绝对边界（与Draw使用相同的计算）
+// 优先使用布局过程缓存的结果；没有缓存时（例如不可见的模板控件）按父容器即时计算
+func widgetBounds(widget Widget, parent image.Rectangle) image.Rectangle {
+	if c, ok := widget.(computedLayout); ok {
+		if box, ok := c.GetComputedLayout(); ok {
+			return box.Bounds
+		}
+	}
+	if bc, ok := widget.(boundsComputer); ok {
+		return bc.ComputeBounds(parent.Min.X, parent.Min.Y, parent.Dx(), parent.Dy())
+	}
+	return widget.GetBounds().Add(parent.Min)
+}
+
+// widgetContentBounds 获取控件的内容区域（子控件的父容器）
+// 优先使用布局过程缓存的结果，否则按内边距收缩边界
+func widgetContentBounds(widget Widget, bounds image.Rectangle) image.Rectangle {
+	if c, ok := widget.(computedLayout); ok {
+		if box, ok := c.GetComputedLayout(); ok {
+			return box.Content
+		}
+	}
+	content := widget.GetPadding().Inset(bounds)
+	if s, ok := widget.(contentScroller); ok {
+		content = s.scrollContent(content)
+	}
+	return content
+}
+
+// collectWidgetBounds 递归计算所有可见控件的绝对边界
+func collectWidgetBounds(widgets []Widget, parent image.Rectangle, out map[Widget]image.Rectangle) {
+	for _, widget := range widgets {
+		if !widget.IsVisible() {
+			continue
+		}
+		bounds := widgetBounds(widget, parent)
+		out[widget] = bounds
+		collectWidgetBounds(widget.GetChildren(), widgetContentBounds(widget, bounds), out)
+	}
+}
+
+// childClipper 可以将子控件裁剪到自身边界内的控件
+// 所有嵌入BaseWidget的控件都实现了该接口
+type childClipper interface {
+	ClipsChildren() bool
+}
+
+// clipsChildren 判断控件是否将子控件裁剪到自身边界内（被裁剪掉的部分不参与命中测试）
+func clipsChildren(widget Widget) bool {
+	c, ok := widget.(childClipper)
+	return ok && c.ClipsChildren()
+}
+
+// HitTest 查找视口中指定坐标下最上层的可交互控件
+// 使用与Draw相同的绝对边界计算，遵循z顺序、可见性、可交互性、父容器裁剪和圆角
+// 查找前先对控件树执行一次布局过程，保证边界是最新的
+func HitTest(roots []Widget, viewportWidth, viewportHeight, x, y int) Widget {
+	PerformLayout(roots, viewportWidth, viewportHeight)
+	viewport := image.Rect(0, 0, viewportWidth, viewportHeight)
+	return buildStackOrder(roots, viewport, viewport).hitTest(image.Pt(x, y))
+}
+
+// hitTest 命中测试
+// 后绘制的控件在上层，因此按层叠顺序倒序检查；子控件优先于父控件，滚动条优先于滚动面板的子控件
+func (s stackOrder) hitTest(pt image.Point) Widget {
+	for i := len(s) - 1; i >= 0; i-- {
+		e := s[i]
+		if !pt.In(e.clip) {
+			continue
+		}
+		if e.scrollbar {
+			if e.widget.(*ScrollPanelWidget).ScrollbarAt(pt) {
+				return e.widget
+			}
+			continue
+		}
+		if e.widget.IsInteractive() && pointInRoundedRect(pt, e.bounds, e.widget.GetBorderRadius()) {
+			return e.widget
+		}
+	}
+	return nil
+}
+
+// pointInRoundedRect 判断点是否在圆角矩形内
+func pointInRoundedRect(pt image.Point, rect image.Rectangle, radius int) bool {
+	if !pt.In(rect) {
+		return false
+	}
+	if radius <= 0 {
+		return true
+	}
+
+	// 圆角半径不超过短边的一半
+	if limit := min(rect.Dx(), rect.Dy()) / 2; radius > limit {
+		radius = limit
+	}
+
+	// 使用像素中心判断，找出所在角的圆心
+	px := float64(pt.X) + 0.5
+	py := float64(pt.Y) + 0.5
+	r := float64(radius)
+	cx, cy := px, py
+	if px < float64(rect.Min.X)+r {
+		cx = float64(rect.Min.X) + r
+	} else if px > float64(rect.Max.X)-r {
+		cx = float64(rect.Max.X) - r
+	}
+	if py < float64(rect.Min.Y)+r {
+		cy = float64(rect.Min.Y) + r
+	} else if py > float64(rect.Max.Y)-r {
+		cy = float64(rect.Max.Y) - r
+	}
+
+	dx, dy := px-cx, py-cy
+	return dx*dx+dy*dy <= r*r
+}
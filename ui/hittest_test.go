@@ -0,0 +1,243 @@
+package ui
+
+import (
+	"image"
+	"testing"
+)
+
+// hitID 返回命中控件的ID（未命中返回空字符串）
+func hitID(w Widget) string {
+	if w == nil {
+		return ""
+	}
+	return w.GetID()
+}
+
+// TestHitTest_AnchoredWidget 测试锚点定位控件按计算后的位置命中
+func TestHitTest_AnchoredWidget(t *testing.T) {
+	btn := NewButton("btn")
+	btn.PositionMode = "anchor"
+	btn.AnchorX = "right"
+	btn.AnchorY = "bottom"
+	btn.OffsetX = -130
+	btn.OffsetY = -50
+
+	roots := []Widget{btn}
+
+	// 视口800x600，按钮位于(670,550)-(790,590)
+	if got := hitID(HitTest(roots, 800, 600, 700, 560)); got != "btn" {
+		t.Errorf("Expected btn at anchored position, got %q", got)
+	}
+	// 原始X/Y（0,0）处不应命中
+	if got := hitID(HitTest(roots, 800, 600, 5, 5)); got != "" {
+		t.Errorf("Expected no hit at raw X/Y, got %q", got)
+	}
+}
+
+// TestHitTest_ChildRelativeToParent 测试子控件相对父控件定位
+func TestHitTest_ChildRelativeToParent(t *testing.T) {
+	panel := NewPanel("panel")
+	panel.X, panel.Y = 100, 100
+	btn := NewButton("btn")
+	btn.X, btn.Y = 10, 10
+	panel.AddChild(btn)
+
+	roots := []Widget{panel}
+
+	if got := hitID(HitTest(roots, 800, 600, 115, 115)); got != "btn" {
+		t.Errorf("Expected btn inside panel, got %q", got)
+	}
+	if got := hitID(HitTest(roots, 800, 600, 15, 15)); got != "" {
+		t.Errorf("Expected no hit at child's raw position, got %q", got)
+	}
+}
+
+// TestHitTest_StretchedWidget 测试右/底边锚定拉伸后的尺寸
+func TestHitTest_StretchedWidget(t *testing.T) {
+	btn := NewButton("btn")
+	btn.X, btn.Y = 10, 10
+	btn.AnchorRight = true
+	btn.DesignMarginRight = 10
+	btn.AnchorBottom = true
+	btn.DesignMarginBottom = 10
+
+	roots := []Widget{btn}
+
+	// 拉伸后为(10,10)-(790,590)
+	if got := hitID(HitTest(roots, 800, 600, 700, 500)); got != "btn" {
+		t.Errorf("Expected stretched btn hit, got %q", got)
+	}
+	if got := hitID(HitTest(roots, 800, 600, 795, 500)); got != "" {
+		t.Errorf("Expected no hit in right margin, got %q", got)
+	}
+}
+
+// TestHitTest_ZOrder 测试重叠控件按z顺序命中
+func TestHitTest_ZOrder(t *testing.T) {
+	low := NewButton("low")
+	low.ZIndex = 1
+	high := NewButton("high")
+	high.ZIndex = 5
+
+	if got := hitID(HitTest([]Widget{high, low}, 800, 600, 20, 20)); got != "high" {
+		t.Errorf("Expected high z-index widget, got %q", got)
+	}
+
+	// z相同时后绘制（文档顺序靠后）的在上层
+	a := NewButton("a")
+	b := NewButton("b")
+	if got := hitID(HitTest([]Widget{a, b}, 800, 600, 20, 20)); got != "b" {
+		t.Errorf("Expected later sibling on top, got %q", got)
+	}
+}
+
+// TestHitTest_VisibilityAndInteractivity 测试隐藏和不可交互控件
+func TestHitTest_VisibilityAndInteractivity(t *testing.T) {
+	panel := NewPanel("panel")
+	btn := NewButton("btn")
+	panel.AddChild(btn)
+
+	roots := []Widget{panel}
+
+	// 面板不可交互但子按钮可交互
+	if got := hitID(HitTest(roots, 800, 600, 20, 20)); got != "btn" {
+		t.Errorf("Expected btn, got %q", got)
+	}
+	// 面板本身不可交互
+	if got := hitID(HitTest(roots, 800, 600, 300, 200)); got != "" {
+		t.Errorf("Expected no hit on non-interactive panel, got %q", got)
+	}
+	// 隐藏父控件时整个子树不可命中
+	panel.Visible = false
+	if got := hitID(HitTest(roots, 800, 600, 20, 20)); got != "" {
+		t.Errorf("Expected no hit in hidden subtree, got %q", got)
+	}
+}
+
+// TestHitTest_ParentClipping 测试裁剪父控件之外的区域不可命中
+func TestHitTest_ParentClipping(t *testing.T) {
+	list := NewListView("list")
+	list.Width, list.Height = 100, 100
+	btn := NewButton("btn")
+	btn.X, btn.Y = 50, 50 // 按钮延伸到列表之外
+	list.AddChild(btn)
+
+	roots := []Widget{list}
+
+	if got := hitID(HitTest(roots, 800, 600, 60, 60)); got != "btn" {
+		t.Errorf("Expected btn inside clip, got %q", got)
+	}
+	if got := hitID(HitTest(roots, 800, 600, 150, 60)); got != "" {
+		t.Errorf("Expected clipped area to miss, got %q", got)
+	}
+
+	// 视口之外同样不可命中
+	wide := NewButton("wide")
+	wide.X = 750
+	if got := hitID(HitTest([]Widget{wide}, 800, 600, 820, 20)); got != "" {
+		t.Errorf("Expected no hit outside viewport, got %q", got)
+	}
+}
+
+// TestHitTest_ClipChildren 测试面板开启clipChildren后超出边界的子控件部分不可命中，嵌套裁剪取交集
+func TestHitTest_ClipChildren(t *testing.T) {
+	outer := NewPanel("outer")
+	outer.Width, outer.Height = 100, 100
+	inner := NewPanel("inner")
+	inner.X, inner.Y = 50, 50
+	inner.Width, inner.Height = 150, 150
+	btn := NewButton("btn")
+	btn.Width, btn.Height = 100, 100
+	inner.AddChild(btn)
+	outer.AddChild(inner)
+	roots := []Widget{outer}
+
+	// 默认不裁剪
+	if got := hitID(HitTest(roots, 800, 600, 120, 120)); got != "btn" {
+		t.Errorf("Expected btn outside unclipped parent, got %q", got)
+	}
+
+	outer.ClipChildren = true
+	if got := hitID(HitTest(roots, 800, 600, 120, 120)); got != "" {
+		t.Errorf("Expected clipped area to miss, got %q", got)
+	}
+	if got := hitID(HitTest(roots, 800, 600, 80, 80)); got != "btn" {
+		t.Errorf("Expected btn inside clip, got %q", got)
+	}
+
+	// 内层裁剪与外层裁剪取交集
+	inner.ClipChildren = true
+	inner.X, inner.Y = -30, -30
+	if got := hitID(HitTest(roots, 800, 600, 10, 10)); got != "btn" {
+		t.Errorf("Expected btn inside both clips, got %q", got)
+	}
+	inner.Width, inner.Height = 50, 50
+	if got := hitID(HitTest(roots, 800, 600, 30, 30)); got != "" {
+		t.Errorf("Expected area clipped by inner panel to miss, got %q", got)
+	}
+}
+
+// TestHitTest_BorderRadius 测试圆角外的角落不可命中
+func TestHitTest_BorderRadius(t *testing.T) {
+	btn := NewButton("btn")
+	btn.BorderRadius = 20
+
+	roots := []Widget{btn}
+
+	if got := hitID(HitTest(roots, 800, 600, 1, 1)); got != "" {
+		t.Errorf("Expected corner outside radius to miss, got %q", got)
+	}
+	if got := hitID(HitTest(roots, 800, 600, 20, 2)); got != "btn" {
+		t.Errorf("Expected top edge center area to hit, got %q", got)
+	}
+	if got := hitID(HitTest(roots, 800, 600, 60, 20)); got != "btn" {
+		t.Errorf("Expected center to hit, got %q", got)
+	}
+}
+
+// TestPointInRoundedRect 测试圆角矩形点判断
+func TestPointInRoundedRect(t *testing.T) {
+	rect := image.Rect(0, 0, 100, 50)
+	tests := []struct {
+		pt     image.Point
+		radius int
+		want   bool
+	}{
+		{image.Pt(0, 0), 0, true},
+		{image.Pt(0, 0), 10, false},
+		{image.Pt(99, 49), 10, false},
+		{image.Pt(5, 5), 10, true},
+		{image.Pt(50, 0), 10, true},
+		{image.Pt(100, 10), 10, false},
+		{image.Pt(1, 1), 100, false}, // 半径被限制为短边的一半
+		{image.Pt(50, 25), 100, true},
+	}
+	for _, tt := range tests {
+		if got := pointInRoundedRect(tt.pt, rect, tt.radius); got != tt.want {
+			t.Errorf("pointInRoundedRect(%v, r=%d) = %v, want %v", tt.pt, tt.radius, got, tt.want)
+		}
+	}
+}
+
+// TestInputDispatcher_UsesLayoutHitTest 测试分发器使用视口计算命中
+func TestInputDispatcher_UsesLayoutHitTest(t *testing.T) {
+	btn := NewButton("btn")
+	btn.PositionMode = "anchor"
+	btn.AnchorX = "center"
+	btn.AnchorY = "middle"
+	btn.OffsetX = -60
+	btn.OffsetY = -20
+
+	src := newMockInputSource()
+	eq := NewEventQueue()
+	defer eq.Close()
+	d := NewInputDispatcher(src, eq)
+	d.SetRoots([]Widget{btn})
+	d.SetViewport(400, 300)
+
+	src.x, src.y = 200, 150
+	d.Update()
+	if hitID(d.GetHovered()) != "btn" {
+		t.Errorf("Expected btn hovered at viewport center, got %q", hitID(d.GetHovered()))
+	}
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"image"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -54,168 +55,79 @@ func (img *ImageWidget) Draw(screen *ebiten.Image, parentX, parentY, parentWidth
 	// 计算响应式尺寸（支持边界锚定）
 	renderWidth, renderHeight := img.CalculateSize(parentWidth, parentHeight, localX, localY)
 
-	// 注意：Image控件不绘制背景色/背景图片
-	// 只显示图片本身，保持透明背景
-
-	// 绘制图片
-	if img.image != nil {
-		img.drawImage(screen, absX, absY)
-	}
+	drawPainted(screen, img, image.Rect(absX, absY, absX+renderWidth, absY+renderHeight))
 
 	// 绘制子控件
 	img.BaseWidget.DrawChildren(screen, absX, absY, renderWidth, renderHeight)
 }
 
-// drawImage 根据缩放模式绘制图片
-func (img *ImageWidget) drawImage(dst *ebiten.Image, x, y int) {
-	// 第一步：处理源图裁剪
-	clipX := img.ClipX
-	clipY := img.ClipY
-	clipW := img.ClipWidth
-	clipH := img.ClipHeight
+// Paint 记录图片（在内容区域内按缩放模式绘制，超出内容区域的部分被裁剪）
+// 注意：Image控件不绘制背景色/背景图片，只显示图片本身，保持透明背景
+func (img *ImageWidget) Paint(list *DrawList, bounds image.Rectangle) {
+	if img.image == nil {
+		return
+	}
+	content := img.ContentRect(bounds)
+	source := img.sourceRect()
+	if content.Empty() || source.Empty() {
+		return
+	}
 
-	srcW, srcH := img.image.Size()
+	list.PushClip(content)
+	list.DrawSubImage(img.image, source, img.targetRect(content, source.Size()), img.Opacity)
+	list.PopClip()
+}
 
-	// 如果裁剪宽高为0，使用图片剩余尺寸
+// sourceRect 计算源图裁剪区域（裁剪宽高为0时使用图片剩余尺寸，并限制在图片范围内）
+func (img *ImageWidget) sourceRect() image.Rectangle {
+	srcW, srcH := img.image.Bounds().Dx(), img.image.Bounds().Dy()
+	clipX, clipY := max(img.ClipX, 0), max(img.ClipY, 0)
+	clipW, clipH := img.ClipWidth, img.ClipHeight
 	if clipW == 0 {
 		clipW = srcW - clipX
 	}
 	if clipH == 0 {
 		clipH = srcH - clipY
 	}
+	clipW = min(clipW, srcW-clipX)
+	clipH = min(clipH, srcH-clipY)
+	return image.Rect(clipX, clipY, clipX+clipW, clipY+clipH)
+}
 
-	// 防止越界
-	if clipX < 0 {
-		clipX = 0
-	}
-	if clipY < 0 {
-		clipY = 0
-	}
-	if clipX+clipW > srcW {
-		clipW = srcW - clipX
-	}
-	if clipY+clipH > srcH {
-		clipH = srcH - clipY
-	}
-
-	// 裁剪图像（如果需要）
-	sourceImg := img.image
-	if clipX != 0 || clipY != 0 || clipW != srcW || clipH != srcH {
-		sourceImg = img.image.SubImage(image.Rectangle{
-			Min: image.Point{X: clipX, Y: clipY},
-			Max: image.Point{X: clipX + clipW, Y: clipY + clipH},
-		}).(*ebiten.Image)
-	}
-
-	// 第二步：根据缩放模式计算目标绘制区域
-	dstW := float64(img.Width)
-	dstH := float64(img.Height)
-	srcWidth := float64(clipW)
-	srcHeight := float64(clipH)
-
-	op := &ebiten.DrawImageOptions{}
+// targetRect 根据缩放模式计算图片在内容区域中的绘制区域
+func (img *ImageWidget) targetRect(content image.Rectangle, src image.Point) image.Rectangle {
+	dstW := float64(content.Dx())
+	dstH := float64(content.Dy())
+	srcWidth := float64(src.X)
+	srcHeight := float64(src.Y)
 
 	switch img.ScaleMode {
-	case "fit":
-		// 等比例缩放，保持宽高比，完整显示（居中）
-		scale := dstW / srcWidth
-		if dstH/srcHeight < scale {
-			scale = dstH / srcHeight
-		}
-
-		scaledW := srcWidth * scale
-		scaledH := srcHeight * scale
-
-		// 居中
-		offsetX := (dstW - scaledW) / 2
-		offsetY := (dstH - scaledH) / 2
-
-		op.GeoM.Scale(scale, scale)
-		op.GeoM.Translate(float64(x)+offsetX, float64(y)+offsetY)
-
-		// fit模式需要裁剪超出部分
-		// 创建一个临时图像作为裁剪区域
-		clippedDst := dst.SubImage(image.Rectangle{
-			Min: image.Point{X: x, Y: y},
-			Max: image.Point{X: x + img.Width, Y: y + img.Height},
-		}).(*ebiten.Image)
-
-		// 应用透明度
-		if img.Opacity < 100 {
-			op.ColorScale.ScaleAlpha(float32(img.Opacity) / 100.0)
-		}
-
-		clippedDst.DrawImage(sourceImg, op)
-		return
-
 	case "fill":
 		// 填充整个区域，保持宽高比，可能裁剪（居中）
-		scale := dstW / srcWidth
-		if dstH/srcHeight > scale {
-			scale = dstH / srcHeight
-		}
-
-		scaledW := srcWidth * scale
-		scaledH := srcHeight * scale
-
-		// 居中
-		offsetX := (dstW - scaledW) / 2
-		offsetY := (dstH - scaledH) / 2
-
-		op.GeoM.Scale(scale, scale)
-		op.GeoM.Translate(float64(x)+offsetX, float64(y)+offsetY)
-
+		return centeredRect(content, srcWidth, srcHeight, math.Max(dstW/srcWidth, dstH/srcHeight))
 	case "stretch":
 		// 拉伸填充
-		scaleX := dstW / srcWidth
-		scaleY := dstH / srcHeight
-		op.GeoM.Scale(scaleX, scaleY)
-		op.GeoM.Translate(float64(x), float64(y))
-
+		return content
 	case "none":
 		// 不缩放，原始尺寸，左上角对齐
-		op.GeoM.Translate(float64(x), float64(y))
-
-		// none模式也需要裁剪超出部分
-		clippedDst := dst.SubImage(image.Rectangle{
-			Min: image.Point{X: x, Y: y},
-			Max: image.Point{X: x + img.Width, Y: y + img.Height},
-		}).(*ebiten.Image)
-
-		// 应用透明度
-		if img.Opacity < 100 {
-			op.ColorScale.ScaleAlpha(float32(img.Opacity) / 100.0)
-		}
-
-		clippedDst.DrawImage(sourceImg, op)
-		return
-
+		return image.Rectangle{Min: content.Min, Max: content.Min.Add(src)}
 	default:
-		// 默认fit模式
-		scale := dstW / srcWidth
-		if dstH/srcHeight < scale {
-			scale = dstH / srcHeight
-		}
-
-		scaledW := srcWidth * scale
-		scaledH := srcHeight * scale
-
-		offsetX := (dstW - scaledW) / 2
-		offsetY := (dstH - scaledH) / 2
-
-		op.GeoM.Scale(scale, scale)
-		op.GeoM.Translate(float64(x)+offsetX, float64(y)+offsetY)
-	}
-
-	// 应用透明度
-	if img.Opacity < 100 {
-		op.ColorScale.ScaleAlpha(float32(img.Opacity) / 100.0)
+		// fit（默认）：等比例缩放，保持宽高比，完整显示（居中）
+		return centeredRect(content, srcWidth, srcHeight, math.Min(dstW/srcWidth, dstH/srcHeight))
 	}
+}
 
-	dst.DrawImage(sourceImg, op)
+// centeredRect 按比例缩放后居中于区域内的矩形
+func centeredRect(area image.Rectangle, srcWidth, srcHeight, scale float64) image.Rectangle {
+	scaledW := int(math.Round(srcWidth * scale))
+	scaledH := int(math.Round(srcHeight * scale))
+	x := area.Min.X + (area.Dx()-scaledW)/2
+	y := area.Min.Y + (area.Dy()-scaledH)/2
+	return image.Rect(x, y, x+scaledW, y+scaledH)
 }
 
 // SetImage 设置图片
 func (img *ImageWidget) SetImage(image *ebiten.Image) {
 	img.image = image
+	img.MarkDirty()
 }
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// DefaultImagePoolSizeLimit 每种尺寸默认保留的空闲图像数量
+const DefaultImagePoolSizeLimit = 4
+
+// ImagePoolStats 图像池的统计信息（累计值）
+type ImagePoolStats struct {
+	Allocated int // 新创建的图像数
+	Reused    int // 从池中取出复用的图像数
+	Disposed  int // 释放的图像数
+	Idle      int // 当前池中空闲的图像数
+}
+
+// ImagePool 按尺寸复用的离屏图像池
+// 绘制过程中需要临时图像时从池中取出（Get）并在用完后放回（Put），稳定的画面不再每帧创建图像；
+// 图像的GPU资源只在超出尺寸上限、Trim或Dispose时显式释放
+type ImagePool struct {
+	// SizeLimit 每种尺寸保留的空闲图像数量上限（超出时放回的图像被释放，<=0表示不保留）
+	SizeLimit int
+
+	idle  map[image.Point][]*ebiten.Image
+	stats ImagePoolStats
+}
+
+// NewImagePool 创建图像池
+func NewImagePool() *ImagePool {
+	return &ImagePool{
+		SizeLimit: DefaultImagePoolSizeLimit,
+		idle:      make(map[image.Point][]*ebiten.Image),
+	}
+}
+
+// Get 取出指定尺寸的透明图像（池中没有时创建）
+func (p *ImagePool) Get(width, height int) *ebiten.Image {
+	size := image.Pt(max(width, 1), max(height, 1))
+	if images := p.idle[size]; len(images) > 0 {
+		img := images[len(images)-1]
+		images[len(images)-1] = nil
+		p.idle[size] = images[:len(images)-1]
+		p.stats.Idle--
+		p.stats.Reused++
+		img.Clear()
+		return img
+	}
+	p.stats.Allocated++
+	return ebiten.NewImage(size.X, size.Y)
+}
+
+// Put 放回用完的图像（同一帧内稍后取出复用是安全的，ebiten按提交顺序执行绘制命令）
+func (p *ImagePool) Put(img *ebiten.Image) {
+	if img == nil {
+		return
+	}
+	size := img.Bounds().Size()
+	if len(p.idle[size]) >= p.SizeLimit {
+		p.dispose(img)
+		return
+	}
+	p.idle[size] = append(p.idle[size], img)
+	p.stats.Idle++
+}
+
+// Trim 释放池中所有空闲图像
+func (p *ImagePool) Trim() {
+	for size, images := range p.idle {
+		for _, img := range images {
+			p.dispose(img)
+		}
+		delete(p.idle, size)
+	}
+	p.stats.Idle = 0
+}
+
+// Dispose 释放池中所有空闲图像（已经取出的图像由调用者负责放回或释放）
+func (p *ImagePool) Dispose() {
+	p.Trim()
+}
+
+// Stats 获取统计信息
+func (p *ImagePool) Stats() ImagePoolStats {
+	return p.stats
+}
+
+func (p *ImagePool) dispose(img *ebiten.Image) {
+	img.Dispose()
+	p.stats.Disposed++
+}
+
+// scratchImages 控件绘制时使用的临时图像池（ebiten后端的图层先绘制到离屏图像再整体应用透明度）
+var scratchImages = NewImagePool()
+
+// ScratchImagePool 获取控件绘制临时图像使用的图像池（可以调用Trim释放空闲图像，或者读取统计信息）
+func ScratchImagePool() *ImagePool {
+	return scratchImages
+}
+
+// whiteImage 1x1白色图像（DrawTriangles的纯色源图像，首次使用时创建）
+var whiteImage *ebiten.Image
+
+// whitePixel 获取纯色绘制使用的白色源图像
+func whitePixel() *ebiten.Image {
+	if whiteImage == nil {
+		whiteImage = ebiten.NewImage(1, 1)
+		whiteImage.Fill(color.White)
+	}
+	return whiteImage
+}
@@ -0,0 +1,468 @@
+package ui
+
+import (
+	"image"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// InputSource 输入源接口
+// 分发器通过该接口读取原始输入，测试时可以注入模拟输入而无需创建窗口
+type InputSource interface {
+	CursorPosition() (int, int)
+	IsMouseButtonPressed(button ebiten.MouseButton) bool
+	Wheel() (float64, float64)
+	AppendPressedKeys(keys []ebiten.Key) []ebiten.Key
+	AppendInputChars(runes []rune) []rune
+}
+
+// EbitenInputSource 基于ebiten的默认输入源
+type EbitenInputSource struct{}
+
+func (EbitenInputSource) CursorPosition() (int, int) { return ebiten.CursorPosition() }
+
+func (EbitenInputSource) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return ebiten.IsMouseButtonPressed(button)
+}
+
+func (EbitenInputSource) Wheel() (float64, float64) { return ebiten.Wheel() }
+
+func (EbitenInputSource) AppendPressedKeys(keys []ebiten.Key) []ebiten.Key {
+	return inpututil.AppendPressedKeys(keys)
+}
+
+func (EbitenInputSource) AppendInputChars(runes []rune) []rune {
+	return ebiten.AppendInputChars(runes)
+}
+
+// 事件中使用的鼠标按钮编号（0=左, 1=中, 2=右）
+var dispatchButtons = [3]ebiten.MouseButton{
+	ebiten.MouseButtonLeft,
+	ebiten.MouseButtonMiddle,
+	ebiten.MouseButtonRight,
+}
+
+// Modifiers 修饰键状态
+type Modifiers struct {
+	Shift bool
+	Ctrl  bool
+	Alt   bool
+	Meta  bool
+}
+
+// modifiersFromKeys 根据当前按下的键计算修饰键状态
+func modifiersFromKeys(keys []ebiten.Key) Modifiers {
+	var m Modifiers
+	for _, key := range keys {
+		switch key {
+		case ebiten.KeyShiftLeft, ebiten.KeyShiftRight:
+			m.Shift = true
+		case ebiten.KeyControlLeft, ebiten.KeyControlRight:
+			m.Ctrl = true
+		case ebiten.KeyAltLeft, ebiten.KeyAltRight:
+			m.Alt = true
+		case ebiten.KeyMetaLeft, ebiten.KeyMetaRight:
+			m.Meta = true
+		}
+	}
+	return m
+}
+
+// apply 将修饰键状态写入事件附加数据
+func (m Modifiers) apply(data map[string]interface{}) {
+	data["shiftKey"] = m.Shift
+	data["ctrlKey"] = m.Ctrl
+	data["altKey"] = m.Alt
+	data["metaKey"] = m.Meta
+}
+
+// defaultViewportSize 未设置视口时使用的默认尺寸
+const defaultViewportSize = 1 << 16
+
+// InputDispatcher 输入分发器
+// 每帧读取一次原始输入，跟踪指针和键盘状态，并把完整的控件事件推送到事件队列
+type InputDispatcher struct {
+	source     InputSource
+	eventQueue *EventQueue
+	roots      []Widget
+
+	// 视口尺寸（顶层控件的父容器）
+	viewportWidth  int
+	viewportHeight int
+
+	// 布局引擎（每帧开始时计算所有控件的边界）
+	layout *LayoutEngine
+
+	// 指针状态
+	cursorX, cursorY int
+	hasCursor        bool
+	hovered          Widget
+	buttonDown       [3]bool
+	pressTarget      [3]Widget // 按下时命中的控件（用于判断click，开始拖拽后清除）
+	pressedWidget    Widget    // 左键按下时处于按下状态的控件（开始拖拽后仍保留，抬起时清除）
+
+	// 键盘状态（键盘事件发往焦点控件）
+	focus    *FocusManager
+	keysDown map[ebiten.Key]bool
+	keyBuf   []ebiten.Key
+	charBuf  []rune
+
+	// 触摸与手势
+	touches  map[ebiten.TouchID]*touchPoint
+	touchBuf []ebiten.TouchID
+	gestures *GestureRecognizer
+
+	// 拖放
+	drag dragState
+
+	// 滚动面板（滚轮、滚动条、触摸拖动和惯性滚动）
+	scroll scrollState
+
+	// 悬停提示
+	tooltips *TooltipManager
+
+	// 覆盖层（弹出内容、模态对话框等）
+	layers *LayerManager
+
+	// 本帧的输入消耗情况
+	result InputResult
+
+	// 指针形状
+	cursorController CursorController
+	cursorShape      ebiten.CursorShapeType
+	cursorSet        bool
+
+	// 空间导航（键盘方向键/手柄）
+	navSource NavigationSource
+	navBuf    []NavAction
+
+	now func() time.Time
+}
+
+// NewInputDispatcher 创建输入分发器
+// source为nil时使用ebiten作为输入源
+func NewInputDispatcher(source InputSource, eventQueue *EventQueue) *InputDispatcher {
+	if source == nil {
+		source = EbitenInputSource{}
+	}
+	return &InputDispatcher{
+		source:     source,
+		eventQueue: eventQueue,
+		focus:      NewFocusManager(eventQueue),
+		keysDown:   make(map[ebiten.Key]bool),
+		touches:    make(map[ebiten.TouchID]*touchPoint),
+		gestures:   NewGestureRecognizer(DefaultGestureConfig()),
+		tooltips:   NewTooltipManager(DefaultTooltipConfig()),
+		layers:     NewLayerManager(),
+		layout:     NewLayoutEngine(),
+		now:        time.Now,
+
+		viewportWidth:  defaultViewportSize,
+		viewportHeight: defaultViewportSize,
+	}
+}
+
+// SetRoots 设置参与命中测试的顶层控件
+func (d *InputDispatcher) SetRoots(widgets []Widget) {
+	setWidgetState(d.hovered, WidgetStateHover, false, nil)
+	setWidgetState(d.pressedWidget, WidgetStatePressed, false, nil)
+	d.roots = widgets
+	d.hovered = nil
+	d.pressTarget = [3]Widget{}
+	d.pressedWidget = nil
+	for _, point := range d.touches {
+		if point.pressed {
+			setWidgetState(point.target, WidgetStatePressed, false, nil)
+		}
+		point.target = nil
+	}
+	syncWidgetStates(widgets, nil)
+	d.scroll = scrollState{}
+	d.layers.dismissAll()
+	d.focus.SetRoots(widgets)
+	d.layout.SetRoots(widgets)
+}
+
+// FocusManager 获取分发器使用的焦点管理器
+func (d *InputDispatcher) FocusManager() *FocusManager {
+	return d.focus
+}
+
+// Layout 获取分发器使用的布局引擎
+func (d *InputDispatcher) Layout() *LayoutEngine {
+	return d.layout
+}
+
+// TooltipManager 获取分发器使用的提示管理器（可用于调整显示延迟和样式）
+func (d *InputDispatcher) TooltipManager() *TooltipManager {
+	return d.tooltips
+}
+
+// GetHovered 获取当前悬停的控件
+func (d *InputDispatcher) GetHovered() Widget {
+	return d.hovered
+}
+
+// Update 读取本帧输入并分发事件（在ebiten的Update中每帧调用一次）
+// 返回本帧UI对输入的消耗情况，游戏逻辑据此决定是否处理世界输入
+func (d *InputDispatcher) Update() InputResult {
+	d.layout.Update()
+	viewport := image.Rect(0, 0, d.viewportWidth, d.viewportHeight)
+	d.layers.layout(viewport)
+	syncPopups(d.roots, d.layers)
+
+	x, y := d.source.CursorPosition()
+	moved := !d.hasCursor || x != d.cursorX || y != d.cursorY
+	d.cursorX, d.cursorY = x, y
+	d.hasCursor = true
+
+	d.keyBuf = d.source.AppendPressedKeys(d.keyBuf[:0])
+	mods := modifiersFromKeys(d.keyBuf)
+
+	d.focus.Validate()
+	syncWidgetStates(d.roots, d.push)
+	d.updateScrollInertia(d.now(), mods)
+
+	target := d.HitTest(x, y)
+
+	d.updateHover(target, x, y, moved, mods)
+	d.updateDrag(x, y, mods)
+	d.updateCursor(target)
+	d.updateButtons(target, x, y, mods)
+	d.updateScrollbarDrag(x, y, mods)
+	d.updateWheel(target, x, y, mods)
+	d.updateTouches(mods)
+	d.updateKeys(mods)
+	d.updateNavigation(mods)
+	d.updateScrollIntoView(mods)
+
+	// 指针位于弹出内容上时不显示下方控件的提示
+	pressed := d.buttonDown[0] || d.buttonDown[1] || d.buttonDown[2] || len(d.touches) > 0
+	tooltipRoots := d.roots
+	if _, ok := d.layers.stackAt(image.Pt(x, y)); ok {
+		tooltipRoots = nil
+	}
+	d.tooltips.Update(tooltipRoots, viewport, x, y, pressed, d.now())
+
+	d.result = d.computeResult(target)
+	return d.result
+}
+
+// updateHover 处理进入、离开和悬停移动，并维护控件的悬停状态
+func (d *InputDispatcher) updateHover(target Widget, x, y int, moved bool, mods Modifiers) {
+	if target != d.hovered {
+		if d.hovered != nil {
+			d.pushPointer(EventMouseLeave, d.hovered, x, y, 0, mods, nil)
+			setWidgetState(d.hovered, WidgetStateHover, false, d.push)
+		}
+		if target != nil {
+			d.pushPointer(EventMouseEnter, target, x, y, 0, mods, nil)
+			setWidgetState(target, WidgetStateHover, true, d.push)
+		}
+		d.hovered = target
+	}
+
+	if moved && target != nil {
+		d.pushPointer(EventHover, target, x, y, 0, mods, nil)
+	}
+}
+
+// updateButtons 处理按下、抬起和点击
+// 只有按下和抬起命中同一个控件时才产生click；左键按下的控件（已禁用的除外）在抬起前处于按下状态
+func (d *InputDispatcher) updateButtons(target Widget, x, y int, mods Modifiers) {
+	for i, button := range dispatchButtons {
+		pressed := d.source.IsMouseButtonPressed(button)
+		if pressed == d.buttonDown[i] {
+			continue
+		}
+		d.buttonDown[i] = pressed
+
+		if pressed {
+			// 在弹出内容外按下时先关闭弹出内容，关闭捕获输入的弹出内容时本次按下被消耗
+			if d.layers.dismissOnPress(image.Pt(x, y)) {
+				d.pressTarget[i] = nil
+				continue
+			}
+			d.pressTarget[i] = target
+			if target != nil {
+				d.pushPointer(EventMouseDown, target, x, y, i, mods, nil)
+			}
+			if i == 0 {
+				d.pressedWidget = target
+				d.setPressed(target, true)
+				d.updateFocusOnPress(target)
+				// 按在滚动条上时拖动滑块，不作为拖放的起点
+				dragTarget := target
+				if d.beginScrollbarDrag(target, x, y, mods) {
+					dragTarget = nil
+				}
+				d.beginDragCandidate(dragTarget, x, y)
+			}
+			continue
+		}
+
+		if i == 0 {
+			d.finishDrag(x, y, mods)
+		}
+
+		if target != nil {
+			d.pushPointer(EventMouseUp, target, x, y, i, mods, nil)
+			if target == d.pressTarget[i] {
+				d.pushPointer(EventClick, target, x, y, i, mods, nil)
+			}
+		}
+		if i == 0 {
+			d.setPressed(d.pressedWidget, false)
+			d.pressedWidget = nil
+		}
+		d.pressTarget[i] = nil
+	}
+}
+
+// setPressed 设置或清除控件的按下状态（已禁用的控件不进入按下状态）
+func (d *InputDispatcher) setPressed(widget Widget, pressed bool) {
+	if widget == nil || pressed && !isWidgetEnabled(widget) {
+		return
+	}
+	setWidgetState(widget, WidgetStatePressed, pressed, d.push)
+}
+
+// updateFocusOnPress 左键按下时将焦点移动到命中的可聚焦控件，点击其他位置则清除焦点
+// 按在弹出内容中不可聚焦的部分（例如下拉列表的选项）或模态遮罩上时保持原有焦点
+func (d *InputDispatcher) updateFocusOnPress(target Widget) {
+	switch {
+	case IsFocusable(target):
+		d.focus.Focus(target)
+	case d.layers.capturing() != nil, target != nil && d.layers.contentContains(target):
+	default:
+		d.focus.Blur()
+	}
+}
+
+// updateWheel 处理滚轮（推送wheel事件，并滚动指针下的滚动面板）
+func (d *InputDispatcher) updateWheel(target Widget, x, y int, mods Modifiers) {
+	dx, dy := d.source.Wheel()
+	if dx == 0 && dy == 0 {
+		return
+	}
+	if target != nil {
+		d.pushPointer(EventWheel, target, x, y, 0, mods, map[string]interface{}{
+			"deltaX": dx,
+			"deltaY": dy,
+		})
+	}
+	d.wheelScroll(x, y, dx, dy, mods)
+}
+
+// updateKeys 处理按键和文本输入
+func (d *InputDispatcher) updateKeys(mods Modifiers) {
+	current := make(map[ebiten.Key]bool, len(d.keyBuf))
+	for _, key := range d.keyBuf {
+		current[key] = true
+		if !d.keysDown[key] {
+			d.pushKey(EventKeyDown, key, mods)
+			if key == ebiten.KeyTab {
+				d.handleTab(mods)
+			}
+		}
+	}
+	for key := range d.keysDown {
+		if !current[key] {
+			d.pushKey(EventKeyUp, key, mods)
+		}
+	}
+	d.keysDown = current
+
+	d.charBuf = d.source.AppendInputChars(d.charBuf[:0])
+	target := d.keyTarget()
+	for _, ch := range d.charBuf {
+		if target == nil {
+			break
+		}
+		data := map[string]interface{}{
+			"key":  string(ch),
+			"code": int(ch),
+			"text": string(ch),
+		}
+		mods.apply(data)
+		d.push(WidgetEvent{
+			Type:     EventKeyPress,
+			WidgetID: target.GetID(),
+			Widget:   target,
+			Data:     data,
+		})
+	}
+}
+
+// handleTab Tab/Shift+Tab切换焦点
+func (d *InputDispatcher) handleTab(mods Modifiers) {
+	if mods.Shift {
+		d.focus.FocusPrevious()
+	} else {
+		d.focus.FocusNext()
+	}
+}
+
+// pushPointer 推送指针类事件
+func (d *InputDispatcher) pushPointer(eventType EventType, target Widget, x, y, button int, mods Modifiers, data map[string]interface{}) {
+	if data == nil {
+		data = make(map[string]interface{}, 4)
+	}
+	mods.apply(data)
+	d.push(WidgetEvent{
+		Type:     eventType,
+		WidgetID: target.GetID(),
+		Widget:   target,
+		X:        x,
+		Y:        y,
+		Button:   button,
+		Data:     data,
+	})
+	if r, ok := target.(pointerReactor); ok {
+		r.reactPointer(d, WidgetEvent{Type: eventType, WidgetID: target.GetID(), Widget: target, X: x, Y: y, Button: button, Data: data})
+	}
+}
+
+// pushKey 推送按键事件（发往当前焦点控件）
+func (d *InputDispatcher) pushKey(eventType EventType, key ebiten.Key, mods Modifiers) {
+	target := d.keyTarget()
+	if target == nil {
+		return
+	}
+	data := map[string]interface{}{
+		"key":  key.String(),
+		"code": int(key),
+	}
+	mods.apply(data)
+	d.push(WidgetEvent{
+		Type:     eventType,
+		WidgetID: target.GetID(),
+		Widget:   target,
+		Data:     data,
+	})
+}
+
+// push 补全时间戳并推送到事件队列
+func (d *InputDispatcher) push(event WidgetEvent) {
+	if d.eventQueue == nil {
+		return
+	}
+	event.Timestamp = d.now()
+	d.eventQueue.Push(event)
+}
+
+// SetViewport 设置视口尺寸（与绘制时传给顶层控件的父容器尺寸一致）
+func (d *InputDispatcher) SetViewport(width, height int) {
+	d.viewportWidth = width
+	d.viewportHeight = height
+	d.layout.SetViewport(width, height)
+}
+
+// HitTest 查找指定坐标下最上层的可交互控件
+// 弹出内容优先；存在捕获输入的弹出内容或可见的模态面板时只在其中查找
+func (d *InputDispatcher) HitTest(x, y int) Widget {
+	pt := image.Pt(x, y)
+	return d.stackOrderAt(pt).hitTest(pt)
+}
@@ -0,0 +1,258 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// mockInputSource 测试用的模拟输入源
+type mockInputSource struct {
+	x, y    int
+	buttons map[ebiten.MouseButton]bool
+	wheelX  float64
+	wheelY  float64
+	keys    []ebiten.Key
+	chars   []rune
+	touches map[ebiten.TouchID][2]int
+}
+
+func newMockInputSource() *mockInputSource {
+	return &mockInputSource{
+		buttons: make(map[ebiten.MouseButton]bool),
+		touches: make(map[ebiten.TouchID][2]int),
+	}
+}
+
+func (m *mockInputSource) CursorPosition() (int, int) { return m.x, m.y }
+func (m *mockInputSource) IsMouseButtonPressed(button ebiten.MouseButton) bool {
+	return m.buttons[button]
+}
+func (m *mockInputSource) Wheel() (float64, float64) { return m.wheelX, m.wheelY }
+func (m *mockInputSource) AppendPressedKeys(keys []ebiten.Key) []ebiten.Key {
+	return append(keys, m.keys...)
+}
+func (m *mockInputSource) AppendInputChars(runes []rune) []rune {
+	return append(runes, m.chars...)
+}
+func (m *mockInputSource) AppendTouchIDs(ids []ebiten.TouchID) []ebiten.TouchID {
+	for id := range m.touches {
+		ids = append(ids, id)
+	}
+	return ids
+}
+func (m *mockInputSource) TouchPosition(id ebiten.TouchID) (int, int) {
+	p := m.touches[id]
+	return p[0], p[1]
+}
+
+// drainEvents 取出队列中的所有事件
+func drainEvents(eq *EventQueue) []WidgetEvent {
+	var events []WidgetEvent
+	for {
+		event, ok := eq.TryPop()
+		if !ok {
+			return events
+		}
+		events = append(events, event)
+	}
+}
+
+// eventTypes 提取事件类型序列（可按控件过滤）
+func eventTypes(events []WidgetEvent, widgetID string) []EventType {
+	var types []EventType
+	for _, e := range events {
+		if widgetID == "" || e.WidgetID == widgetID {
+			types = append(types, e.Type)
+		}
+	}
+	return types
+}
+
+func equalEventTypes(a, b []EventType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// newDispatcherFixture 创建两个按钮并返回分发器
+func newDispatcherFixture() (*InputDispatcher, *mockInputSource, *EventQueue) {
+	btn1 := NewButton("btn1")
+	btn1.X, btn1.Y = 10, 10
+	btn2 := NewButton("btn2")
+	btn2.X, btn2.Y = 200, 10
+
+	src := newMockInputSource()
+	eq := NewEventQueue()
+	d := NewInputDispatcher(src, eq)
+	d.SetRoots([]Widget{btn1, btn2})
+	return d, src, eq
+}
+
+// TestInputDispatcher_ClickSameWidget 测试按下和抬起在同一控件上产生click
+func TestInputDispatcher_ClickSameWidget(t *testing.T) {
+	d, src, eq := newDispatcherFixture()
+	defer eq.Close()
+
+	src.x, src.y = 20, 20
+	d.Update()
+	drainEvents(eq)
+
+	src.buttons[ebiten.MouseButtonLeft] = true
+	d.Update()
+	src.buttons[ebiten.MouseButtonLeft] = false
+	d.Update()
+
+	got := eventTypes(drainEvents(eq), "btn1")
+	// 按下、获得焦点和抬起各伴随一个statechange
+	want := []EventType{EventMouseDown, EventStateChange, EventFocus, EventStateChange, EventMouseUp, EventClick, EventStateChange}
+	if !equalEventTypes(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestInputDispatcher_NoClickAcrossWidgets 测试在不同控件上按下和抬起不产生click
+func TestInputDispatcher_NoClickAcrossWidgets(t *testing.T) {
+	d, src, eq := newDispatcherFixture()
+	defer eq.Close()
+
+	src.x, src.y = 20, 20
+	src.buttons[ebiten.MouseButtonLeft] = true
+	d.Update()
+
+	src.x, src.y = 210, 20
+	src.buttons[ebiten.MouseButtonLeft] = false
+	d.Update()
+
+	for _, e := range drainEvents(eq) {
+		if e.Type == EventClick {
+			t.Errorf("Unexpected click on %s", e.WidgetID)
+		}
+	}
+}
+
+// TestInputDispatcher_EnterLeave 测试进入和离开事件
+func TestInputDispatcher_EnterLeave(t *testing.T) {
+	d, src, eq := newDispatcherFixture()
+	defer eq.Close()
+
+	src.x, src.y = 20, 20
+	d.Update()
+	src.x, src.y = 210, 20
+	d.Update()
+	src.x, src.y = 600, 600
+	d.Update()
+
+	events := drainEvents(eq)
+	got1 := eventTypes(events, "btn1")
+	want1 := []EventType{EventMouseEnter, EventStateChange, EventHover, EventMouseLeave, EventStateChange}
+	if !equalEventTypes(got1, want1) {
+		t.Errorf("btn1: expected %v, got %v", want1, got1)
+	}
+	got2 := eventTypes(events, "btn2")
+	want2 := []EventType{EventMouseEnter, EventStateChange, EventHover, EventMouseLeave, EventStateChange}
+	if !equalEventTypes(got2, want2) {
+		t.Errorf("btn2: expected %v, got %v", want2, got2)
+	}
+	if d.GetHovered() != nil {
+		t.Errorf("Expected no hovered widget, got %s", d.GetHovered().GetID())
+	}
+}
+
+// TestInputDispatcher_Wheel 测试滚轮事件
+func TestInputDispatcher_Wheel(t *testing.T) {
+	d, src, eq := newDispatcherFixture()
+	defer eq.Close()
+
+	src.x, src.y = 20, 20
+	src.wheelY = -2
+	d.Update()
+
+	var wheel *WidgetEvent
+	for _, e := range drainEvents(eq) {
+		if e.Type == EventWheel {
+			e := e
+			wheel = &e
+		}
+	}
+	if wheel == nil {
+		t.Fatal("Expected wheel event")
+	}
+	if wheel.WidgetID != "btn1" {
+		t.Errorf("Expected wheel on btn1, got %s", wheel.WidgetID)
+	}
+	if wheel.Data["deltaY"] != -2.0 {
+		t.Errorf("Expected deltaY -2, got %v", wheel.Data["deltaY"])
+	}
+}
+
+// TestInputDispatcher_KeysWithModifiers 测试按键和文本事件携带修饰键
+func TestInputDispatcher_KeysWithModifiers(t *testing.T) {
+	d, src, eq := newDispatcherFixture()
+	defer eq.Close()
+
+	// 点击btn1使其获得焦点（键盘事件发往焦点控件）
+	src.x, src.y = 20, 20
+	src.buttons[ebiten.MouseButtonLeft] = true
+	d.Update()
+	src.buttons[ebiten.MouseButtonLeft] = false
+	d.Update()
+	drainEvents(eq)
+
+	src.keys = []ebiten.Key{ebiten.KeyShiftLeft, ebiten.KeyA}
+	src.chars = []rune{'A'}
+	d.Update()
+
+	src.keys = nil
+	src.chars = nil
+	d.Update()
+
+	events := drainEvents(eq)
+	var sawKeyDownA, sawPress, sawKeyUpA bool
+	for _, e := range events {
+		if e.WidgetID != "btn1" {
+			t.Errorf("Key event sent to %s, expected btn1", e.WidgetID)
+		}
+		switch {
+		case e.Type == EventKeyDown && e.Data["key"] == ebiten.KeyA.String():
+			sawKeyDownA = true
+			if e.Data["shiftKey"] != true {
+				t.Error("Expected shiftKey on keydown")
+			}
+		case e.Type == EventKeyPress:
+			sawPress = true
+			if e.Data["key"] != "A" {
+				t.Errorf("Expected keypress key 'A', got %v", e.Data["key"])
+			}
+		case e.Type == EventKeyUp && e.Data["key"] == ebiten.KeyA.String():
+			sawKeyUpA = true
+		}
+	}
+	if !sawKeyDownA || !sawPress || !sawKeyUpA {
+		t.Errorf("Missing key events: keydown=%v keypress=%v keyup=%v", sawKeyDownA, sawPress, sawKeyUpA)
+	}
+}
+
+// TestInputDispatcher_IgnoresNonInteractive 测试不可交互控件不参与命中
+func TestInputDispatcher_IgnoresNonInteractive(t *testing.T) {
+	d, src, eq := newDispatcherFixture()
+	defer eq.Close()
+
+	label := NewLabel("label")
+	label.X, label.Y = 400, 10
+	d.SetRoots([]Widget{label})
+
+	src.x, src.y = 410, 20
+	src.buttons[ebiten.MouseButtonLeft] = true
+	d.Update()
+
+	if events := drainEvents(eq); len(events) != 0 {
+		t.Errorf("Expected no events, got %v", eventTypes(events, ""))
+	}
+}
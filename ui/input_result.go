@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"image"
+)
+
+// InputResult 一帧中UI对输入的消耗情况
+// 游戏循环在PointerCaptured为true时跳过世界中的点击/拾取，在KeyboardCaptured为true时跳过键盘控制
+type InputResult struct {
+	PointerCaptured  bool // 指针位于可交互控件上、正在与控件交互（按下/拖拽/触摸），或有模态面板
+	KeyboardCaptured bool // 文本输入框获得焦点或有模态面板
+	TextInputActive  bool // 文本输入框获得焦点（游戏应忽略字符输入和快捷键）
+
+	Widget  Widget // 捕获指针的控件（按下时为按下的控件，否则为悬停的控件）
+	Focused Widget // 当前焦点控件
+	Modal   Widget // 当前最上层的可见模态面板
+}
+
+// LastResult 获取最近一次Update的输入消耗情况
+func (d *InputDispatcher) LastResult() InputResult {
+	return d.result
+}
+
+// computeResult 计算本帧的输入消耗情况
+func (d *InputDispatcher) computeResult(target Widget) InputResult {
+	result := InputResult{
+		Widget:  target,
+		Focused: d.focus.GetFocused(),
+	}
+
+	// 按下后即使移出控件，指针仍由按下的控件捕获
+	for i := range d.pressTarget {
+		if d.buttonDown[i] && d.pressTarget[i] != nil {
+			result.Widget = d.pressTarget[i]
+			break
+		}
+	}
+	if d.drag.active {
+		result.Widget = d.drag.source
+	}
+	result.PointerCaptured = result.Widget != nil
+	for _, point := range d.touches {
+		if point.target != nil {
+			result.PointerCaptured = true
+		}
+	}
+
+	if _, ok := result.Focused.(*TextInputWidget); ok {
+		result.TextInputActive = true
+		result.KeyboardCaptured = true
+	}
+
+	viewport := image.Rect(0, 0, d.viewportWidth, d.viewportHeight)
+	if modal, _, ok := findModal(d.roots, viewport); ok {
+		result.Modal = modal
+		result.PointerCaptured = true
+		result.KeyboardCaptured = true
+	}
+	if popup := d.layers.capturing(); popup != nil {
+		result.Modal = popup.Content
+		result.PointerCaptured = true
+		result.KeyboardCaptured = true
+	}
+	return result
+}
+
+// findModal 按层叠顺序查找最上层的可见模态面板，同时返回其父容器的绝对边界
+func findModal(roots []Widget, viewport image.Rectangle) (Widget, image.Rectangle, bool) {
+	order := buildStackOrder(roots, viewport, viewport)
+	for i := len(order) - 1; i >= 0; i-- {
+		e := order[i]
+		if m, ok := e.widget.(interface{ IsModal() bool }); ok && m.IsModal() && !e.scrollbar {
+			return e.widget, e.parent, true
+		}
+	}
+	return nil, image.Rectangle{}, false
+}
+
+// stackOrder 获取指针查找使用的层叠顺序，存在可见的模态面板时只包含模态面板的子树
+func (d *InputDispatcher) stackOrder() stackOrder {
+	viewport := image.Rect(0, 0, d.viewportWidth, d.viewportHeight)
+	if modal, parent, ok := findModal(d.roots, viewport); ok {
+		return buildStackOrder([]Widget{modal}, parent, viewport)
+	}
+	return buildStackOrder(d.roots, viewport, viewport)
+}
+
+// keyTarget 获取键盘事件的目标控件
+// 存在模态面板时，模态面板外的焦点控件不再接收键盘输入
+func (d *InputDispatcher) keyTarget() Widget {
+	focused := d.focus.GetFocused()
+	if focused == nil {
+		return nil
+	}
+	if popup := d.layers.capturing(); popup != nil && !containsWidget(popup.Content, focused) {
+		return nil
+	}
+	viewport := image.Rect(0, 0, d.viewportWidth, d.viewportHeight)
+	if modal, _, ok := findModal(d.roots, viewport); ok && !containsWidget(modal, focused) && !d.layers.contentContains(focused) {
+		return nil
+	}
+	return focused
+}
+
+// containsWidget 判断target是否为root自身或其后代
+func containsWidget(root, target Widget) bool {
+	if root == target {
+		return true
+	}
+	for _, child := range root.GetChildren() {
+		if containsWidget(child, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// TestInputResult_Pointer 测试指针在控件上或按下控件后拖出时被捕获
+func TestInputResult_Pointer(t *testing.T) {
+	d, src, eq := newDispatcherFixture()
+	defer eq.Close()
+
+	src.x, src.y = 600, 600
+	if result := d.Update(); result.PointerCaptured || result.Widget != nil {
+		t.Errorf("Expected pointer not captured over empty space, got %+v", result)
+	}
+
+	src.x, src.y = 20, 20
+	result := d.Update()
+	if !result.PointerCaptured || result.Widget == nil || result.Widget.GetID() != "btn1" {
+		t.Fatalf("Expected pointer captured by btn1, got %+v", result)
+	}
+
+	// 按下后移出控件，直到抬起前仍然被捕获
+	src.buttons[ebiten.MouseButtonLeft] = true
+	d.Update()
+	src.x, src.y = 600, 600
+	if result := d.Update(); !result.PointerCaptured || result.Widget.GetID() != "btn1" {
+		t.Errorf("Expected pointer still captured while pressed, got %+v", result)
+	}
+	src.buttons[ebiten.MouseButtonLeft] = false
+	if result := d.Update(); result.PointerCaptured {
+		t.Errorf("Expected pointer released, got %+v", result)
+	}
+	if d.LastResult().PointerCaptured {
+		t.Error("LastResult should match the last Update")
+	}
+}
+
+// TestInputResult_TextInput 测试文本输入框获得焦点时捕获键盘
+func TestInputResult_TextInput(t *testing.T) {
+	btn := NewButton("btn")
+	btn.X, btn.Y = 10, 10
+	input := NewTextInput("input")
+	input.X, input.Y = 200, 10
+
+	src := newMockInputSource()
+	d := NewInputDispatcher(src, nil)
+	d.SetRoots([]Widget{btn, input})
+
+	d.FocusManager().Focus(btn)
+	if result := d.Update(); result.KeyboardCaptured || result.TextInputActive {
+		t.Errorf("Focused button should not capture keyboard, got %+v", result)
+	}
+
+	d.FocusManager().Focus(input)
+	result := d.Update()
+	if !result.KeyboardCaptured || !result.TextInputActive || result.Focused != input {
+		t.Errorf("Expected keyboard captured by text input, got %+v", result)
+	}
+}
+
+// TestInputResult_Modal 测试模态面板屏蔽下方控件并消耗键盘输入
+func TestInputResult_Modal(t *testing.T) {
+	background := NewButton("background")
+	background.X, background.Y = 10, 10
+
+	modal := NewPanel("modal")
+	modal.X, modal.Y = 300, 0
+	modal.Modal = true
+	ok := NewButton("ok")
+	ok.X, ok.Y = 10, 10
+	modal.AddChild(ok)
+
+	src := newMockInputSource()
+	eq := NewEventQueue()
+	defer eq.Close()
+	d := NewInputDispatcher(src, eq)
+	d.SetRoots([]Widget{background, modal})
+	d.FocusManager().Focus(background)
+	drainEvents(eq)
+
+	// 模态面板外的控件不再命中
+	src.x, src.y = 20, 20
+	result := d.Update()
+	if result.Widget != nil || !result.PointerCaptured || !result.KeyboardCaptured || result.Modal != modal {
+		t.Errorf("Expected modal to capture all input, got %+v", result)
+	}
+
+	// 模态面板外的焦点控件不接收按键
+	src.keys = []ebiten.Key{ebiten.KeyA}
+	d.Update()
+	src.keys = nil
+	if events := drainEvents(eq); len(events) != 0 {
+		t.Errorf("Expected no events outside the modal, got %v", eventTypes(events, ""))
+	}
+
+	// 模态面板内的控件正常命中
+	src.x, src.y = 320, 20
+	if result := d.Update(); result.Widget != ok {
+		t.Errorf("Expected ok button inside modal, got %+v", result)
+	}
+
+	modal.Visible = false
+	src.x, src.y = 20, 20
+	if result := d.Update(); result.Widget != background || result.KeyboardCaptured {
+		t.Errorf("Expected normal input after closing the modal, got %+v", result)
+	}
+}
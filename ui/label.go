@@ -1,7 +1,7 @@
 package ui
 
 import (
-	"image/color"
+	"image"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/text"
@@ -60,96 +60,98 @@ func (l *LabelWidget) Draw(screen *ebiten.Image, parentX, parentY, parentWidth,
 	absX := parentX + localX
 	absY := parentY + localY
 
-	// 绘制背景（如果有）
-	if l.BackgroundAlpha > 0 || l.backgroundImage != nil {
-		lblImage := ebiten.NewImage(l.Width, l.Height)
-
-		// 背景颜色
-		if l.BackgroundAlpha > 0 {
-			bgColor := RGBA{
-				R: l.BackgroundColor.R,
-				G: l.BackgroundColor.G,
-				B: l.BackgroundColor.B,
-				A: l.BackgroundAlpha,
-			}
-			lblImage.Fill(bgColor.ToColor())
-		}
-
-		// 背景图片
-		if l.backgroundImage != nil {
-			op := &ebiten.DrawImageOptions{}
-			srcW, srcH := l.backgroundImage.Size()
-			scaleX := float64(l.Width) / float64(srcW)
-			scaleY := float64(l.Height) / float64(srcH)
-			op.GeoM.Scale(scaleX, scaleY)
-			lblImage.DrawImage(l.backgroundImage, op)
-		}
-
-		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(float64(absX), float64(absY))
-		screen.DrawImage(lblImage, op)
-	}
-
-	// 绘制文本
-	if l.Text != "" {
-		l.drawText(screen, absX, absY)
-	}
-
 	// 计算响应式尺寸（支持边界锚定）
 	renderWidth, renderHeight := l.CalculateSize(parentWidth, parentHeight, localX, localY)
 
+	drawPainted(screen, l, image.Rect(absX, absY, absX+renderWidth, absY+renderHeight))
+
 	// 绘制子控件
 	l.BaseWidget.DrawChildren(screen, absX, absY, renderWidth, renderHeight)
 }
 
-// drawText 绘制文本
-func (l *LabelWidget) drawText(dst *ebiten.Image, x, y int) {
+// Paint 记录标签的阴影、背景（颜色或渐变）、背景图片、边框和文本
+func (l *LabelWidget) Paint(list *DrawList, bounds image.Rectangle) {
+	l.paintOuterShadows(list, bounds, 100)
+
+	// 背景颜色或渐变
+	l.paintFill(list, bounds, RGBA{
+		R: l.BackgroundColor.R,
+		G: l.BackgroundColor.G,
+		B: l.BackgroundColor.B,
+		A: l.BackgroundAlpha,
+	})
+
+	// 背景图片
+	paintBackgroundImage(list, l.backgroundImage, l.BackgroundSliceFor(l.backgroundImage), bounds)
+	l.paintInnerShadows(list, bounds)
+
+	// 边框
+	l.paintBorder(list, bounds)
+
+	// 绘制文本
+	if l.Text != "" {
+		l.paintText(list, bounds)
+	}
+}
+
+// paintText 记录文本
+func (l *LabelWidget) paintText(list *DrawList, rect image.Rectangle) {
 	if l.Font == nil {
 		l.Font = basicfont.Face7x13
 	}
 
-	textColor := color.RGBA{
+	textColor := RGBA{
 		R: l.TextColor.R,
 		G: l.TextColor.G,
 		B: l.TextColor.B,
 		A: l.TextColorAlpha,
 	}
 
-	// 计算文本位置
+	// 计算文本位置（在内容区域内对齐）
 	bounds := text.BoundString(l.Font, l.Text)
 	textWidth := bounds.Dx()
+	content := l.ContentRect(rect)
 
 	var textX, textY int
 
 	// 水平对齐
 	switch l.TextAlignment {
 	case "left":
-		textX = x + l.BaseWidget.Padding.Left
+		textX = content.Min.X
 	case "right":
-		textX = x + l.BaseWidget.Width - textWidth - l.BaseWidget.Padding.Right
+		textX = content.Max.X - textWidth
 	case "center":
-		textX = x + (l.BaseWidget.Width-textWidth)/2
+		textX = content.Min.X + (content.Dx()-textWidth)/2
 	default:
-		textX = x + l.BaseWidget.Padding.Left
+		textX = content.Min.X
 	}
 
 	// 垂直对齐 - 修正基线偏移
 	switch l.VerticalAlign {
 	case "top":
-		textY = y + l.BaseWidget.Padding.Top - bounds.Min.Y
+		textY = content.Min.Y - bounds.Min.Y
 	case "bottom":
-		textY = y + l.BaseWidget.Height - l.BaseWidget.Padding.Bottom - bounds.Max.Y
+		textY = content.Max.Y - bounds.Max.Y
 	case "middle":
 		// 真正的文本高度 = bounds.Max.Y - bounds.Min.Y
-		textY = y + l.BaseWidget.Height/2 - bounds.Min.Y - (bounds.Max.Y-bounds.Min.Y)/2
+		textY = content.Min.Y + content.Dy()/2 - bounds.Min.Y - (bounds.Max.Y-bounds.Min.Y)/2
 	default:
-		textY = y + l.BaseWidget.Height/2 - bounds.Min.Y - (bounds.Max.Y-bounds.Min.Y)/2
+		textY = content.Min.Y + content.Dy()/2 - bounds.Min.Y - (bounds.Max.Y-bounds.Min.Y)/2
 	}
 
-	text.Draw(dst, l.Text, l.Font, textX, textY, textColor)
+	list.DrawText(l.Text, l.Font, textX, textY, textColor)
+}
+
+// ContentSize 计算文本内容加内边距的尺寸（用于布局的内容尺寸）
+func (l *LabelWidget) ContentSize() (int, int) {
+	if l.Font == nil {
+		l.Font = basicfont.Face7x13
+	}
+	return textContentSize(l.Font, l.Text, l.Padding)
 }
 
 // SetText 设置文本
 func (l *LabelWidget) SetText(text string) {
+	l.MarkDirty()
 	l.Text = text
 }
@@ -0,0 +1,316 @@
+package ui
+
+import (
+	"image"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Layer 覆盖层的层级（数值大的绘制在上层）
+type Layer int
+
+const (
+	LayerBase    Layer = iota // 普通控件树
+	LayerPopup                // 下拉列表、菜单等弹出内容
+	LayerModal                // 模态对话框
+	LayerTooltip              // 悬停提示
+	LayerDebug                // 调试信息
+)
+
+// interactive 该层的内容是否参与指针输入
+func (l Layer) interactive() bool {
+	return l == LayerPopup || l == LayerModal
+}
+
+// Popup 推入覆盖层的临时内容
+type Popup struct {
+	Content Widget // 内容控件（X/Y为视口坐标）
+	Layer   Layer
+	Owner   Widget // 打开弹出内容的控件（按在其上不算点击外部），可以为nil
+
+	DismissOnClickOutside bool // 在内容和Owner之外按下时关闭
+	DismissOnEscape       bool // 返回动作（Escape/手柄返回键）关闭最上层的该类弹出内容
+	CaptureInput          bool // 打开期间屏蔽下方所有层的指针和键盘输入
+
+	// 遮罩（绘制在内容下方、覆盖整个视口，透明度为0时不绘制）
+	BackdropColor RGBA
+	BackdropAlpha uint8
+
+	OnDismiss func() // 关闭时调用（包括点击外部、Escape和Dismiss）
+}
+
+// NewPopup 创建弹出内容，按层级设置默认行为
+// 弹出层点击外部或按Escape关闭；模态层捕获输入并绘制半透明遮罩
+func NewPopup(content Widget, layer Layer) *Popup {
+	p := &Popup{Content: content, Layer: layer}
+	switch layer {
+	case LayerPopup:
+		p.DismissOnClickOutside = true
+		p.DismissOnEscape = true
+	case LayerModal:
+		p.DismissOnEscape = true
+		p.CaptureInput = true
+		p.BackdropAlpha = 128
+	}
+	return p
+}
+
+// LayerManager 覆盖层管理器
+// 控件把临时内容推入弹出层、模态层、提示层或调试层，管理器负责布局、绘制、输入捕获和点击外部关闭
+type LayerManager struct {
+	popups   []*Popup // 按层级升序排列，同一层级按打开顺序
+	viewport image.Rectangle
+}
+
+// NewLayerManager 创建覆盖层管理器
+func NewLayerManager() *LayerManager {
+	return &LayerManager{}
+}
+
+// Push 打开弹出内容（已经打开时移到同一层级的最上方）
+func (m *LayerManager) Push(p *Popup) {
+	m.remove(p)
+	m.popups = append(m.popups, p)
+	sort.SliceStable(m.popups, func(i, j int) bool {
+		return m.popups[i].Layer < m.popups[j].Layer
+	})
+	if !m.viewport.Empty() {
+		PerformLayout([]Widget{p.Content}, m.viewport.Dx(), m.viewport.Dy())
+	}
+}
+
+// Dismiss 关闭弹出内容，返回是否原本处于打开状态
+func (m *LayerManager) Dismiss(p *Popup) bool {
+	if !m.remove(p) {
+		return false
+	}
+	if p.OnDismiss != nil {
+		p.OnDismiss()
+	}
+	return true
+}
+
+// DismissLayer 关闭指定层级的所有弹出内容
+func (m *LayerManager) DismissLayer(layer Layer) {
+	popups := m.Popups(layer)
+	for i := len(popups) - 1; i >= 0; i-- {
+		m.Dismiss(popups[i])
+	}
+}
+
+// IsOpen 弹出内容是否处于打开状态
+func (m *LayerManager) IsOpen(p *Popup) bool {
+	for _, open := range m.popups {
+		if open == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Popups 获取指定层级打开的弹出内容（由下到上）
+func (m *LayerManager) Popups(layer Layer) []*Popup {
+	var out []*Popup
+	for _, p := range m.popups {
+		if p.Layer == layer {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// remove 从列表中移除弹出内容
+func (m *LayerManager) remove(p *Popup) bool {
+	for i, open := range m.popups {
+		if open == p {
+			m.popups = append(m.popups[:i], m.popups[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// layout 布局所有弹出内容（视口为其父容器）
+func (m *LayerManager) layout(viewport image.Rectangle) {
+	m.viewport = viewport
+	if len(m.popups) == 0 {
+		return
+	}
+	contents := make([]Widget, len(m.popups))
+	for i, p := range m.popups {
+		contents[i] = p.Content
+	}
+	PerformLayout(contents, viewport.Dx(), viewport.Dy())
+}
+
+// stackAt 查找指针所在的弹出内容，返回其层叠顺序
+// ok=false表示指针不在任何可交互的弹出内容上，也没有被捕获输入的弹出内容挡住，输入交给普通控件树；
+// 被挡住时返回空的层叠顺序
+func (m *LayerManager) stackAt(pt image.Point) (stackOrder, bool) {
+	for i := len(m.popups) - 1; i >= 0; i-- {
+		p := m.popups[i]
+		if !p.Layer.interactive() || !p.Content.IsVisible() {
+			continue
+		}
+		if order := m.stackOrder(p); order.contains(pt) {
+			return order, true
+		}
+		if p.CaptureInput {
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+// stackOrder 弹出内容的层叠顺序
+func (m *LayerManager) stackOrder(p *Popup) stackOrder {
+	return buildStackOrder([]Widget{p.Content}, m.viewport, m.viewport)
+}
+
+// contains 点是否在层叠顺序中的某个控件的可见区域内
+func (s stackOrder) contains(pt image.Point) bool {
+	return s.topmost(pt, func(stackEntry) bool { return true }) != nil
+}
+
+// capturing 获取最上层捕获输入的弹出内容
+func (m *LayerManager) capturing() *Popup {
+	for i := len(m.popups) - 1; i >= 0; i-- {
+		if p := m.popups[i]; p.Layer.interactive() && p.CaptureInput && p.Content.IsVisible() {
+			return p
+		}
+	}
+	return nil
+}
+
+// contentContains 判断控件是否位于某个打开的弹出内容中
+func (m *LayerManager) contentContains(widget Widget) bool {
+	for _, p := range m.popups {
+		if containsWidget(p.Content, widget) {
+			return true
+		}
+	}
+	return false
+}
+
+// dismissOnPress 按下时由上到下关闭指针外部的可点击关闭的弹出内容
+// 遇到包含指针的弹出内容或捕获输入的弹出内容时停止；关闭了捕获输入的弹出内容时本次按下被消耗，返回true
+func (m *LayerManager) dismissOnPress(pt image.Point) bool {
+	// 关闭回调可能打开或关闭其他弹出内容，因此遍历副本
+	popups := append([]*Popup(nil), m.popups...)
+	for i := len(popups) - 1; i >= 0; i-- {
+		p := popups[i]
+		if !m.IsOpen(p) || !p.Layer.interactive() || !p.Content.IsVisible() {
+			continue
+		}
+		if m.stackOrder(p).contains(pt) || ownerContains(p.Owner, pt) {
+			return false
+		}
+		if p.DismissOnClickOutside {
+			m.Dismiss(p)
+			if p.CaptureInput {
+				return true
+			}
+			continue
+		}
+		if p.CaptureInput {
+			return false
+		}
+	}
+	return false
+}
+
+// ownerContains 点是否在弹出内容所属控件的边界内（使用布局过程缓存的边界）
+func ownerContains(owner Widget, pt image.Point) bool {
+	if owner == nil {
+		return false
+	}
+	c, ok := owner.(computedLayout)
+	if !ok {
+		return false
+	}
+	box, ok := c.GetComputedLayout()
+	return ok && pt.In(box.Bounds)
+}
+
+// dismissTop 关闭最上层的可用返回动作关闭的弹出内容，返回是否关闭了弹出内容
+func (m *LayerManager) dismissTop() bool {
+	for i := len(m.popups) - 1; i >= 0; i-- {
+		if p := m.popups[i]; p.DismissOnEscape {
+			return m.Dismiss(p)
+		}
+	}
+	return false
+}
+
+// dismissAll 关闭所有弹出内容
+func (m *LayerManager) dismissAll() {
+	for len(m.popups) > 0 {
+		m.Dismiss(m.popups[len(m.popups)-1])
+	}
+}
+
+// Draw 绘制指定层级的弹出内容（由下到上，每个内容先绘制其遮罩）
+func (m *LayerManager) Draw(screen *ebiten.Image, layer Layer) {
+	for _, p := range m.Popups(layer) {
+		if !p.Content.IsVisible() {
+			continue
+		}
+		if p.BackdropAlpha > 0 {
+			backdrop := p.BackdropColor
+			backdrop.A = p.BackdropAlpha
+			vector.DrawFilledRect(screen, float32(m.viewport.Min.X), float32(m.viewport.Min.Y),
+				float32(m.viewport.Dx()), float32(m.viewport.Dy()), backdrop.ToColor(), false)
+		}
+		DrawWidgets(screen, []Widget{p.Content}, m.viewport.Dx(), m.viewport.Dy())
+	}
+}
+
+// pointerReactor 对指针事件做出内部反应的控件（例如下拉框的展开和选项选择）
+// 分发器推送事件后调用，脚本处理器仍然收到同一事件
+type pointerReactor interface {
+	reactPointer(d *InputDispatcher, event WidgetEvent)
+}
+
+// popupOwner 按自身属性打开或关闭弹出内容的控件（例如下拉框的IsExpanded）
+type popupOwner interface {
+	syncPopup(layers *LayerManager)
+}
+
+// syncPopups 按控件属性同步控件树的弹出内容（属性可能在加载时或由脚本直接设置，而不是通过输入打开）
+func syncPopups(widgets []Widget, layers *LayerManager) {
+	for _, w := range widgets {
+		if o, ok := w.(popupOwner); ok {
+			o.syncPopup(layers)
+		}
+		syncPopups(w.GetChildren(), layers)
+	}
+}
+
+// Layers 获取分发器使用的覆盖层管理器
+func (d *InputDispatcher) Layers() *LayerManager {
+	return d.layers
+}
+
+// stackOrderAt 获取指定坐标的指针查找使用的层叠顺序
+// 指针在弹出内容上（或被捕获输入的弹出内容挡住）时使用弹出内容，否则使用普通控件树
+func (d *InputDispatcher) stackOrderAt(pt image.Point) stackOrder {
+	if order, ok := d.layers.stackAt(pt); ok {
+		return order
+	}
+	return d.stackOrder()
+}
+
+// DrawOverlay 按层级绘制覆盖层内容（在所有控件之后调用）
+// 弹出层、模态层、拖拽影像、提示层（包括悬停提示）、调试层
+func (d *InputDispatcher) DrawOverlay(screen *ebiten.Image) {
+	d.layers.Draw(screen, LayerPopup)
+	d.layers.Draw(screen, LayerModal)
+	if d.drag.active {
+		d.drawDragGhost(screen)
+	}
+	d.layers.Draw(screen, LayerTooltip)
+	d.tooltips.Draw(screen)
+	d.layers.Draw(screen, LayerDebug)
+}
@@ -0,0 +1,256 @@
+package ui
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// clickAt 在指定位置按下并抬起左键（两帧）
+func clickAt(d *InputDispatcher, src *mockInputSource, x, y int) {
+	src.x, src.y = x, y
+	src.buttons[ebiten.MouseButtonLeft] = true
+	d.Update()
+	src.buttons[ebiten.MouseButtonLeft] = false
+	d.Update()
+}
+
+// newPopupContent 创建位于视口坐标的弹出内容（包含一个可点击的按钮）
+func newPopupContent(id string, x, y, w, h int) (*PanelWidget, *ButtonWidget) {
+	content := NewPanel(id)
+	content.X, content.Y = x, y
+	content.Width, content.Height = w, h
+	btn := NewButton(id + "-btn")
+	btn.Width, btn.Height = w, h
+	content.AddChild(btn)
+	return content, btn
+}
+
+// TestLayerManager_PushAndDismiss 测试弹出内容按层级排列，关闭时调用回调
+func TestLayerManager_PushAndDismiss(t *testing.T) {
+	m := NewLayerManager()
+	modalContent, _ := newPopupContent("modal", 0, 0, 10, 10)
+	menuContent, _ := newPopupContent("menu", 0, 0, 10, 10)
+	modal := NewPopup(modalContent, LayerModal)
+	menu := NewPopup(menuContent, LayerPopup)
+
+	dismissed := 0
+	menu.OnDismiss = func() { dismissed++ }
+
+	m.Push(modal)
+	m.Push(menu)
+	if m.popups[0] != menu || m.popups[1] != modal {
+		t.Errorf("Expected popup layer below modal layer regardless of push order")
+	}
+	if !modal.CaptureInput || modal.BackdropAlpha == 0 || !menu.DismissOnClickOutside {
+		t.Errorf("Unexpected layer defaults: modal %+v, menu %+v", modal, menu)
+	}
+
+	if !m.Dismiss(menu) || m.Dismiss(menu) {
+		t.Error("Expected Dismiss to report the first close only")
+	}
+	if dismissed != 1 || m.IsOpen(menu) {
+		t.Errorf("Expected one dismiss callback, got %d", dismissed)
+	}
+
+	m.DismissLayer(LayerModal)
+	if len(m.Popups(LayerModal)) != 0 {
+		t.Error("Expected modal layer to be empty")
+	}
+}
+
+// TestInputDispatcher_PopupAboveBaseAndClickOutside 测试弹出内容优先命中，在外部按下时关闭且按下传给下方控件
+func TestInputDispatcher_PopupAboveBaseAndClickOutside(t *testing.T) {
+	base := NewButton("base")
+	base.Width, base.Height = 300, 300
+
+	src := newMockInputSource()
+	eq := NewEventQueue()
+	defer eq.Close()
+	d := NewInputDispatcher(src, eq)
+	d.SetRoots([]Widget{base})
+	d.SetViewport(800, 600)
+	d.Update()
+
+	content, _ := newPopupContent("menu", 50, 50, 100, 100)
+	menu := NewPopup(content, LayerPopup)
+	d.Layers().Push(menu)
+
+	if got := hitID(d.HitTest(60, 60)); got != "menu-btn" {
+		t.Errorf("Expected popup content above base, got %q", got)
+	}
+	if got := hitID(d.HitTest(200, 200)); got != "base" {
+		t.Errorf("Expected base outside the popup, got %q", got)
+	}
+
+	drainEvents(eq)
+	clickAt(d, src, 200, 200)
+	if d.Layers().IsOpen(menu) {
+		t.Error("Expected click outside to dismiss the popup")
+	}
+	clicked := false
+	for _, e := range drainEvents(eq) {
+		clicked = clicked || (e.WidgetID == "base" && e.Type == EventClick)
+	}
+	if !clicked {
+		t.Error("Expected the dismissing click to reach the base widget")
+	}
+}
+
+// TestInputDispatcher_ModalPopupCapturesInput 测试模态弹出内容屏蔽下方的指针和键盘输入，返回动作关闭它
+func TestInputDispatcher_ModalPopupCapturesInput(t *testing.T) {
+	base := NewTextInput("input")
+	base.Width, base.Height = 300, 300
+
+	src := newMockInputSource()
+	eq := NewEventQueue()
+	defer eq.Close()
+	d := NewInputDispatcher(src, eq)
+	d.SetRoots([]Widget{base})
+	d.SetViewport(800, 600)
+	d.FocusManager().Focus(base)
+	d.Update()
+
+	content, _ := newPopupContent("dialog", 400, 100, 200, 100)
+	dialog := NewPopup(content, LayerModal)
+	d.Layers().Push(dialog)
+
+	src.x, src.y = 10, 10
+	result := d.Update()
+	if d.HitTest(10, 10) != nil {
+		t.Error("Expected modal popup to block the base widget")
+	}
+	if !result.PointerCaptured || !result.KeyboardCaptured || result.Modal != content {
+		t.Errorf("Expected captured input with popup as modal, got %+v", result)
+	}
+	if d.keyTarget() != nil {
+		t.Error("Expected keyboard input to be blocked for the focused base widget")
+	}
+
+	// 按在遮罩上不关闭模态弹出内容
+	clickAt(d, src, 10, 10)
+	if !d.Layers().IsOpen(dialog) {
+		t.Error("Expected modal popup to stay open")
+	}
+
+	d.Navigate(NavActionBack)
+	if d.Layers().IsOpen(dialog) {
+		t.Error("Expected back action to dismiss the modal popup")
+	}
+	if d.keyTarget() != base {
+		t.Error("Expected keyboard input to return to the base widget")
+	}
+}
+
+// newComboFixture 创建下拉选择框，后面紧跟一个会被下拉列表覆盖的按钮
+func newComboFixture() (*InputDispatcher, *mockInputSource, *EventQueue, *ComboBoxWidget) {
+	combo := NewComboBox("combo", 0, 0, 200, 30)
+	combo.Items = []string{"a", "b", "c"}
+	below := NewButton("below")
+	below.Y = 40
+	below.Width, below.Height = 200, 100
+
+	src := newMockInputSource()
+	eq := NewEventQueue()
+	d := NewInputDispatcher(src, eq)
+	d.SetRoots([]Widget{combo, below})
+	d.SetViewport(800, 600)
+	d.Update()
+	return d, src, eq, combo
+}
+
+// TestComboBox_DropdownInPopupLayer 测试下拉列表在弹出层中打开，覆盖后面的兄弟控件并接收点击
+func TestComboBox_DropdownInPopupLayer(t *testing.T) {
+	d, src, eq, combo := newComboFixture()
+	defer eq.Close()
+
+	clickAt(d, src, 50, 15)
+	if !combo.IsExpanded || !combo.IsDropdownOpen() {
+		t.Fatal("Expected click to open the dropdown")
+	}
+	if got := hitID(d.HitTest(50, 50)); got != "combo.dropdown" {
+		t.Errorf("Expected dropdown above the later sibling, got %q", got)
+	}
+
+	// 悬停高亮第三项（30 + 2*30 = 90起）
+	src.x, src.y = 50, 100
+	d.Update()
+	if combo.HoverIndex != 2 {
+		t.Errorf("Expected hover index 2, got %d", combo.HoverIndex)
+	}
+
+	drainEvents(eq)
+	clickAt(d, src, 50, 100)
+	if combo.SelectedIndex != 2 || combo.IsExpanded || combo.IsDropdownOpen() {
+		t.Errorf("Expected item 2 selected and dropdown closed, got index %d expanded %v", combo.SelectedIndex, combo.IsExpanded)
+	}
+	var change *WidgetEvent
+	events := drainEvents(eq)
+	for i := range events {
+		if events[i].Type == EventChange {
+			change = &events[i]
+		}
+	}
+	if change == nil || change.WidgetID != "combo" || change.Data["selectedIndex"] != 2 || change.Data["value"] != "c" {
+		t.Errorf("Expected change event for combo, got %+v", change)
+	}
+	for _, e := range events {
+		if e.WidgetID == "below" && e.Type == EventClick {
+			t.Error("Expected click on the dropdown not to fall through")
+		}
+	}
+}
+
+// TestComboBox_DismissOutsideAndToggle 测试点击外部关闭下拉列表，再次点击主框收起下拉列表
+func TestComboBox_DismissOutsideAndToggle(t *testing.T) {
+	d, src, eq, combo := newComboFixture()
+	defer eq.Close()
+
+	clickAt(d, src, 50, 15)
+	clickAt(d, src, 500, 500)
+	if combo.IsExpanded || combo.SelectedIndex != -1 {
+		t.Errorf("Expected click outside to close without selecting, got expanded %v index %d", combo.IsExpanded, combo.SelectedIndex)
+	}
+
+	clickAt(d, src, 50, 15)
+	clickAt(d, src, 50, 15)
+	if combo.IsExpanded {
+		t.Error("Expected second click on the combo box to close the dropdown")
+	}
+}
+
+// TestComboBox_ExpandedOpensPopup 测试直接设置展开状态（加载时或由脚本设置）时下拉列表在弹出层中打开和关闭
+func TestComboBox_ExpandedOpensPopup(t *testing.T) {
+	d, _, eq, combo := newComboFixture()
+	defer eq.Close()
+
+	combo.IsExpanded = true
+	d.Update()
+	if !combo.IsDropdownOpen() {
+		t.Fatal("Expected IsExpanded to open the dropdown popup")
+	}
+	if got := hitID(d.HitTest(50, 50)); got != "combo.dropdown" {
+		t.Errorf("Expected the dropdown in the popup layer, got %q", got)
+	}
+
+	combo.IsExpanded = false
+	d.Update()
+	if combo.IsDropdownOpen() || len(d.Layers().Popups(LayerPopup)) != 0 {
+		t.Error("Expected clearing IsExpanded to dismiss the dropdown popup")
+	}
+}
+
+// TestComboBox_DropdownRect 测试下拉列表在视口下方放不下时翻转到主框上方
+func TestComboBox_DropdownRect(t *testing.T) {
+	combo := NewComboBox("combo", 0, 0, 200, 30)
+	combo.Items = []string{"a", "b", "c"}
+	viewport := image.Rect(0, 0, 400, 300)
+
+	if got, want := combo.dropdownRect(image.Rect(10, 20, 210, 50), viewport), image.Rect(10, 50, 210, 140); got != want {
+		t.Errorf("Expected dropdown below %v, got %v", want, got)
+	}
+	if got, want := combo.dropdownRect(image.Rect(10, 250, 210, 280), viewport), image.Rect(10, 160, 210, 250); got != want {
+		t.Errorf("Expected dropdown above %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,253 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+)
+
+// LayoutBox 控件的布局结果（绝对坐标）
+type LayoutBox struct {
+	Bounds  image.Rectangle // 控件边界
+	Content image.Rectangle // 内容区域（子控件相对该区域定位）
+}
+
+// computedLayout 可以缓存布局结果的控件（所有嵌入BaseWidget的控件都实现了该接口）
+type computedLayout interface {
+	GetComputedLayout() (LayoutBox, bool)
+	MeasuredSize() (image.Point, bool)
+	setMeasuredSize(size image.Point)
+	setComputedLayout(local image.Rectangle, box LayoutBox)
+	invalidateComputedLayout()
+	computeLocalBounds(parentWidth, parentHeight int) image.Rectangle
+	constrainSize(width, height int, stretchX, stretchY bool) (int, int)
+}
+
+// contentScroller 内容可以滚动的容器（例如ScrollPanel）
+// 根据可视区域返回平移后的内容区域，子控件在该区域中排列，区域可以大于可视区域
+type contentScroller interface {
+	scrollContent(viewport image.Rectangle) image.Rectangle
+}
+
+// childMeasurer 可以根据子控件计算首选尺寸的容器（例如flex/grid布局的面板）
+type childMeasurer interface {
+	MeasureChildren() image.Point
+}
+
+// PerformLayout 对控件树执行一次完整的布局过程
+// 测量阶段自底向上计算首选尺寸，排列阶段自顶向下计算每个控件的绝对边界并缓存在控件上
+func PerformLayout(roots []Widget, viewportWidth, viewportHeight int) {
+	for _, root := range roots {
+		if root.IsVisible() {
+			measureWidget(root)
+		}
+	}
+	arrangeWidgets(roots, image.Rect(0, 0, viewportWidth, viewportHeight))
+}
+
+// measureWidget 测量控件的首选尺寸
+// 设计尺寸为0的方向由容器的子控件或控件内容（例如文本）决定
+func measureWidget(widget Widget) image.Point {
+	for _, child := range widget.GetChildren() {
+		if child.IsVisible() {
+			measureWidget(child)
+		}
+	}
+
+	size := image.Pt(widget.GetWidth(), widget.GetHeight())
+	if size.X <= 0 || size.Y <= 0 {
+		var content image.Point
+		if m, ok := widget.(childMeasurer); ok {
+			// 子控件的尺寸加上内边距（contentSizer的结果已包含内边距）
+			padding := widget.GetPadding()
+			content = m.MeasureChildren().Add(image.Pt(padding.Horizontal(), padding.Vertical()))
+		} else if c, ok := widget.(contentSizer); ok {
+			content = image.Pt(c.ContentSize())
+		}
+		if size.X <= 0 {
+			size.X = content.X
+		}
+		if size.Y <= 0 {
+			size.Y = content.Y
+		}
+	}
+
+	if c, ok := widget.(computedLayout); ok {
+		size.X, size.Y = c.constrainSize(size.X, size.Y, false, false)
+		c.setMeasuredSize(size)
+	}
+	return size
+}
+
+// textContentSize 计算文本加内边距的尺寸（支持\n换行）
+func textContentSize(face font.Face, s string, padding Spacing) (int, int) {
+	lines := strings.Split(s, "\n")
+	width := 0
+	for _, line := range lines {
+		width = max(width, font.MeasureString(face, line).Ceil())
+	}
+	height := len(lines) * face.Metrics().Height.Ceil()
+	return width + padding.Left + padding.Right, height + padding.Top + padding.Bottom
+}
+
+// arrangeWidgets 计算控件在父容器内容区域中的边界
+// 容器先在扣除内边距的内容区域中排列子控件（flex/grid），再递归计算子控件的边界；不可见的子树清除缓存
+func arrangeWidgets(widgets []Widget, parent image.Rectangle) {
+	for _, widget := range widgets {
+		if !widget.IsVisible() {
+			invalidateLayout(widget)
+			continue
+		}
+
+		var local image.Rectangle
+		c, cached := widget.(computedLayout)
+		if cached {
+			local = c.computeLocalBounds(parent.Dx(), parent.Dy())
+		} else {
+			local = widget.GetBounds()
+		}
+		bounds := local.Add(parent.Min)
+		box := LayoutBox{Bounds: bounds, Content: widget.GetPadding().Inset(bounds)}
+		if s, ok := widget.(contentScroller); ok {
+			box.Content = s.scrollContent(box.Content)
+		}
+		if cached {
+			c.setComputedLayout(local, box)
+		}
+
+		if l, ok := widget.(childLayouter); ok {
+			l.LayoutChildren(box.Content.Dx(), box.Content.Dy())
+		}
+		arrangeWidgets(widget.GetChildren(), box.Content)
+	}
+}
+
+// invalidateLayout 清除控件及其子控件缓存的布局结果
+func invalidateLayout(widget Widget) {
+	if c, ok := widget.(computedLayout); ok {
+		c.invalidateComputedLayout()
+	}
+	for _, child := range widget.GetChildren() {
+		invalidateLayout(child)
+	}
+}
+
+// LayoutEngine 布局引擎
+// 默认每帧执行一次布局；关闭EveryFrame后只在视口、根控件变化或调用Invalidate后重新布局
+// 布局结果同时以ID索引的快照保存，供脚本等其他goroutine安全读取
+type LayoutEngine struct {
+	EveryFrame bool
+
+	roots         []Widget
+	width, height int
+	dirty         bool
+
+	mu       sync.RWMutex
+	snapshot map[string]LayoutBox
+}
+
+// NewLayoutEngine 创建布局引擎
+func NewLayoutEngine() *LayoutEngine {
+	return &LayoutEngine{
+		EveryFrame: true,
+		width:      defaultViewportSize,
+		height:     defaultViewportSize,
+		dirty:      true,
+		snapshot:   make(map[string]LayoutBox),
+	}
+}
+
+// SetRoots 设置顶层控件
+func (e *LayoutEngine) SetRoots(roots []Widget) {
+	e.roots = roots
+	e.dirty = true
+}
+
+// SetViewport 设置视口尺寸
+func (e *LayoutEngine) SetViewport(width, height int) {
+	if width != e.width || height != e.height {
+		e.width, e.height = width, height
+		e.dirty = true
+	}
+}
+
+// Invalidate 标记需要重新布局
+func (e *LayoutEngine) Invalidate() {
+	e.dirty = true
+}
+
+// Update 按需执行布局过程，返回本次是否重新布局
+func (e *LayoutEngine) Update() bool {
+	if !e.dirty && !e.EveryFrame {
+		return false
+	}
+	e.dirty = false
+	PerformLayout(e.roots, e.width, e.height)
+
+	snapshot := make(map[string]LayoutBox, len(e.snapshot))
+	collectLayoutSnapshot(e.roots, snapshot)
+	e.mu.Lock()
+	e.snapshot = snapshot
+	e.mu.Unlock()
+	return true
+}
+
+// collectLayoutSnapshot 收集可见控件的布局结果
+func collectLayoutSnapshot(widgets []Widget, out map[string]LayoutBox) {
+	for _, widget := range widgets {
+		if c, ok := widget.(computedLayout); ok {
+			if box, ok := c.GetComputedLayout(); ok && widget.GetID() != "" {
+				out[widget.GetID()] = box
+			}
+		}
+		collectLayoutSnapshot(widget.GetChildren(), out)
+	}
+}
+
+// BoundsByID 获取控件最近一次布局的结果（可在任意goroutine中调用）
+func (e *LayoutEngine) BoundsByID(id string) (LayoutBox, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	box, ok := e.snapshot[id]
+	return box, ok
+}
+
+// Dump 以缩进文本输出最近一次布局的控件树（用于调试）
+func (e *LayoutEngine) Dump() string {
+	var sb strings.Builder
+	dumpLayout(&sb, e.roots, 0)
+	return sb.String()
+}
+
+func dumpLayout(sb *strings.Builder, widgets []Widget, depth int) {
+	for _, widget := range widgets {
+		fmt.Fprintf(sb, "%s%s (%s)", strings.Repeat("  ", depth), widget.GetID(), widget.GetType())
+		if c, ok := widget.(computedLayout); ok {
+			if box, ok := c.GetComputedLayout(); ok {
+				fmt.Fprintf(sb, " bounds=%v content=%v", box.Bounds, box.Content)
+			} else {
+				sb.WriteString(" hidden")
+			}
+		}
+		sb.WriteString("\n")
+		dumpLayout(sb, widget.GetChildren(), depth+1)
+	}
+}
+
+// measureAbsoluteChildren 绝对定位容器的首选尺寸：容纳所有子控件（含外边距）的最小尺寸
+func measureAbsoluteChildren(children []Widget) image.Point {
+	var size image.Point
+	for _, child := range children {
+		if !child.IsVisible() {
+			continue
+		}
+		w, h := preferredSize(child)
+		margin := child.GetMargin()
+		size.X = max(size.X, child.GetX()+w+margin.Horizontal())
+		size.Y = max(size.Y, child.GetY()+h+margin.Vertical())
+	}
+	return size
+}
@@ -0,0 +1,292 @@
+package ui
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+// computedBounds 获取控件缓存的布局边界
+func computedBounds(t *testing.T, w Widget) image.Rectangle {
+	t.Helper()
+	box, ok := w.(computedLayout).GetComputedLayout()
+	if !ok {
+		t.Fatalf("%s has no computed layout", w.GetID())
+	}
+	return box.Bounds
+}
+
+// TestLayout_CachesAbsoluteBounds 测试布局过程缓存绝对边界，绘制使用的位置与缓存一致
+func TestLayout_CachesAbsoluteBounds(t *testing.T) {
+	root := newFlexChild("root", 300, 200)
+	root.X, root.Y = 10, 20
+	child := newFlexChild("child", 50, 40)
+	child.X, child.Y = 5, 6
+	root.AddChild(child)
+
+	PerformLayout([]Widget{root}, 800, 600)
+
+	if got, want := computedBounds(t, root), image.Rect(10, 20, 310, 220); got != want {
+		t.Errorf("expected root bounds %v, got %v", want, got)
+	}
+	if got, want := computedBounds(t, child), image.Rect(15, 26, 65, 66); got != want {
+		t.Errorf("expected child bounds %v, got %v", want, got)
+	}
+
+	// 缓存后CalculatePosition不再依赖传入的父容器尺寸
+	if x, y := child.CalculatePosition(0, 0); x != 5 || y != 6 {
+		t.Errorf("expected cached local position (5, 6), got (%d, %d)", x, y)
+	}
+}
+
+// TestLayout_ChildUsesParentComputedSize 测试子控件使用父容器计算后的尺寸（而不是设计尺寸）
+func TestLayout_ChildUsesParentComputedSize(t *testing.T) {
+	panel := newFlexChild("panel", 200, 100)
+	panel.AnchorRight = true
+	panel.DesignMarginRight = 10
+
+	child := newFlexChild("child", 40, 20)
+	child.PositionMode = "anchor"
+	child.AnchorX = "right"
+	child.OffsetX = -40
+	panel.AddChild(child)
+
+	PerformLayout([]Widget{panel}, 500, 300)
+
+	if got := computedBounds(t, panel).Dx(); got != 490 {
+		t.Fatalf("expected panel width 490, got %d", got)
+	}
+	if got, want := computedBounds(t, child), image.Rect(450, 0, 490, 20); got != want {
+		t.Errorf("expected child bounds %v, got %v", want, got)
+	}
+}
+
+// TestLayout_MeasureAutoSizesFlexPanel 测试尺寸为0的弹性布局面板按子控件自动计算尺寸
+func TestLayout_MeasureAutoSizesFlexPanel(t *testing.T) {
+	panel := newFlexChild("panel", 0, 0)
+	panel.Layout = "flex"
+	panel.Gap = 10
+	a := newFlexChild("a", 50, 20)
+	b := newFlexChild("b", 30, 40)
+	panel.AddChild(a)
+	panel.AddChild(b)
+
+	PerformLayout([]Widget{panel}, 800, 600)
+
+	if size, ok := panel.MeasuredSize(); !ok || size != image.Pt(90, 40) {
+		t.Errorf("expected measured size (90, 40), got %v (ok=%v)", size, ok)
+	}
+	if got, want := computedBounds(t, panel), image.Rect(0, 0, 90, 40); got != want {
+		t.Errorf("expected panel bounds %v, got %v", want, got)
+	}
+	if got, want := computedBounds(t, b), image.Rect(60, 0, 90, 40); got != want {
+		t.Errorf("expected child bounds %v, got %v", want, got)
+	}
+}
+
+// TestLayout_MeasureGrid 测试网格布局面板的测量（fr轨道按内容计算）
+func TestLayout_MeasureGrid(t *testing.T) {
+	children := []Widget{
+		newFlexChild("a", 50, 20),
+		newFlexChild("b", 70, 30),
+		newFlexChild("c", 40, 10),
+	}
+	opts := GridOptions{Columns: ParseGridTracks([]string{"1fr", "100"}), Gap: 5}
+
+	// 两列：max(50,40)=50 + 5 + 100；两行：30 + 5 + 10
+	if got, want := MeasureGrid(children, opts), image.Pt(155, 45); got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestLayout_InvisibleSubtreeInvalidated 测试隐藏的子树清除缓存的布局结果
+func TestLayout_InvisibleSubtreeInvalidated(t *testing.T) {
+	root := newFlexChild("root", 300, 200)
+	child := newFlexChild("child", 50, 40)
+	root.AddChild(child)
+
+	PerformLayout([]Widget{root}, 800, 600)
+	if _, ok := child.GetComputedLayout(); !ok {
+		t.Fatal("expected child to be laid out")
+	}
+
+	root.SetVisible(false)
+	PerformLayout([]Widget{root}, 800, 600)
+	if _, ok := root.GetComputedLayout(); ok {
+		t.Error("expected hidden root to have no computed layout")
+	}
+	if _, ok := child.GetComputedLayout(); ok {
+		t.Error("expected child of hidden root to have no computed layout")
+	}
+}
+
+// TestLayoutEngine_Invalidate 测试关闭每帧布局后只在标记失效时重新布局
+func TestLayoutEngine_Invalidate(t *testing.T) {
+	root := newFlexChild("root", 100, 100)
+	engine := NewLayoutEngine()
+	engine.EveryFrame = false
+	engine.SetRoots([]Widget{root})
+	engine.SetViewport(800, 600)
+
+	if !engine.Update() {
+		t.Fatal("expected first update to perform layout")
+	}
+	if engine.Update() {
+		t.Error("expected no layout without invalidation")
+	}
+
+	root.X = 40
+	engine.Invalidate()
+	if !engine.Update() {
+		t.Fatal("expected layout after Invalidate")
+	}
+	if box, ok := engine.BoundsByID("root"); !ok || box.Bounds.Min.X != 40 {
+		t.Errorf("expected root at x=40, got %v (ok=%v)", box.Bounds, ok)
+	}
+
+	engine.SetViewport(800, 600)
+	if engine.Update() {
+		t.Error("expected unchanged viewport not to trigger layout")
+	}
+	engine.SetViewport(640, 480)
+	if !engine.Update() {
+		t.Error("expected viewport change to trigger layout")
+	}
+}
+
+// TestLayoutEngine_Dump 测试布局调试输出
+func TestLayoutEngine_Dump(t *testing.T) {
+	root := newFlexChild("root", 100, 80)
+	child := newFlexChild("child", 20, 10)
+	child.SetVisible(false)
+	root.AddChild(child)
+
+	engine := NewLayoutEngine()
+	engine.SetRoots([]Widget{root})
+	engine.Update()
+
+	dump := engine.Dump()
+	if !strings.Contains(dump, "root (panel) bounds=(0,0)-(100,80)") {
+		t.Errorf("expected root bounds in dump, got:\n%s", dump)
+	}
+	if !strings.Contains(dump, "  child (panel) hidden") {
+		t.Errorf("expected hidden child in dump, got:\n%s", dump)
+	}
+	if _, ok := engine.BoundsByID("child"); ok {
+		t.Error("expected hidden child to be absent from snapshot")
+	}
+}
+
+// TestLayout_DispatcherRunsLayoutPass 测试分发器每帧执行布局，命中测试使用缓存结果
+func TestLayout_DispatcherRunsLayoutPass(t *testing.T) {
+	panel := newFlexChild("panel", 0, 0)
+	panel.Layout = "flex"
+	button := NewButton("button")
+	button.Width, button.Height = 60, 30
+	panel.AddChild(button)
+
+	source := newMockInputSource()
+	d := NewInputDispatcher(source, NewEventQueue())
+	d.SetRoots([]Widget{panel})
+	d.SetViewport(800, 600)
+
+	source.x, source.y = 30, 15
+	d.Update()
+
+	if _, ok := d.Layout().BoundsByID("button"); !ok {
+		t.Fatal("expected button in layout snapshot")
+	}
+	if d.GetHovered() != button {
+		t.Errorf("expected button to be hovered, got %v", d.GetHovered())
+	}
+}
+
+// TestLayout_PaddingDefinesContentBox 测试内边距定义内容区域，子控件相对内容区域定位
+func TestLayout_PaddingDefinesContentBox(t *testing.T) {
+	root := newFlexChild("root", 200, 100)
+	root.X, root.Y = 10, 10
+	root.Padding = Spacing{Top: 5, Right: 15, Bottom: 5, Left: 20}
+	child := newFlexChild("child", 40, 20)
+	child.X, child.Y = 5, 5
+	stretched := newFlexChild("stretched", 0, 20)
+	stretched.AnchorLeft, stretched.AnchorRight = true, true
+	root.AddChild(child)
+	root.AddChild(stretched)
+
+	PerformLayout([]Widget{root}, 800, 600)
+
+	box, _ := root.GetComputedLayout()
+	if want := image.Rect(30, 15, 195, 105); box.Content != want {
+		t.Errorf("expected content box %v, got %v", want, box.Content)
+	}
+	if got, want := computedBounds(t, child), image.Rect(35, 20, 75, 40); got != want {
+		t.Errorf("expected child bounds %v, got %v", want, got)
+	}
+	if got, want := computedBounds(t, stretched), image.Rect(30, 15, 195, 35); got != want {
+		t.Errorf("expected stretched child bounds %v, got %v", want, got)
+	}
+
+	// 命中测试的回退路径（没有缓存时）同样使用内容区域
+	invalidateLayout(root)
+	bounds := make(map[Widget]image.Rectangle)
+	collectWidgetBounds([]Widget{root}, image.Rect(0, 0, 800, 600), bounds)
+	if got, want := bounds[child], image.Rect(35, 20, 75, 40); got != want {
+		t.Errorf("expected uncached child bounds %v, got %v", want, got)
+	}
+}
+
+// TestLayout_MarginInAnchoring 测试外边距参与锚定
+func TestLayout_MarginInAnchoring(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(w *PanelWidget)
+		want  image.Rectangle
+	}{
+		{"absolute", func(w *PanelWidget) {}, image.Rect(18, 23, 58, 43)},
+		{"stretch", func(w *PanelWidget) {
+			w.AnchorLeft, w.AnchorRight = true, true
+		}, image.Rect(18, 23, 396, 43)},
+		{"right", func(w *PanelWidget) {
+			w.AnchorRight = true
+		}, image.Rect(18, 23, 396, 43)},
+		{"bottom", func(w *PanelWidget) {
+			w.AnchorBottom = true
+		}, image.Rect(18, 23, 58, 291)},
+		{"point right", func(w *PanelWidget) {
+			w.PositionMode = "anchor"
+			w.AnchorX, w.AnchorY = "right", "bottom"
+			w.OffsetX, w.OffsetY = -40, -20
+		}, image.Rect(356, 271, 396, 291)},
+		{"point center", func(w *PanelWidget) {
+			w.PositionMode = "anchor"
+			w.AnchorX, w.AnchorY = "center", "middle"
+		}, image.Rect(202, 147, 242, 167)},
+	}
+	for _, tt := range tests {
+		w := newFlexChild("w", 40, 20)
+		w.X, w.Y = 10, 20
+		w.Margin = Spacing{Top: 3, Right: 4, Bottom: 9, Left: 8}
+		tt.setup(w)
+
+		if got := w.computeLocalBounds(400, 300); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// TestLayout_MeasureIncludesPaddingAndMargin 测试测量结果包含容器内边距和子控件外边距
+func TestLayout_MeasureIncludesPaddingAndMargin(t *testing.T) {
+	panel := newFlexChild("panel", 0, 0)
+	panel.Padding = Spacing{Top: 4, Right: 4, Bottom: 4, Left: 4}
+	child := newFlexChild("child", 50, 20)
+	child.X, child.Y = 10, 10
+	child.Margin = Spacing{Right: 6, Bottom: 2}
+	panel.AddChild(child)
+
+	PerformLayout([]Widget{panel}, 800, 600)
+
+	// 宽：10 + 50 + 6 + 4*2；高：10 + 20 + 2 + 4*2
+	if size, _ := panel.MeasuredSize(); size != image.Pt(74, 40) {
+		t.Errorf("expected measured size (74, 40), got %v", size)
+	}
+}
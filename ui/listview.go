@@ -4,11 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"image"
-	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text"
-	"github.com/hajimehoshi/ebiten/v2/vector"
 	"golang.org/x/image/font/basicfont"
 )
 
@@ -42,13 +39,14 @@ type ListViewWidget struct {
 func NewListView(id string) *ListViewWidget {
 	return &ListViewWidget{
 		BaseWidget: BaseWidget{
-			ID:          id,
-			Type:        TypeListView,
-			Visible:     true,
-			Interactive: true,
-			Width:       200,
-			Height:      150,
-			Opacity:     100,
+			ID:           id,
+			Type:         TypeListView,
+			Visible:      true,
+			Interactive:  true,
+			ClipChildren: true,
+			Width:        200,
+			Height:       150,
+			Opacity:      100,
 		},
 		ItemHeight:           40,
 		Scrollable:           true,
@@ -76,54 +74,52 @@ func (l *ListViewWidget) Draw(screen *ebiten.Image, parentX, parentY, parentWidt
 	absX := parentX + localX
 	absY := parentY + localY
 
-	// 创建子图像作为裁剪区域
-	subImg := screen.SubImage(image.Rect(absX, absY, absX+renderWidth, absY+renderHeight)).(*ebiten.Image)
+	drawPainted(screen, l, image.Rect(absX, absY, absX+renderWidth, absY+renderHeight))
+
+	// 绘制子控件（暂不支持，因为ListView使用模板）
+	// l.BaseWidget.DrawChildren(screen, absX, absY, renderWidth, renderHeight)
+}
+
+// Paint 记录背景、数据项（或占位符）和边框，全部裁剪到列表边界内
+func (l *ListViewWidget) Paint(list *DrawList, bounds image.Rectangle) {
+	list.PushClip(bounds)
 
 	// 绘制背景
-	l.drawBackground(subImg, 0, 0, renderWidth, renderHeight)
+	l.paintBackground(list, bounds)
 
-	// 绘制项
+	// 绘制项（在内容区域内）
+	content := l.ContentRect(bounds)
 	if l.ItemTemplate != nil && len(l.Items) > 0 {
-		l.drawItems(subImg, 0, 0, renderWidth, renderHeight)
+		l.paintItems(list, content.Min.X, content.Min.Y, content.Dx(), content.Dy())
 	} else {
 		// 如果没有模板，绘制占位符
-		l.drawPlaceholder(subImg, 0, 0, renderWidth, renderHeight)
+		l.paintPlaceholder(list, content.Min.X, content.Min.Y, content.Dx(), content.Dy())
 	}
 
 	// 绘制边框
-	l.drawBorder(subImg, 0, 0, renderWidth, renderHeight)
+	l.paintBorder(list, bounds)
 
-	// 绘制子控件（暂不支持，因为ListView使用模板）
-	// l.BaseWidget.DrawChildren(screen, absX, absY, renderWidth, renderHeight)
+	list.PopClip()
 }
 
-// drawBackground 绘制背景
-func (l *ListViewWidget) drawBackground(screen *ebiten.Image, x, y, width, height int) {
+// paintBackground 记录背景
+func (l *ListViewWidget) paintBackground(list *DrawList, bounds image.Rectangle) {
 	bgColor := RGBA{R: l.BackgroundColor.R, G: l.BackgroundColor.G, B: l.BackgroundColor.B, A: l.BackgroundColorAlpha}
-	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(height), bgColor.ToColor(), false)
+	list.FillRect(bounds, bgColor)
 }
 
-// drawBorder 绘制边框
-func (l *ListViewWidget) drawBorder(screen *ebiten.Image, x, y, width, height int) {
+// paintBorder 记录边框
+func (l *ListViewWidget) paintBorder(list *DrawList, bounds image.Rectangle) {
 	if l.BorderWidth <= 0 {
 		return
 	}
 
 	borderColor := RGBA{R: l.BorderColor.R, G: l.BorderColor.G, B: l.BorderColor.B, A: l.BorderColorAlpha}
-	bw := float32(l.BorderWidth)
-
-	// 上边
-	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), bw, borderColor.ToColor(), false)
-	// 下边
-	vector.DrawFilledRect(screen, float32(x), float32(y+height)-bw, float32(width), bw, borderColor.ToColor(), false)
-	// 左边
-	vector.DrawFilledRect(screen, float32(x), float32(y), bw, float32(height), borderColor.ToColor(), false)
-	// 右边
-	vector.DrawFilledRect(screen, float32(x+width)-bw, float32(y), bw, float32(height), borderColor.ToColor(), false)
+	list.StrokeRect(bounds, l.BorderWidth, borderColor)
 }
 
-// drawPlaceholder 绘制占位符（无数据时）
-func (l *ListViewWidget) drawPlaceholder(screen *ebiten.Image, x, y, width, height int) {
+// paintPlaceholder 记录占位符（无数据时）
+func (l *ListViewWidget) paintPlaceholder(list *DrawList, x, y, width, height int) {
 	// 绘制示例项
 	itemCount := height / l.ItemHeight
 	if itemCount > 5 {
@@ -134,22 +130,24 @@ func (l *ListViewWidget) drawPlaceholder(screen *ebiten.Image, x, y, width, heig
 		itemY := y + i*l.ItemHeight
 
 		// 项背景（交替颜色）
-		var itemColor color.Color
-		if i%2 == 0 {
-			itemColor = color.RGBA{240, 240, 240, 255}
-		} else {
-			itemColor = color.RGBA{250, 250, 250, 255}
-		}
-		vector.DrawFilledRect(screen, float32(x+2), float32(itemY+2), float32(width-4), float32(l.ItemHeight-4), itemColor, false)
+		list.FillRect(image.Rect(x+2, itemY+2, x+width-2, itemY+l.ItemHeight-2), listItemColor(i))
 
 		// 示例文本
 		placeholderText := fmt.Sprintf("Item %d", i+1)
-		text.Draw(screen, placeholderText, basicfont.Face7x13, x+10, itemY+l.ItemHeight/2+5, color.RGBA{100, 100, 100, 255})
+		list.DrawText(placeholderText, basicfont.Face7x13, x+10, itemY+l.ItemHeight/2+5, RGBA{100, 100, 100, 255})
+	}
+}
+
+// listItemColor 列表项的背景颜色（奇偶行交替）
+func listItemColor(index int) RGBA {
+	if index%2 == 0 {
+		return RGBA{240, 240, 240, 255}
 	}
+	return RGBA{250, 250, 250, 255}
 }
 
-// drawItems 绘制数据项（使用模板）
-func (l *ListViewWidget) drawItems(screen *ebiten.Image, x, y, width, height int) {
+// paintItems 记录数据项（使用模板）
+func (l *ListViewWidget) paintItems(list *DrawList, x, y, width, height int) {
 	// 计算可见项范围
 	startIndex := l.ScrollY / l.ItemHeight
 	if startIndex < 0 {
@@ -173,21 +171,15 @@ func (l *ListViewWidget) drawItems(screen *ebiten.Image, x, y, width, height int
 		}
 
 		// 项背景（交替颜色）
-		var itemColor color.Color
-		if i%2 == 0 {
-			itemColor = color.RGBA{240, 240, 240, 255}
-		} else {
-			itemColor = color.RGBA{250, 250, 250, 255}
-		}
-		vector.DrawFilledRect(screen, float32(x+2), float32(itemY+2), float32(width-4), float32(l.ItemHeight-4), itemColor, false)
+		list.FillRect(image.Rect(x+2, itemY+2, x+width-2, itemY+l.ItemHeight-2), listItemColor(i))
 
 		// 渲染模板
-		l.renderItemTemplate(screen, itemData, x, itemY, width, l.ItemHeight)
+		l.paintItemTemplate(list, itemData, image.Rect(x, itemY, x+width, itemY+l.ItemHeight))
 	}
 }
 
-// renderItemTemplate 渲染单个项的模板
-func (l *ListViewWidget) renderItemTemplate(screen *ebiten.Image, data map[string]interface{}, x, y, width, height int) {
+// paintItemTemplate 记录单个项的模板
+func (l *ListViewWidget) paintItemTemplate(list *DrawList, data map[string]interface{}, item image.Rectangle) {
 	if l.ItemTemplate == nil {
 		return
 	}
@@ -214,8 +206,8 @@ func (l *ListViewWidget) renderItemTemplate(screen *ebiten.Image, data map[strin
 		// 应用数据绑定（简单的文本替换）
 		l.applyDataBinding(widget, data)
 
-		// 绘制子控件（相对于项的位置）
-		widget.Draw(screen, x, y, width, height)
+		// 记录子控件（相对于项的位置）
+		recordWidget(list, widget, item)
 	}
 }
 
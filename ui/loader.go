@@ -6,9 +6,11 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"image"
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -31,14 +33,18 @@ type ResourceInfo struct {
 	Type   string `json:"type"`
 	Offset int    `json:"offset"`
 	Size   int    `json:"size"`
+	// Slice 图片资源的九宫格切片（作为背景时使用，控件可以用backgroundSlice覆盖）
+	Slice NineSlice `json:"nineSlice"`
 }
 
 // Loader UI加载器（支持pak格式）
 type Loader struct {
 	imageCache   map[string]*ebiten.Image
+	imageSources map[*ebiten.Image]image.Image // 已加载图片的解码图像
 	pakData      []byte
 	manifest     *ResourceManifest
 	pakHash      string
+	theme        *Theme            // 加载UI时使用的主题（优先于.ui文件中声明的主题）
 	resourcePath string            // UI文件所在目录
 	scripts      map[string]string // 脚本数据：widgetID -> scriptCode
 }
@@ -46,8 +52,9 @@ type Loader struct {
 // NewLoader 创建加载器
 func NewLoader() *Loader {
 	return &Loader{
-		imageCache: make(map[string]*ebiten.Image),
-		scripts:    make(map[string]string),
+		imageCache:   make(map[string]*ebiten.Image),
+		imageSources: make(map[*ebiten.Image]image.Image),
+		scripts:      make(map[string]string),
 	}
 }
 
@@ -114,6 +121,12 @@ func (l *Loader) LoadFromData(data map[string]interface{}) ([]Widget, error) {
 		l.manifest = l.parseManifest(manifestData)
 	}
 
+	// 解析主题（如果有）
+	theme, err := l.layoutTheme(data)
+	if err != nil {
+		return nil, fmt.Errorf("load theme error: %w", err)
+	}
+
 	// 解析脚本数据（如果有）
 	if scriptsData, ok := data["scripts"].(map[string]interface{}); ok {
 		for widgetID, scriptCode := range scriptsData {
@@ -168,9 +181,80 @@ func (l *Loader) LoadFromData(data map[string]interface{}) ([]Widget, error) {
 		}
 	}
 
+	// 应用主题（需要完整的控件树以计算继承的属性）
+	if theme != nil {
+		applyTheme(rootWidgets, theme, nil)
+	}
+
 	return rootWidgets, nil
 }
 
+// SetTheme 设置之后加载的UI使用的主题（优先于.ui文件中声明的主题，nil表示使用文件中的主题）
+// 运行时切换已加载的控件树的主题使用包级函数SetTheme
+func (l *Loader) SetTheme(theme *Theme) {
+	l.theme = theme
+}
+
+// LoadTheme 从文件加载主题，extends指定的基础主题相对于主题文件所在目录解析
+func (l *Loader) LoadTheme(filename string) (*Theme, error) {
+	return l.loadTheme(filename, make(map[string]bool))
+}
+
+// loadTheme 加载主题文件（visited用于检测循环继承）
+func (l *Loader) loadTheme(filename string, visited map[string]bool) (*Theme, error) {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+	if visited[abs] {
+		return nil, fmt.Errorf("theme inheritance cycle at %s", filename)
+	}
+	visited[abs] = true
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("read theme error: %w", err)
+	}
+	var themeData map[string]interface{}
+	if err := json.Unmarshal(data, &themeData); err != nil {
+		return nil, fmt.Errorf("parse theme error: %w", err)
+	}
+	return l.parseThemeData(themeData, filepath.Dir(filename), visited)
+}
+
+// parseThemeData 解析主题数据并合并基础主题
+func (l *Loader) parseThemeData(data map[string]interface{}, dir string, visited map[string]bool) (*Theme, error) {
+	theme, err := ParseTheme(data)
+	if err != nil {
+		return nil, err
+	}
+	if extends, ok := data["extends"].(string); ok && extends != "" {
+		base, err := l.loadTheme(filepath.Join(dir, extends), visited)
+		if err != nil {
+			return nil, err
+		}
+		theme = theme.Extend(base)
+	}
+	return theme, nil
+}
+
+// layoutTheme 获取加载UI时使用的主题：Loader设置的主题，或者.ui文件中的theme（主题文件路径或主题对象）
+func (l *Loader) layoutTheme(data map[string]interface{}) (*Theme, error) {
+	if l.theme != nil {
+		return l.theme, nil
+	}
+	switch theme := data["theme"].(type) {
+	case string:
+		if theme == "" {
+			return nil, nil
+		}
+		return l.LoadTheme(filepath.Join(l.resourcePath, theme))
+	case map[string]interface{}:
+		return l.parseThemeData(theme, l.resourcePath, make(map[string]bool))
+	}
+	return nil, nil
+}
+
 // GetScripts 获取所有脚本数据
 func (l *Loader) GetScripts() map[string]string {
 	return l.scripts
@@ -209,6 +293,9 @@ func (l *Loader) parseManifest(data map[string]interface{}) *ResourceManifest {
 				if size, ok := resObj["size"].(float64); ok {
 					info.Size = int(size)
 				}
+				if slice, ok := resObj["nineSlice"].(map[string]interface{}); ok {
+					info.Slice = parseNineSlice(slice)
+				}
 				log.Printf("[Loader]   Resource[%d]: ID=%s, Name=%s, Type=%s, Offset=%d, Size=%d",
 					i, info.ID, info.Name, info.Type, info.Offset, info.Size)
 				manifest.Resources = append(manifest.Resources, info)
@@ -264,6 +351,8 @@ func (l *Loader) createWidget(data map[string]interface{}) (Widget, error) {
 		widget = l.createRadioButton(data)
 	case TypePanel:
 		widget = l.createPanel(data)
+	case TypeScrollPanel:
+		widget = l.createScrollPanel(data)
 	case TypeImage:
 		widget = l.createImage(data)
 	case TypeListView:
@@ -295,6 +384,8 @@ func (l *Loader) setBaseProperties(widget Widget, data map[string]interface{}) {
 		base = &w.BaseWidget
 	case *PanelWidget:
 		base = &w.BaseWidget
+	case *ScrollPanelWidget:
+		base = &w.BaseWidget
 	case *ImageWidget:
 		base = &w.BaseWidget
 	case *ListViewWidget:
@@ -342,6 +433,98 @@ func (l *Loader) setBaseProperties(widget Widget, data map[string]interface{}) {
 	if interactive, ok := data["interactive"].(bool); ok {
 		base.Interactive = interactive
 	}
+	if clipChildren, ok := data["clipChildren"].(bool); ok {
+		base.ClipChildren = clipChildren
+	}
+	if overlay, ok := data["overlay"].(bool); ok {
+		base.Overlay = overlay
+	}
+	if tabIndex, ok := data["tabIndex"].(float64); ok {
+		base.TabIndex = int(tabIndex)
+	}
+	if draggable, ok := data["draggable"].(bool); ok {
+		base.Draggable = draggable
+	}
+	if dragType, ok := data["dragType"].(string); ok {
+		base.DragType = dragType
+	}
+	if dropTarget, ok := data["dropTarget"].(bool); ok {
+		base.DropTarget = dropTarget
+	}
+	if acceptTypes, ok := data["acceptTypes"].([]interface{}); ok {
+		base.AcceptTypes = make([]string, 0, len(acceptTypes))
+		for _, t := range acceptTypes {
+			if typeName, ok := t.(string); ok {
+				base.AcceptTypes = append(base.AcceptTypes, typeName)
+			}
+		}
+	}
+	if navUp, ok := data["navUp"].(string); ok {
+		base.NavUp = navUp
+	}
+	if navDown, ok := data["navDown"].(string); ok {
+		base.NavDown = navDown
+	}
+	if navLeft, ok := data["navLeft"].(string); ok {
+		base.NavLeft = navLeft
+	}
+	if navRight, ok := data["navRight"].(string); ok {
+		base.NavRight = navRight
+	}
+	// tooltip可以是文本，也可以是 {"text": "...", "template": "widgetId"}
+	switch tooltip := data["tooltip"].(type) {
+	case string:
+		base.Tooltip = tooltip
+	case map[string]interface{}:
+		if text, ok := tooltip["text"].(string); ok {
+			base.Tooltip = text
+		}
+		if template, ok := tooltip["template"].(string); ok {
+			base.TooltipTemplate = template
+		}
+	}
+	if template, ok := data["tooltipTemplate"].(string); ok {
+		base.TooltipTemplate = template
+	}
+	if cursor, ok := data["cursor"].(string); ok {
+		base.Cursor = cursor
+	}
+
+	// 弹性布局子项属性（flexBasis可以是像素、"auto"或"content"）
+	if flexGrow, ok := data["flexGrow"].(float64); ok {
+		base.FlexGrow = flexGrow
+	}
+	if flexShrink, ok := data["flexShrink"].(float64); ok {
+		base.FlexShrink = flexShrink
+	}
+	switch flexBasis := data["flexBasis"].(type) {
+	case float64:
+		base.FlexBasis = int(flexBasis)
+	case string:
+		if flexBasis == "content" {
+			base.FlexBasis = FlexBasisContent
+		}
+	}
+	if alignSelf, ok := data["alignSelf"].(string); ok {
+		base.AlignSelf = alignSelf
+	}
+
+	// 网格布局子项属性
+	if gridRow, ok := data["gridRow"].(float64); ok {
+		base.GridRow = int(gridRow)
+	}
+	if gridColumn, ok := data["gridColumn"].(float64); ok {
+		base.GridColumn = int(gridColumn)
+	}
+	if rowSpan, ok := data["gridRowSpan"].(float64); ok {
+		base.GridRowSpan = int(rowSpan)
+	}
+	if columnSpan, ok := data["gridColumnSpan"].(float64); ok {
+		base.GridColumnSpan = int(columnSpan)
+	}
+	if justifySelf, ok := data["justifySelf"].(string); ok {
+		base.JustifySelf = justifySelf
+	}
 
 	// 解析颜色
 	if bgColor, ok := data["backgroundColor"].(string); ok {
@@ -366,6 +549,20 @@ func (l *Loader) setBaseProperties(widget Widget, data map[string]interface{}) {
 		base.Opacity = int(opacity)
 	}
 
+	// 解析渐变背景、阴影和边框样式
+	if gradient, ok := data["backgroundGradient"].(map[string]interface{}); ok {
+		base.BackgroundGradient = parseGradient(gradient)
+	}
+	if shadow, ok := data["boxShadow"]; ok {
+		base.BoxShadow = parseBoxShadows(shadow)
+	}
+	if borderStyle, ok := data["borderStyle"].(string); ok {
+		base.BorderStyle = BorderStyle(borderStyle)
+	}
+	if borderDash, ok := data["borderDash"].(float64); ok {
+		base.BorderDash = int(borderDash)
+	}
+
 	// 解析锚点定位属性
 	if positionMode, ok := data["positionMode"].(string); ok {
 		base.PositionMode = positionMode
@@ -403,6 +600,23 @@ func (l *Loader) setBaseProperties(widget Widget, data map[string]interface{}) {
 		base.DesignMarginBottom = int(designMarginBottom)
 	}
 
+	// 解析尺寸约束
+	if minWidth, ok := data["minWidth"].(float64); ok {
+		base.MinWidth = int(minWidth)
+	}
+	if maxWidth, ok := data["maxWidth"].(float64); ok {
+		base.MaxWidth = int(maxWidth)
+	}
+	if minHeight, ok := data["minHeight"].(float64); ok {
+		base.MinHeight = int(minHeight)
+	}
+	if maxHeight, ok := data["maxHeight"].(float64); ok {
+		base.MaxHeight = int(maxHeight)
+	}
+	if ratio, ok := parseAspectRatio(data["aspectRatio"]); ok {
+		base.AspectRatio = ratio
+	}
+
 	// 解析Padding
 	if padding, ok := data["padding"].(map[string]interface{}); ok {
 		if top, ok := padding["top"].(float64); ok {
@@ -435,13 +649,26 @@ func (l *Loader) setBaseProperties(widget Widget, data map[string]interface{}) {
 		}
 	}
 
+	// 样式类，.ui中直接声明的属性优先于主题
+	if class, ok := data["class"].(string); ok {
+		base.Class = class
+	}
+	base.styling.inline = data
+	// 状态样式：控件处于hover、pressed等状态时覆盖的属性
+	if states, ok := data["states"].(map[string]interface{}); ok {
+		base.styling.states = parseStateStyles(widget.GetType(), states)
+	}
+
 	// 加载背景图片
 	if bgResourceID, ok := data["backgroundResourceId"].(string); ok {
 		if bgResourceID != "" {
 			base.BackgroundResourceID = bgResourceID
-			base.backgroundImage = l.loadImage(bgResourceID)
+			base.backgroundImage = l.loadBackground(base, bgResourceID)
 		}
 	}
+	if slice, ok := data["backgroundSlice"].(map[string]interface{}); ok {
+		base.BackgroundSlice = parseNineSlice(slice)
+	}
 }
 
 // createButton 创建按钮
@@ -487,15 +714,15 @@ func (l *Loader) createButton(data map[string]interface{}) *ButtonWidget {
 	// 三态背景资源
 	if resNormal, ok := data["backgroundResourceNormal"].(string); ok {
 		btn.BackgroundResourceNormal = resNormal
-		btn.backgroundImageNormal = l.loadImage(resNormal)
+		btn.backgroundImageNormal = l.loadBackground(&btn.BaseWidget, resNormal)
 	}
 	if resPressed, ok := data["backgroundResourcePressed"].(string); ok {
 		btn.BackgroundResourcePressed = resPressed
-		btn.backgroundImagePressed = l.loadImage(resPressed)
+		btn.backgroundImagePressed = l.loadBackground(&btn.BaseWidget, resPressed)
 	}
 	if resDisabled, ok := data["backgroundResourceDisabled"].(string); ok {
 		btn.BackgroundResourceDisabled = resDisabled
-		btn.backgroundImageDisabled = l.loadImage(resDisabled)
+		btn.backgroundImageDisabled = l.loadBackground(&btn.BaseWidget, resDisabled)
 	}
 
 	if enabled, ok := data["enabled"].(bool); ok {
@@ -613,6 +840,20 @@ func (l *Loader) createTextInput(data map[string]interface{}) *TextInputWidget {
 		input.BackgroundColorDisabledAlpha = uint8(bgDisabledAlpha)
 	}
 
+	// 三态背景资源
+	if resNormal, ok := data["backgroundResourceNormal"].(string); ok {
+		input.BackgroundResourceNormal = resNormal
+		input.backgroundImageNormal = l.loadBackground(&input.BaseWidget, resNormal)
+	}
+	if resEditing, ok := data["backgroundResourceEditing"].(string); ok {
+		input.BackgroundResourceEditing = resEditing
+		input.backgroundImageEditing = l.loadBackground(&input.BaseWidget, resEditing)
+	}
+	if resDisabled, ok := data["backgroundResourceDisabled"].(string); ok {
+		input.BackgroundResourceDisabled = resDisabled
+		input.backgroundImageDisabled = l.loadBackground(&input.BaseWidget, resDisabled)
+	}
+
 	if enabled, ok := data["enabled"].(bool); ok {
 		input.SetEnabled(enabled)
 	}
@@ -623,9 +864,81 @@ func (l *Loader) createTextInput(data map[string]interface{}) *TextInputWidget {
 // createPanel 创建面板
 func (l *Loader) createPanel(data map[string]interface{}) *PanelWidget {
 	panel := NewPanel("")
+	l.setPanelProperties(panel, data)
 	return panel
 }
 
+// createScrollPanel 创建滚动面板
+func (l *Loader) createScrollPanel(data map[string]interface{}) *ScrollPanelWidget {
+	panel := NewScrollPanel("")
+	l.setPanelProperties(&panel.PanelWidget, data)
+
+	if direction, ok := data["scrollDirection"].(string); ok {
+		panel.ScrollDirection = direction
+	}
+	if scrollX, ok := data["scrollX"].(float64); ok {
+		panel.ScrollX = scrollX
+	}
+	if scrollY, ok := data["scrollY"].(float64); ok {
+		panel.ScrollY = scrollY
+	}
+	if step, ok := data["wheelStep"].(float64); ok {
+		panel.WheelStep = step
+	}
+	if inertia, ok := data["inertia"].(bool); ok {
+		panel.Inertia = inertia
+	}
+	if width, ok := data["scrollbarWidth"].(float64); ok {
+		panel.ScrollbarWidth = int(width)
+	}
+	if c, ok := data["scrollbarColor"].(string); ok {
+		panel.ScrollbarColor = l.parseColor(c)
+	}
+	if alpha, ok := data["scrollbarColorAlpha"].(float64); ok {
+		panel.ScrollbarColor.A = uint8(alpha)
+	}
+	if c, ok := data["scrollbarTrackColor"].(string); ok {
+		panel.ScrollbarTrackColor = l.parseColor(c)
+	}
+	if alpha, ok := data["scrollbarTrackColorAlpha"].(float64); ok {
+		panel.ScrollbarTrackColor.A = uint8(alpha)
+	}
+
+	return panel
+}
+
+// setPanelProperties 设置面板的模态和子控件布局属性
+func (l *Loader) setPanelProperties(panel *PanelWidget, data map[string]interface{}) {
+	if modal, ok := data["modal"].(bool); ok {
+		panel.Modal = modal
+	}
+
+	// 子控件布局
+	if layout, ok := data["layout"].(string); ok {
+		panel.Layout = layout
+	}
+	if direction, ok := data["direction"].(string); ok {
+		panel.Direction = direction
+	}
+	if gap, ok := data["gap"].(float64); ok {
+		panel.Gap = int(gap)
+	}
+	if justify, ok := data["justify"].(string); ok {
+		panel.Justify = justify
+	}
+	if alignItems, ok := data["alignItems"].(string); ok {
+		panel.AlignItems = alignItems
+	}
+	if wrap, ok := data["wrap"].(bool); ok {
+		panel.Wrap = wrap
+	}
+	panel.GridColumns = parseTrackList(data["gridColumns"])
+	panel.GridRows = parseTrackList(data["gridRows"])
+	if justifyItems, ok := data["justifyItems"].(string); ok {
+		panel.JustifyItems = justifyItems
+	}
+}
+
 // createImage 创建图片
 func (l *Loader) createImage(data map[string]interface{}) *ImageWidget {
 	img := NewImage("")
@@ -996,6 +1309,7 @@ func (l *Loader) loadImage(resourceID string) *ebiten.Image {
 	}
 
 	var img *ebiten.Image
+	var source image.Image
 	var err error
 
 	// 优先从pak加载
@@ -1003,9 +1317,9 @@ func (l *Loader) loadImage(resourceID string) *ebiten.Image {
 		resourceData, err := l.getResourceData(resourceID)
 		if err == nil {
 			// 从字节数组创建图片
-			img, _, err = ebitenutil.NewImageFromReader(bytes.NewReader(resourceData))
+			img, source, err = ebitenutil.NewImageFromReader(bytes.NewReader(resourceData))
 			if err == nil {
-				l.imageCache[resourceID] = img
+				l.cacheImage(resourceID, img, source)
 				return img
 			}
 		}
@@ -1015,17 +1329,17 @@ func (l *Loader) loadImage(resourceID string) *ebiten.Image {
 	// 尝试在resourcePath中查找文件
 	if l.resourcePath != "" {
 		possiblePath := filepath.Join(l.resourcePath, resourceID)
-		img, _, err = ebitenutil.NewImageFromFile(possiblePath)
+		img, source, err = ebitenutil.NewImageFromFile(possiblePath)
 		if err == nil {
-			l.imageCache[resourceID] = img
+			l.cacheImage(resourceID, img, source)
 			return img
 		}
 	}
 
 	// 直接尝试加载resourceID作为路径
-	img, _, err = ebitenutil.NewImageFromFile(resourceID)
+	img, source, err = ebitenutil.NewImageFromFile(resourceID)
 	if err == nil {
-		l.imageCache[resourceID] = img
+		l.cacheImage(resourceID, img, source)
 		return img
 	}
 
@@ -1033,6 +1347,37 @@ func (l *Loader) loadImage(resourceID string) *ebiten.Image {
 	return nil
 }
 
+// loadBackground 加载背景图片资源，并记录资源清单中为其声明的九宫格切片
+func (l *Loader) loadBackground(base *BaseWidget, resourceID string) *ebiten.Image {
+	img := l.loadImage(resourceID)
+	base.setResourceSlice(img, l.resourceSlice(resourceID))
+	return img
+}
+
+// resourceSlice 获取资源清单中为资源声明的九宫格切片
+func (l *Loader) resourceSlice(resourceID string) NineSlice {
+	if l.manifest == nil {
+		return NineSlice{}
+	}
+	for _, resInfo := range l.manifest.Resources {
+		if resInfo.ID == resourceID {
+			return resInfo.Slice
+		}
+	}
+	return NineSlice{}
+}
+
+// cacheImage 缓存加载的图片和解码得到的CPU侧图像
+func (l *Loader) cacheImage(resourceID string, img *ebiten.Image, source image.Image) {
+	l.imageCache[resourceID] = img
+	l.imageSources[img] = source
+}
+
+// ImageSources 获取已加载的ebiten图片对应的解码图像（供纯Go绘制后端使用，例如RenderToRGBA）
+func (l *Loader) ImageSources() map[*ebiten.Image]image.Image {
+	return l.imageSources
+}
+
 // loadFont 加载字体资源（从pak文件）
 func (l *Loader) loadFont(resourceID string) font.Face {
 	if resourceID == "" {
@@ -1152,6 +1497,9 @@ func (l *Loader) createGridView(data map[string]interface{}) *GridViewWidget {
 	if enabled, ok := data["enabled"].(bool); ok {
 		gv.Enabled = enabled
 	}
+	if dropMode, ok := data["dropMode"].(string); ok {
+		gv.DropMode = dropMode
+	}
 
 	// 解析项模板
 	if itemTemplate, ok := data["itemTemplate"].(map[string]interface{}); ok {
@@ -1292,3 +1640,46 @@ func (l *Loader) createTableView(data map[string]interface{}) *TableViewWidget {
 
 	return tv
 }
+
+// parseTrackList 解析网格轨道列表
+// 支持数组（["120", "1fr", 80]）或空格分隔的字符串（"120 1fr auto"）
+func parseTrackList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		tracks := make([]string, 0, len(v))
+		for _, item := range v {
+			switch track := item.(type) {
+			case string:
+				tracks = append(tracks, track)
+			case float64:
+				tracks = append(tracks, strconv.FormatFloat(track, 'f', -1, 64))
+			}
+		}
+		return tracks
+	}
+	return nil
+}
+
+// parseAspectRatio 解析宽高比
+// 支持数字（1.5）或字符串（"16:9"、"16/9"、"1.5"）
+func parseAspectRatio(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, v > 0
+	case string:
+		sep := strings.IndexAny(v, ":/")
+		if sep < 0 {
+			ratio, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			return ratio, err == nil && ratio > 0
+		}
+		w, errW := strconv.ParseFloat(strings.TrimSpace(v[:sep]), 64)
+		h, errH := strconv.ParseFloat(strings.TrimSpace(v[sep+1:]), 64)
+		if errW != nil || errH != nil || w <= 0 || h <= 0 {
+			return 0, false
+		}
+		return w / h, true
+	}
+	return 0, false
+}
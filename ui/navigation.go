@@ -0,0 +1,354 @@
+package ui
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// NavAction 导航动作
+type NavAction int
+
+const (
+	NavActionUp NavAction = iota
+	NavActionDown
+	NavActionLeft
+	NavActionRight
+	NavActionConfirm // 确认（映射为click）
+	NavActionBack    // 返回（映射为cancel）
+)
+
+// String 返回动作名称（用于事件附加数据）
+func (a NavAction) String() string {
+	switch a {
+	case NavActionUp:
+		return "up"
+	case NavActionDown:
+		return "down"
+	case NavActionLeft:
+		return "left"
+	case NavActionRight:
+		return "right"
+	case NavActionConfirm:
+		return "confirm"
+	case NavActionBack:
+		return "back"
+	}
+	return "unknown"
+}
+
+// NavigationSource 导航输入源接口
+// 返回本帧新触发的导航动作，测试时可以注入合成输入而无需真实手柄
+type NavigationSource interface {
+	AppendNavActions(actions []NavAction) []NavAction
+}
+
+// stickThreshold 摇杆触发导航的阈值
+const stickThreshold = 0.5
+
+// EbitenNavigationSource 基于ebiten的导航输入源
+// 键盘：方向键、Enter/Space确认、Escape返回
+// 手柄（标准布局）：十字键和左摇杆导航，下方按键确认，右方按键返回
+type EbitenNavigationSource struct {
+	gamepadIDs []ebiten.GamepadID
+	stickState map[ebiten.GamepadID][2]int // 上一帧摇杆方向（用于边沿触发）
+}
+
+// NewEbitenNavigationSource 创建基于ebiten的导航输入源
+func NewEbitenNavigationSource() *EbitenNavigationSource {
+	return &EbitenNavigationSource{
+		stickState: make(map[ebiten.GamepadID][2]int),
+	}
+}
+
+// 键盘按键到导航动作的映射
+var navKeyMap = []struct {
+	key    ebiten.Key
+	action NavAction
+}{
+	{ebiten.KeyArrowUp, NavActionUp},
+	{ebiten.KeyArrowDown, NavActionDown},
+	{ebiten.KeyArrowLeft, NavActionLeft},
+	{ebiten.KeyArrowRight, NavActionRight},
+	{ebiten.KeyEnter, NavActionConfirm},
+	{ebiten.KeyNumpadEnter, NavActionConfirm},
+	{ebiten.KeySpace, NavActionConfirm},
+	{ebiten.KeyEscape, NavActionBack},
+}
+
+// 标准手柄按键到导航动作的映射
+var navGamepadMap = []struct {
+	button ebiten.StandardGamepadButton
+	action NavAction
+}{
+	{ebiten.StandardGamepadButtonLeftTop, NavActionUp},
+	{ebiten.StandardGamepadButtonLeftBottom, NavActionDown},
+	{ebiten.StandardGamepadButtonLeftLeft, NavActionLeft},
+	{ebiten.StandardGamepadButtonLeftRight, NavActionRight},
+	{ebiten.StandardGamepadButtonRightBottom, NavActionConfirm},
+	{ebiten.StandardGamepadButtonRightRight, NavActionBack},
+}
+
+// AppendNavActions 实现NavigationSource接口
+func (s *EbitenNavigationSource) AppendNavActions(actions []NavAction) []NavAction {
+	for _, m := range navKeyMap {
+		if inpututil.IsKeyJustPressed(m.key) {
+			actions = append(actions, m.action)
+		}
+	}
+
+	s.gamepadIDs = ebiten.AppendGamepadIDs(s.gamepadIDs[:0])
+	for _, id := range s.gamepadIDs {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		for _, m := range navGamepadMap {
+			if inpututil.IsStandardGamepadButtonJustPressed(id, m.button) {
+				actions = append(actions, m.action)
+			}
+		}
+		actions = s.appendStickActions(id, actions)
+	}
+	return actions
+}
+
+// appendStickActions 摇杆越过阈值时触发一次导航
+func (s *EbitenNavigationSource) appendStickActions(id ebiten.GamepadID, actions []NavAction) []NavAction {
+	dir := [2]int{
+		stickDirection(ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal)),
+		stickDirection(ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical)),
+	}
+	prev := s.stickState[id]
+	s.stickState[id] = dir
+
+	if dir[0] != prev[0] {
+		switch dir[0] {
+		case -1:
+			actions = append(actions, NavActionLeft)
+		case 1:
+			actions = append(actions, NavActionRight)
+		}
+	}
+	if dir[1] != prev[1] {
+		switch dir[1] {
+		case -1:
+			actions = append(actions, NavActionUp)
+		case 1:
+			actions = append(actions, NavActionDown)
+		}
+	}
+	return actions
+}
+
+// stickDirection 将摇杆轴值转换为-1/0/1
+func stickDirection(value float64) int {
+	switch {
+	case value <= -stickThreshold:
+		return -1
+	case value >= stickThreshold:
+		return 1
+	}
+	return 0
+}
+
+// navOverride 获取控件在指定方向上显式指定的导航目标ID
+func navOverride(widget Widget, action NavAction) string {
+	n, ok := widget.(interface {
+		GetNavTargets() (up, down, left, right string)
+	})
+	if !ok {
+		return ""
+	}
+	up, down, left, right := n.GetNavTargets()
+	switch action {
+	case NavActionUp:
+		return up
+	case NavActionDown:
+		return down
+	case NavActionLeft:
+		return left
+	case NavActionRight:
+		return right
+	}
+	return ""
+}
+
+// FindNavigationTarget 查找从当前控件沿指定方向导航的目标控件
+// 优先使用navUp/navDown/navLeft/navRight显式指定的目标，否则根据计算后的绝对边界进行空间查找
+// 查找前先对控件树执行一次布局过程
+func FindNavigationTarget(roots []Widget, viewportWidth, viewportHeight int, from Widget, action NavAction) Widget {
+	PerformLayout(roots, viewportWidth, viewportHeight)
+	return findNavigationTarget(roots, image.Rect(0, 0, viewportWidth, viewportHeight), from, action)
+}
+
+// findNavigationTarget 使用已缓存的布局结果查找导航目标
+func findNavigationTarget(roots []Widget, viewport image.Rectangle, from Widget, action NavAction) Widget {
+	if id := navOverride(from, action); id != "" {
+		if target := findWidgetByID(roots, id); IsFocusable(target) {
+			return target
+		}
+	}
+
+	bounds := make(map[Widget]image.Rectangle)
+	collectWidgetBounds(roots, viewport, bounds)
+
+	fromRect, ok := bounds[from]
+	if !ok {
+		return nil
+	}
+
+	var candidates []Widget
+	collectFocusable(roots, &candidates)
+
+	var best Widget
+	bestScore := math.MaxFloat64
+	for _, candidate := range candidates {
+		if candidate == from {
+			continue
+		}
+		rect, ok := bounds[candidate]
+		if !ok {
+			continue
+		}
+		if score, ok := navScore(fromRect, rect, action); ok && score < bestScore {
+			best = candidate
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// navScore 计算候选控件的导航得分（越小越优先）
+// 主轴距离为两个边界之间的间隙，副轴偏移在投影重叠时为0，并以较大的权重惩罚
+func navScore(from, to image.Rectangle, action NavAction) (float64, bool) {
+	fromX, fromY := rectCenter(from)
+	toX, toY := rectCenter(to)
+
+	var primary, secondary, drift float64
+	switch action {
+	case NavActionUp:
+		if toY >= fromY {
+			return 0, false
+		}
+		primary = float64(from.Min.Y - to.Max.Y)
+		secondary = rangeGap(from.Min.X, from.Max.X, to.Min.X, to.Max.X)
+		drift = math.Abs(toX - fromX)
+	case NavActionDown:
+		if toY <= fromY {
+			return 0, false
+		}
+		primary = float64(to.Min.Y - from.Max.Y)
+		secondary = rangeGap(from.Min.X, from.Max.X, to.Min.X, to.Max.X)
+		drift = math.Abs(toX - fromX)
+	case NavActionLeft:
+		if toX >= fromX {
+			return 0, false
+		}
+		primary = float64(from.Min.X - to.Max.X)
+		secondary = rangeGap(from.Min.Y, from.Max.Y, to.Min.Y, to.Max.Y)
+		drift = math.Abs(toY - fromY)
+	case NavActionRight:
+		if toX <= fromX {
+			return 0, false
+		}
+		primary = float64(to.Min.X - from.Max.X)
+		secondary = rangeGap(from.Min.Y, from.Max.Y, to.Min.Y, to.Max.Y)
+		drift = math.Abs(toY - fromY)
+	default:
+		return 0, false
+	}
+
+	if primary < 0 {
+		primary = 0
+	}
+	return primary + secondary*2 + drift*0.1, true
+}
+
+// rangeGap 计算两个区间之间的间隙（重叠时为0）
+func rangeGap(aMin, aMax, bMin, bMax int) float64 {
+	switch {
+	case bMax <= aMin:
+		return float64(aMin - bMax)
+	case bMin >= aMax:
+		return float64(bMin - aMax)
+	}
+	return 0
+}
+
+// rectCenter 计算矩形中心点
+func rectCenter(r image.Rectangle) (float64, float64) {
+	return float64(r.Min.X+r.Max.X) / 2, float64(r.Min.Y+r.Max.Y) / 2
+}
+
+// SetNavigationSource 设置导航输入源（nil表示禁用空间导航）
+func (d *InputDispatcher) SetNavigationSource(source NavigationSource) {
+	d.navSource = source
+}
+
+// updateNavigation 处理方向导航以及确认/返回动作
+func (d *InputDispatcher) updateNavigation(mods Modifiers) {
+	if d.navSource == nil {
+		return
+	}
+	d.navBuf = d.navSource.AppendNavActions(d.navBuf[:0])
+	for _, action := range d.navBuf {
+		d.navigate(action, mods)
+	}
+}
+
+// Navigate 执行一个导航动作
+// 没有焦点时方向动作会聚焦Tab顺序中的第一个控件；确认向焦点控件发送click，返回发送cancel
+func (d *InputDispatcher) Navigate(action NavAction) {
+	d.navigate(action, Modifiers{})
+}
+
+// navigate 执行导航动作（携带当前修饰键状态）
+func (d *InputDispatcher) navigate(action NavAction, mods Modifiers) {
+	focused := d.focus.GetFocused()
+
+	switch action {
+	case NavActionConfirm:
+		if focused != nil {
+			d.pushNavPointer(EventClick, focused, action, mods)
+		}
+		return
+	case NavActionBack:
+		// 先关闭最上层的弹出内容
+		if d.layers.dismissTop() {
+			return
+		}
+		if focused != nil {
+			d.pushNavPointer(EventCancel, focused, action, mods)
+		}
+		return
+	}
+
+	if focused == nil {
+		d.focus.FocusNext()
+		return
+	}
+
+	// 文本输入框聚焦时左右方向留给光标移动
+	if _, ok := focused.(*TextInputWidget); ok && (action == NavActionLeft || action == NavActionRight) {
+		return
+	}
+
+	if target := findNavigationTarget(d.roots, image.Rect(0, 0, d.viewportWidth, d.viewportHeight), focused, action); target != nil {
+		d.focus.Focus(target)
+	}
+}
+
+// pushNavPointer 以控件中心为坐标推送导航产生的事件
+func (d *InputDispatcher) pushNavPointer(eventType EventType, target Widget, action NavAction, mods Modifiers) {
+	viewport := image.Rect(0, 0, d.viewportWidth, d.viewportHeight)
+	bounds := make(map[Widget]image.Rectangle)
+	collectWidgetBounds(d.roots, viewport, bounds)
+
+	x, y := rectCenter(bounds[target])
+	d.pushPointer(eventType, target, int(x), int(y), 0, mods, map[string]interface{}{
+		"source": "navigation",
+		"action": action.String(),
+	})
+}
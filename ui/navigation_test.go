@@ -0,0 +1,147 @@
+package ui
+
+import "testing"
+
+// mockNavigationSource 测试用的合成导航输入
+type mockNavigationSource struct {
+	pending []NavAction
+}
+
+func (m *mockNavigationSource) AppendNavActions(actions []NavAction) []NavAction {
+	actions = append(actions, m.pending...)
+	m.pending = nil
+	return actions
+}
+
+// newNavGrid 创建2x2按钮网格（放在面板中，面板偏移用于验证使用绝对边界）
+//
+//	a b
+//	c d
+func newNavGrid() (*PanelWidget, map[string]*ButtonWidget) {
+	panel := NewPanel("panel")
+	panel.X, panel.Y = 100, 100
+	buttons := make(map[string]*ButtonWidget)
+	positions := map[string][2]int{
+		"a": {0, 0},
+		"b": {200, 0},
+		"c": {0, 100},
+		"d": {200, 100},
+	}
+	for _, id := range []string{"a", "b", "c", "d"} {
+		btn := NewButton(id)
+		btn.X, btn.Y = positions[id][0], positions[id][1]
+		panel.AddChild(btn)
+		buttons[id] = btn
+	}
+	return panel, buttons
+}
+
+// TestFindNavigationTarget_Spatial 测试基于计算边界的空间导航
+func TestFindNavigationTarget_Spatial(t *testing.T) {
+	panel, b := newNavGrid()
+	roots := []Widget{panel}
+
+	tests := []struct {
+		from   string
+		action NavAction
+		want   string
+	}{
+		{"a", NavActionRight, "b"},
+		{"a", NavActionDown, "c"},
+		{"a", NavActionUp, ""},
+		{"a", NavActionLeft, ""},
+		{"d", NavActionUp, "b"},
+		{"d", NavActionLeft, "c"},
+		{"b", NavActionDown, "d"},
+		{"c", NavActionRight, "d"},
+	}
+	for _, tt := range tests {
+		got := hitID(FindNavigationTarget(roots, 800, 600, b[tt.from], tt.action))
+		if got != tt.want {
+			t.Errorf("From %s %s: expected %q, got %q", tt.from, tt.action, tt.want, got)
+		}
+	}
+}
+
+// TestFindNavigationTarget_PrefersAligned 测试优先选择同一行/列的控件
+func TestFindNavigationTarget_PrefersAligned(t *testing.T) {
+	from := NewButton("from")
+	from.X, from.Y = 0, 200
+	// 更近但偏离很多
+	diagonal := NewButton("diagonal")
+	diagonal.X, diagonal.Y = 150, 0
+	// 更远但在同一行
+	aligned := NewButton("aligned")
+	aligned.X, aligned.Y = 300, 200
+
+	got := hitID(FindNavigationTarget([]Widget{from, diagonal, aligned}, 800, 600, from, NavActionRight))
+	if got != "aligned" {
+		t.Errorf("Expected aligned widget, got %q", got)
+	}
+}
+
+// TestFindNavigationTarget_Override 测试navUp/navDown/navLeft/navRight显式覆盖
+func TestFindNavigationTarget_Override(t *testing.T) {
+	panel, b := newNavGrid()
+	roots := []Widget{panel}
+
+	b["a"].NavRight = "d"
+	if got := hitID(FindNavigationTarget(roots, 800, 600, b["a"], NavActionRight)); got != "d" {
+		t.Errorf("Expected override target d, got %q", got)
+	}
+
+	// 覆盖目标不可聚焦时回退到空间查找
+	b["d"].SetEnabled(false)
+	if got := hitID(FindNavigationTarget(roots, 800, 600, b["a"], NavActionRight)); got != "b" {
+		t.Errorf("Expected fallback to b, got %q", got)
+	}
+}
+
+// TestInputDispatcher_Navigation 测试合成导航输入驱动焦点移动及确认/返回
+func TestInputDispatcher_Navigation(t *testing.T) {
+	panel, b := newNavGrid()
+	src := newMockInputSource()
+	nav := &mockNavigationSource{}
+	eq := NewEventQueue()
+	defer eq.Close()
+
+	d := NewInputDispatcher(src, eq)
+	d.SetRoots([]Widget{panel})
+	d.SetViewport(800, 600)
+	d.SetNavigationSource(nav)
+	src.x, src.y = -1, -1
+
+	step := func(actions ...NavAction) {
+		nav.pending = actions
+		d.Update()
+	}
+
+	// 无焦点时第一次方向输入聚焦第一个控件
+	step(NavActionDown)
+	if d.FocusManager().GetFocused() != b["a"] {
+		t.Fatalf("Expected a focused, got %q", hitID(d.FocusManager().GetFocused()))
+	}
+
+	step(NavActionRight)
+	step(NavActionDown)
+	if d.FocusManager().GetFocused() != b["d"] {
+		t.Fatalf("Expected d focused, got %q", hitID(d.FocusManager().GetFocused()))
+	}
+
+	drainEvents(eq)
+	step(NavActionConfirm, NavActionBack)
+	events := drainEvents(eq)
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %v", eventTypes(events, ""))
+	}
+	if events[0].Type != EventClick || events[0].WidgetID != "d" {
+		t.Errorf("Expected click on d, got %s on %s", events[0].Type, events[0].WidgetID)
+	}
+	// 坐标为控件中心（面板偏移100 + 按钮位置200 + 宽度一半60）
+	if events[0].X != 360 || events[0].Y != 220 {
+		t.Errorf("Expected click at (360,220), got (%d,%d)", events[0].X, events[0].Y)
+	}
+	if events[1].Type != EventCancel || events[1].WidgetID != "d" {
+		t.Errorf("Expected cancel on d, got %s on %s", events[1].Type, events[1].WidgetID)
+	}
+}
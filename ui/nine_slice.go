@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// NineSliceMode 九宫格边缘和中心区域的填充方式
+type NineSliceMode string
+
+const (
+	NineSliceStretch NineSliceMode = "stretch" // 拉伸（默认）
+	NineSliceTile    NineSliceMode = "tile"    // 按源图像的像素尺寸平铺，末尾不足一块时裁剪
+)
+
+// NineSlice 背景图片的九宫格切片（9-patch）
+// 四个角按源图像的尺寸绘制，不随控件缩放；上下边缘在水平方向、左右边缘在垂直方向拉伸或平铺，中心区域在两个方向上拉伸或平铺
+// 目标小于两侧边距之和时角按比例缩小；四个边距都为0时表示不切片，整个图像直接拉伸
+type NineSlice struct {
+	Top        int           `json:"top"`
+	Right      int           `json:"right"`
+	Bottom     int           `json:"bottom"`
+	Left       int           `json:"left"`
+	EdgeMode   NineSliceMode `json:"edgeMode"`   // 边缘填充方式
+	CenterMode NineSliceMode `json:"centerMode"` // 中心填充方式
+}
+
+// IsZero 是否没有切片
+func (s NineSlice) IsZero() bool {
+	return s.Top <= 0 && s.Right <= 0 && s.Bottom <= 0 && s.Left <= 0
+}
+
+// parseNineSlice 解析.ui文件和资源清单中的切片声明，例如
+// {"top": 8, "right": 8, "bottom": 8, "left": 8, "edgeMode": "tile", "centerMode": "stretch"}
+// 也可以用"insets"一次指定四个边距
+func parseNineSlice(data map[string]interface{}) NineSlice {
+	var s NineSlice
+	if insets, ok := data["insets"].(float64); ok {
+		s.Top, s.Right, s.Bottom, s.Left = int(insets), int(insets), int(insets), int(insets)
+	}
+	if top, ok := data["top"].(float64); ok {
+		s.Top = int(top)
+	}
+	if right, ok := data["right"].(float64); ok {
+		s.Right = int(right)
+	}
+	if bottom, ok := data["bottom"].(float64); ok {
+		s.Bottom = int(bottom)
+	}
+	if left, ok := data["left"].(float64); ok {
+		s.Left = int(left)
+	}
+	if mode, ok := data["edgeMode"].(string); ok {
+		s.EdgeMode = NineSliceMode(mode)
+	}
+	if mode, ok := data["centerMode"].(string); ok {
+		s.CenterMode = NineSliceMode(mode)
+	}
+	return s
+}
+
+// DrawNineSlice 按九宫格切片把图像绘制到矩形（切片为空时等同DrawImage）
+// 每个区域记录为一条或多条（平铺时）DrawSubImage命令，后端不需要额外支持
+func (l *DrawList) DrawNineSlice(img image.Image, slice NineSlice, r image.Rectangle, opacity int) {
+	if slice.IsZero() {
+		l.DrawImage(img, r, opacity)
+		return
+	}
+	if img == nil || r.Empty() || opacity <= 0 {
+		return
+	}
+	size := img.Bounds().Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return
+	}
+
+	// 边距限制在源图像内，目标不够时按比例缩小
+	left, right := clampSliceInsets(slice.Left, slice.Right, size.X)
+	top, bottom := clampSliceInsets(slice.Top, slice.Bottom, size.Y)
+	dstLeft, dstRight := clampSliceInsets(left, right, r.Dx())
+	dstTop, dstBottom := clampSliceInsets(top, bottom, r.Dy())
+
+	srcX := [4]int{0, left, size.X - right, size.X}
+	srcY := [4]int{0, top, size.Y - bottom, size.Y}
+	dstX := [4]int{r.Min.X, r.Min.X + dstLeft, r.Max.X - dstRight, r.Max.X}
+	dstY := [4]int{r.Min.Y, r.Min.Y + dstTop, r.Max.Y - dstBottom, r.Max.Y}
+
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			src := image.Rect(srcX[col], srcY[row], srcX[col+1], srcY[row+1])
+			dst := image.Rect(dstX[col], dstY[row], dstX[col+1], dstY[row+1])
+			if src.Empty() || dst.Empty() {
+				continue
+			}
+			var tileX, tileY bool
+			switch {
+			case row == 1 && col == 1:
+				tileX = slice.CenterMode == NineSliceTile
+				tileY = tileX
+			case col == 1:
+				tileX = slice.EdgeMode == NineSliceTile
+			case row == 1:
+				tileY = slice.EdgeMode == NineSliceTile
+			}
+			l.drawTiled(img, src, dst, tileX, tileY, opacity)
+		}
+	}
+}
+
+// drawTiled 把源区域绘制到目标区域：平铺的方向上按源尺寸重复，其他方向拉伸
+func (l *DrawList) drawTiled(img image.Image, src, dst image.Rectangle, tileX, tileY bool, opacity int) {
+	stepW, stepH := dst.Dx(), dst.Dy()
+	if tileX {
+		stepW = src.Dx()
+	}
+	if tileY {
+		stepH = src.Dy()
+	}
+	for y := dst.Min.Y; y < dst.Max.Y; y += stepH {
+		h, srcH := dst.Dy(), src.Dy()
+		if tileY {
+			h = min(stepH, dst.Max.Y-y)
+			srcH = h
+		}
+		for x := dst.Min.X; x < dst.Max.X; x += stepW {
+			w, srcW := dst.Dx(), src.Dx()
+			if tileX {
+				w = min(stepW, dst.Max.X-x)
+				srcW = w
+			}
+			part := image.Rect(src.Min.X, src.Min.Y, src.Min.X+srcW, src.Min.Y+srcH)
+			l.DrawSubImage(img, part, image.Rect(x, y, x+w, y+h), opacity)
+		}
+	}
+}
+
+// clampSliceInsets 两侧边距之和超过长度时按比例缩小
+func clampSliceInsets(a, b, length int) (int, int) {
+	a, b = max(a, 0), max(b, 0)
+	if a+b <= length {
+		return a, b
+	}
+	if length <= 0 {
+		return 0, 0
+	}
+	scaledA := a * length / (a + b)
+	return scaledA, length - scaledA
+}
+
+// paintBackgroundImage 记录背景图片（有切片时按九宫格绘制）
+func paintBackgroundImage(list *DrawList, img *ebiten.Image, slice NineSlice, bounds image.Rectangle) {
+	if img == nil {
+		return
+	}
+	list.DrawNineSlice(img, slice, bounds, 100)
+}
+
+// backgroundSlicer 能提供背景图片切片的控件（嵌入BaseWidget的控件都实现了该接口）
+type backgroundSlicer interface {
+	BackgroundSliceFor(img *ebiten.Image) NineSlice
+}
+
+// BackgroundSliceFor 获取背景图片的九宫格切片
+// 控件声明的BackgroundSlice优先，否则使用加载时从资源清单中得到的该图片资源的切片
+func (w *BaseWidget) BackgroundSliceFor(img *ebiten.Image) NineSlice {
+	if !w.BackgroundSlice.IsZero() {
+		return w.BackgroundSlice
+	}
+	return w.resourceSlices[img]
+}
+
+// SetBackgroundSlice 设置控件所有背景图片（包括三态背景）使用的九宫格切片
+func (w *BaseWidget) SetBackgroundSlice(slice NineSlice) {
+	w.BackgroundSlice = slice
+	w.MarkDirty()
+}
+
+// setResourceSlice 记录背景图片资源在资源清单中声明的切片
+func (w *BaseWidget) setResourceSlice(img *ebiten.Image, slice NineSlice) {
+	if img == nil || slice.IsZero() {
+		return
+	}
+	if w.resourceSlices == nil {
+		w.resourceSlices = make(map[*ebiten.Image]NineSlice)
+	}
+	w.resourceSlices[img] = slice
+}
@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// sliceRects 提取命令的源区域和目标区域
+func sliceRects(list *DrawList) (src, dst []image.Rectangle) {
+	for _, cmd := range list.Commands {
+		if cmd.Op == OpImage {
+			src = append(src, cmd.Source)
+			dst = append(dst, cmd.Rect)
+		}
+	}
+	return src, dst
+}
+
+// TestNineSlice_CornersKeepSourceSize 测试四个角保持源尺寸，边缘和中心拉伸
+func TestNineSlice_CornersKeepSourceSize(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 12, 12))
+	var list DrawList
+	list.DrawNineSlice(img, NineSlice{Top: 4, Right: 4, Bottom: 4, Left: 4}, image.Rect(10, 10, 110, 50), 100)
+
+	src, dst := sliceRects(&list)
+	if len(dst) != 9 {
+		t.Fatalf("expected 9 regions, got %d", len(dst))
+	}
+	checks := []struct {
+		i        int
+		src, dst image.Rectangle
+	}{
+		{0, image.Rect(0, 0, 4, 4), image.Rect(10, 10, 14, 14)},     // 左上角
+		{1, image.Rect(4, 0, 8, 4), image.Rect(14, 10, 106, 14)},    // 上边缘
+		{2, image.Rect(8, 0, 12, 4), image.Rect(106, 10, 110, 14)},  // 右上角
+		{4, image.Rect(4, 4, 8, 8), image.Rect(14, 14, 106, 46)},    // 中心
+		{8, image.Rect(8, 8, 12, 12), image.Rect(106, 46, 110, 50)}, // 右下角
+	}
+	for _, c := range checks {
+		if src[c.i] != c.src || dst[c.i] != c.dst {
+			t.Errorf("region %d: expected %v -> %v, got %v -> %v", c.i, c.src, c.dst, src[c.i], dst[c.i])
+		}
+	}
+}
+
+// TestNineSlice_TileEdgesAndCenter 测试平铺模式按源尺寸重复，末尾裁剪
+func TestNineSlice_TileEdgesAndCenter(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 9, 9))
+	var list DrawList
+	slice := NineSlice{Top: 3, Right: 3, Bottom: 3, Left: 3, EdgeMode: NineSliceTile}
+	list.DrawNineSlice(img, slice, image.Rect(0, 0, 14, 9), 100)
+
+	// 上边缘宽8：3 + 3 + 2（最后一块裁剪源区域）
+	src, dst := sliceRects(&list)
+	var top []image.Rectangle
+	for i := range dst {
+		if dst[i].Min.Y == 0 && dst[i].Min.X >= 3 && dst[i].Max.X <= 11 {
+			top = append(top, dst[i])
+			if dst[i].Dx() != src[i].Dx() || src[i].Min.X != 3 {
+				t.Errorf("expected tile to keep source width, got %v -> %v", src[i], dst[i])
+			}
+		}
+	}
+	want := []image.Rectangle{image.Rect(3, 0, 6, 3), image.Rect(6, 0, 9, 3), image.Rect(9, 0, 11, 3)}
+	if len(top) != len(want) {
+		t.Fatalf("expected top edge tiles %v, got %v", want, top)
+	}
+	for i := range want {
+		if top[i] != want[i] {
+			t.Errorf("tile %d: expected %v, got %v", i, want[i], top[i])
+		}
+	}
+
+	// 中心也平铺
+	list.Reset()
+	slice.CenterMode = NineSliceTile
+	list.DrawNineSlice(img, slice, image.Rect(0, 0, 15, 15), 100)
+	_, dst = sliceRects(&list)
+	// 4个角 + 4条边各3块 + 中心3x3块
+	if len(dst) != 4+4*3+9 {
+		t.Errorf("expected %d commands with tiled center, got %d", 4+4*3+9, len(dst))
+	}
+}
+
+// TestNineSlice_ShrinksCornersWhenTooSmall 测试目标小于两侧边距之和时角按比例缩小
+func TestNineSlice_ShrinksCornersWhenTooSmall(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	var list DrawList
+	list.DrawNineSlice(img, NineSlice{Top: 8, Right: 4, Bottom: 8, Left: 12}, image.Rect(0, 0, 8, 40), 100)
+
+	_, dst := sliceRects(&list)
+	for _, r := range dst {
+		if r.Min.X < 0 || r.Max.X > 8 {
+			t.Errorf("region %v escapes destination", r)
+		}
+	}
+	// 左右边距12:4按比例缩小为6:2，中间列为空
+	if dst[0] != image.Rect(0, 0, 6, 8) {
+		t.Errorf("expected scaled top-left corner (0,0)-(6,8), got %v", dst[0])
+	}
+	if len(dst) != 6 {
+		t.Errorf("expected empty center column to be skipped, got %d regions", len(dst))
+	}
+
+	// 没有切片时整个图像拉伸
+	list.Reset()
+	list.DrawNineSlice(img, NineSlice{}, image.Rect(0, 0, 8, 40), 100)
+	if list.Len() != 1 || !list.Commands[0].Source.Empty() {
+		t.Errorf("expected a single stretched image, got %v", list.Commands)
+	}
+}
+
+// TestNineSlice_LoaderResolvesManifestAndWidgetSlices 测试资源清单中的切片应用到三态背景，控件声明的切片优先
+func TestNineSlice_LoaderResolvesManifestAndWidgetSlices(t *testing.T) {
+	loader := NewLoader()
+	manifest := loader.parseManifest(map[string]interface{}{
+		"version": float64(1),
+		"resources": []interface{}{
+			map[string]interface{}{"id": "btn_normal", "type": "image",
+				"nineSlice": map[string]interface{}{"insets": float64(6), "edgeMode": "tile"}},
+			map[string]interface{}{"id": "btn_pressed", "type": "image"},
+		},
+	})
+	loader.manifest = manifest
+	normal := ebiten.NewImage(16, 16)
+	pressed := ebiten.NewImage(16, 16)
+	defer normal.Dispose()
+	defer pressed.Dispose()
+	loader.imageCache["btn_normal"] = normal
+	loader.imageCache["btn_pressed"] = pressed
+
+	widget, err := loader.createWidget(map[string]interface{}{
+		"id": "b", "type": "button",
+		"backgroundResourceNormal":  "btn_normal",
+		"backgroundResourcePressed": "btn_pressed",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	btn := widget.(*ButtonWidget)
+	want := NineSlice{Top: 6, Right: 6, Bottom: 6, Left: 6, EdgeMode: NineSliceTile}
+	if got := btn.BackgroundSliceFor(normal); got != want {
+		t.Errorf("expected manifest slice %+v, got %+v", want, got)
+	}
+	if got := btn.BackgroundSliceFor(pressed); !got.IsZero() {
+		t.Errorf("expected no slice for pressed image, got %+v", got)
+	}
+
+	// 控件声明的切片覆盖资源清单
+	widget, err = loader.createWidget(map[string]interface{}{
+		"id": "in", "type": "textinput",
+		"backgroundResourceNormal": "btn_normal",
+		"backgroundSlice":          map[string]interface{}{"top": float64(2), "bottom": float64(2)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := widget.(*TextInputWidget)
+	if _, img := input.GetStateBackground(); img != normal {
+		t.Fatalf("expected text input state background to be loaded")
+	}
+	if got := input.BackgroundSliceFor(normal); got != (NineSlice{Top: 2, Bottom: 2}) {
+		t.Errorf("expected widget slice to override manifest, got %+v", got)
+	}
+}
+
+// roundedFrame 创建带圆角边框的12x12测试图像（边框宽3，中心浅色）
+func roundedFrame() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 12, 12))
+	for y := 0; y < 12; y++ {
+		for x := 0; x < 12; x++ {
+			corner := (x == 0 || x == 11) && (y == 0 || y == 11)
+			switch {
+			case corner:
+			case x < 3 || x >= 9 || y < 3 || y >= 9:
+				img.Set(x, y, color.RGBA{40, 90, 160, 255})
+			default:
+				img.Set(x, y, color.RGBA{220, 230, 245, 255})
+			}
+		}
+	}
+	return img
+}
+
+// TestGolden_NineSlice 九宫格背景的golden图像（按钮三态背景、面板背景资源）
+func TestGolden_NineSlice(t *testing.T) {
+	src := roundedFrame()
+	img := ebiten.NewImageFromImage(src)
+	defer img.Dispose()
+	sources := map[*ebiten.Image]image.Image{img: src}
+
+	btn := NewButton("b")
+	btn.X, btn.Y, btn.Width, btn.Height = 5, 5, 110, 30
+	btn.Text = "Sliced"
+	btn.BackgroundColorNormalAlpha = 0
+	btn.backgroundImageNormal = img
+	btn.SetBackgroundSlice(NineSlice{Top: 3, Right: 3, Bottom: 3, Left: 3})
+
+	panel := NewPanel("p")
+	panel.X, panel.Y, panel.Width, panel.Height = 5, 40, 110, 50
+	panel.backgroundImage = img
+	panel.setResourceSlice(img, NineSlice{Top: 3, Right: 3, Bottom: 3, Left: 3, EdgeMode: NineSliceTile, CenterMode: NineSliceTile})
+
+	renderGolden(t, "nine_slice", 120, 95, sources, btn, panel)
+}
@@ -1,12 +1,32 @@
 package ui
 
 import (
+	"image"
+
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
 // PanelWidget 面板控件（容器）
 type PanelWidget struct {
 	BaseWidget
+
+	// 模态面板可见时屏蔽其下方控件的指针输入，并消耗所有键盘输入
+	Modal bool `json:"modal"`
+
+	// 子控件布局："absolute"（默认，使用子控件自身的定位）、"flex" 或 "grid"
+	Layout     string `json:"layout"`
+	Gap        int    `json:"gap"`        // 子控件之间（flex换行后的各行之间、grid的行列之间）的间距
+	AlignItems string `json:"alignItems"` // 交叉轴/单元格内垂直对齐："start"（默认）、"center"、"end"、"stretch"
+
+	// 弹性布局
+	Direction string `json:"direction"` // 主轴方向："row"（默认）或 "column"
+	Justify   string `json:"justify"`   // 主轴对齐："start"、"center"、"end"、"space-between"、"space-around"、"space-evenly"
+	Wrap      bool   `json:"wrap"`      // 主轴放不下时换行
+
+	// 网格布局（轨道定义为"120"、"120px"、"1fr"或"auto"）
+	GridColumns  []string `json:"gridColumns"`
+	GridRows     []string `json:"gridRows"`
+	JustifyItems string   `json:"justifyItems"` // 单元格内水平对齐
 }
 
 // NewPanel 创建面板
@@ -24,6 +44,56 @@ func NewPanel(id string) *PanelWidget {
 	}
 }
 
+// IsModal 是否为模态面板
+func (p *PanelWidget) IsModal() bool {
+	return p.Modal
+}
+
+// FlexOptions 获取面板的弹性布局参数
+func (p *PanelWidget) FlexOptions() FlexOptions {
+	return FlexOptions{
+		Direction:  p.Direction,
+		Gap:        p.Gap,
+		Justify:    p.Justify,
+		AlignItems: p.AlignItems,
+		Wrap:       p.Wrap,
+	}
+}
+
+// GridOptions 获取面板的网格布局参数
+func (p *PanelWidget) GridOptions() GridOptions {
+	return GridOptions{
+		Columns:      ParseGridTracks(p.GridColumns),
+		Rows:         ParseGridTracks(p.GridRows),
+		Gap:          p.Gap,
+		JustifyItems: p.JustifyItems,
+		AlignItems:   p.AlignItems,
+	}
+}
+
+// LayoutChildren 按布局模式排列子控件
+func (p *PanelWidget) LayoutChildren(width, height int) {
+	switch p.Layout {
+	case "flex":
+		LayoutFlex(p.Children, p.FlexOptions(), width, height)
+	case "grid":
+		LayoutGrid(p.Children, p.GridOptions(), width, height)
+	default:
+		clearLayoutBounds(p.Children)
+	}
+}
+
+// MeasureChildren 根据子控件和布局模式计算面板的首选尺寸
+func (p *PanelWidget) MeasureChildren() image.Point {
+	switch p.Layout {
+	case "flex":
+		return MeasureFlex(p.Children, p.FlexOptions())
+	case "grid":
+		return MeasureGrid(p.Children, p.GridOptions())
+	}
+	return measureAbsoluteChildren(p.Children)
+}
+
 // Draw 绘制面板
 func (p *PanelWidget) Draw(screen *ebiten.Image, parentX, parentY, parentWidth, parentHeight int) {
 	if !p.Visible {
@@ -38,42 +108,39 @@ func (p *PanelWidget) Draw(screen *ebiten.Image, parentX, parentY, parentWidth,
 	// 计算响应式尺寸（支持边界锚定）
 	renderWidth, renderHeight := p.CalculateSize(parentWidth, parentHeight, localX, localY)
 
-	// 创建面板图像
-	if p.BackgroundAlpha > 0 || p.backgroundImage != nil {
-		panelImage := ebiten.NewImage(renderWidth, renderHeight)
-
-		// 背景颜色
-		if p.BackgroundAlpha > 0 {
-			bgColor := RGBA{
-				R: p.BackgroundColor.R,
-				G: p.BackgroundColor.G,
-				B: p.BackgroundColor.B,
-				A: p.BackgroundAlpha,
-			}
-			panelImage.Fill(bgColor.ToColor())
-		}
-
-		// 背景图片
-		if p.backgroundImage != nil {
-			op := &ebiten.DrawImageOptions{}
-			srcW, srcH := p.backgroundImage.Size()
-			scaleX := float64(renderWidth) / float64(srcW)
-			scaleY := float64(renderHeight) / float64(srcH)
-			op.GeoM.Scale(scaleX, scaleY)
-			panelImage.DrawImage(p.backgroundImage, op)
-		}
-
-		// 绘制到屏幕
-		op := &ebiten.DrawImageOptions{}
-		op.GeoM.Translate(float64(absX), float64(absY))
-
-		if p.Opacity < 100 {
-			op.ColorScale.ScaleAlpha(float32(p.Opacity) / 100.0)
-		}
-
-		screen.DrawImage(panelImage, op)
-	}
+	drawPainted(screen, p, image.Rect(absX, absY, absX+renderWidth, absY+renderHeight))
 
 	// 绘制子控件（传递Panel自己的绝对坐标和响应式尺寸作为子控件的父容器信息）
+	// 布局过程已经排列过子控件时直接使用其结果
+	if !p.hasComputed {
+		content := p.ContentRect(image.Rect(0, 0, renderWidth, renderHeight))
+		p.LayoutChildren(content.Dx(), content.Dy())
+	}
 	p.BaseWidget.DrawChildren(screen, absX, absY, renderWidth, renderHeight)
 }
+
+// Paint 记录面板的阴影、背景（颜色或渐变）、拉伸的背景图片和边框（叠加后整体应用透明度）
+func (p *PanelWidget) Paint(list *DrawList, bounds image.Rectangle) {
+	if !p.hasBoxDecoration() {
+		return
+	}
+	p.paintOuterShadows(list, bounds, p.Opacity)
+	pushGroup(list, bounds, p.Opacity)
+
+	// 背景颜色或渐变
+	p.paintFill(list, bounds, RGBA{
+		R: p.BackgroundColor.R,
+		G: p.BackgroundColor.G,
+		B: p.BackgroundColor.B,
+		A: p.BackgroundAlpha,
+	})
+
+	// 背景图片
+	paintBackgroundImage(list, p.backgroundImage, p.BackgroundSliceFor(p.backgroundImage), bounds)
+	p.paintInnerShadows(list, bounds)
+
+	// 边框
+	p.paintBorder(list, bounds)
+
+	popGroup(list, p.Opacity)
+}
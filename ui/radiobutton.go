@@ -1,10 +1,9 @@
 package ui
 
 import (
-	"image/color"
+	"image"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text"
 	"golang.org/x/image/font/basicfont"
 )
 
@@ -85,31 +84,38 @@ func (r *RadioButtonWidget) Draw(screen *ebiten.Image, parentX, parentY, parentW
 	y := parentY + localY
 
 	// 计算响应式尺寸
-	_, height := r.CalculateSize(parentWidth, parentHeight, localX, localY)
+	width, height := r.CalculateSize(parentWidth, parentHeight, localX, localY)
+	drawPainted(screen, r, image.Rect(x, y, x+width, y+height))
+}
+
+// Paint 记录单选按钮、内圆点和文本标签（在内容区域内布局）
+func (r *RadioButtonWidget) Paint(list *DrawList, bounds image.Rectangle) {
+	content := r.ContentRect(bounds)
+	x, y, height := content.Min.X, content.Min.Y, content.Dy()
 
 	// 绘制单选按钮（圆形）
 	buttonY := y + (height-r.ButtonSize)/2
 	centerX := x + r.ButtonSize/2
 	centerY := buttonY + r.ButtonSize/2
-	r.drawButton(screen, centerX, centerY, r.ButtonSize/2)
+	r.paintButton(list, centerX, centerY, r.ButtonSize/2)
 
 	// 如果选中，绘制内圆点
 	if r.Selected {
-		r.drawDot(screen, centerX, centerY, r.ButtonSize/2)
+		r.paintDot(list, centerX, centerY, r.ButtonSize/2)
 	}
 
 	// 绘制文本标签
 	if r.Text != "" {
 		textX := x + r.ButtonSize + 8
 		textY := y + height/2
-		r.drawText(screen, textX, textY)
+		r.paintText(list, textX, textY)
 	}
 }
 
-// drawButton 绘制单选按钮（圆形）
-func (r *RadioButtonWidget) drawButton(screen *ebiten.Image, centerX, centerY, radius int) {
+// paintButton 记录单选按钮（圆形）
+func (r *RadioButtonWidget) paintButton(list *DrawList, centerX, centerY, radius int) {
 	// 确定背景色
-	bgColor := color.RGBA{
+	bgColor := RGBA{
 		R: r.ButtonBgColor.R,
 		G: r.ButtonBgColor.G,
 		B: r.ButtonBgColor.B,
@@ -117,7 +123,7 @@ func (r *RadioButtonWidget) drawButton(screen *ebiten.Image, centerX, centerY, r
 	}
 
 	if r.Selected {
-		bgColor = color.RGBA{
+		bgColor = RGBA{
 			R: r.SelectedBgColor.R,
 			G: r.SelectedBgColor.G,
 			B: r.SelectedBgColor.B,
@@ -130,17 +136,11 @@ func (r *RadioButtonWidget) drawButton(screen *ebiten.Image, centerX, centerY, r
 	}
 
 	// 绘制圆形背景
-	for dy := -radius; dy <= radius; dy++ {
-		for dx := -radius; dx <= radius; dx++ {
-			if dx*dx+dy*dy <= radius*radius {
-				screen.Set(centerX+dx, centerY+dy, bgColor)
-			}
-		}
-	}
+	list.FillCircle(centerX, centerY, radius, bgColor)
 
 	// 绘制边框
 	if r.BorderWidth > 0 {
-		borderColor := color.RGBA{
+		borderColor := RGBA{
 			R: r.BorderColor.R,
 			G: r.BorderColor.G,
 			B: r.BorderColor.B,
@@ -151,20 +151,13 @@ func (r *RadioButtonWidget) drawButton(screen *ebiten.Image, centerX, centerY, r
 			borderColor.A = 128
 		}
 
-		for dy := -radius; dy <= radius; dy++ {
-			for dx := -radius; dx <= radius; dx++ {
-				dist := dx*dx + dy*dy
-				if dist <= radius*radius && dist >= (radius-r.BorderWidth)*(radius-r.BorderWidth) {
-					screen.Set(centerX+dx, centerY+dy, borderColor)
-				}
-			}
-		}
+		list.StrokeCircle(centerX, centerY, radius, r.BorderWidth, borderColor)
 	}
 }
 
-// drawDot 绘制内圆点
-func (r *RadioButtonWidget) drawDot(screen *ebiten.Image, centerX, centerY, outerRadius int) {
-	dotColor := color.RGBA{
+// paintDot 记录内圆点
+func (r *RadioButtonWidget) paintDot(list *DrawList, centerX, centerY, outerRadius int) {
+	dotColor := RGBA{
 		R: r.DotColor.R,
 		G: r.DotColor.G,
 		B: r.DotColor.B,
@@ -182,19 +175,13 @@ func (r *RadioButtonWidget) drawDot(screen *ebiten.Image, centerX, centerY, oute
 	}
 
 	// 绘制实心圆点
-	for dy := -dotRadius; dy <= dotRadius; dy++ {
-		for dx := -dotRadius; dx <= dotRadius; dx++ {
-			if dx*dx+dy*dy <= dotRadius*dotRadius {
-				screen.Set(centerX+dx, centerY+dy, dotColor)
-			}
-		}
-	}
+	list.FillCircle(centerX, centerY, dotRadius, dotColor)
 }
 
-// drawText 绘制文本标签
-func (r *RadioButtonWidget) drawText(screen *ebiten.Image, x, y int) {
+// paintText 记录文本标签
+func (r *RadioButtonWidget) paintText(list *DrawList, x, y int) {
 	face := basicfont.Face7x13
-	textColor := color.RGBA{
+	textColor := RGBA{
 		R: r.TextColor.R,
 		G: r.TextColor.G,
 		B: r.TextColor.B,
@@ -205,19 +192,23 @@ func (r *RadioButtonWidget) drawText(screen *ebiten.Image, x, y int) {
 		textColor.A = 128
 	}
 
-	text.Draw(screen, r.Text, face, x, y+4, textColor)
+	list.DrawText(r.Text, face, x, y+4, textColor)
 }
 
 // Select 选中此单选按钮
 func (r *RadioButtonWidget) Select() {
-	if r.Enabled {
+	if r.Enabled && !r.Selected {
 		r.Selected = true
+		r.MarkDirty()
 	}
 }
 
 // Deselect 取消选中
 func (r *RadioButtonWidget) Deselect() {
-	r.Selected = false
+	if r.Selected {
+		r.Selected = false
+		r.MarkDirty()
+	}
 }
 
 // IsSelected 获取选中状态
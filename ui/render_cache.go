@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// cachedWidget 支持脏标记、可以作为缓存块的控件
+// 所有嵌入BaseWidget的控件都实现了该接口
+type cachedWidget interface {
+	Widget
+	IsDirty() bool
+	clearDirty()
+	renderCacheEntry() *cacheEntry
+	setRenderCacheEntry(e *cacheEntry)
+}
+
+// RenderCacheStats 最近一帧的渲染缓存统计
+type RenderCacheStats struct {
+	Blocks  int // 缓存块（有子控件的可见容器）数量
+	Redrawn int // 重新绘制到离屏图像的缓存块数
+	Reused  int // 直接合成缓存图像的缓存块数
+}
+
+// RenderCache 保留模式的渲染缓存
+// 有子控件的容器作为缓存块，其子树绘制到离屏图像中；之后的帧在子树没有控件变脏、区域没有改变时直接合成缓存图像。
+// 控件变脏时包含它的缓存块重新绘制，其中未变化的内层缓存块仍然直接合成；
+// 没有嵌入BaseWidget的控件不支持脏标记，所在的缓存块每帧重新绘制。
+// 离屏图像来自缓存自己的图像池，不再使用的缓存块每帧结束时把图像放回图像池，Dispose释放全部图像。
+type RenderCache struct {
+	pool    *ImagePool
+	entries map[Widget]*cacheEntry
+	frame   uint64
+	drawing bool
+	stats   RenderCacheStats
+}
+
+// cacheEntry 一个缓存块的离屏图像和绘制参数
+type cacheEntry struct {
+	cache  *RenderCache
+	widget cachedWidget
+	image  *ebiten.Image
+	rect   image.Rectangle // 离屏图像覆盖的区域（绘制坐标系）
+	parent image.Rectangle // 绘制时父容器的内容区域
+	valid  bool
+	frame  uint64 // 最近一次分配给控件的帧
+}
+
+// NewRenderCache 创建渲染缓存
+func NewRenderCache() *RenderCache {
+	return &RenderCache{
+		pool:    NewImagePool(),
+		entries: make(map[Widget]*cacheEntry),
+	}
+}
+
+// Draw 按层叠顺序绘制控件树（与DrawWidgets的结果相同），复用未变化子树的缓存图像
+func (c *RenderCache) Draw(screen *ebiten.Image, roots []Widget, viewportWidth, viewportHeight int) {
+	c.frame++
+	c.stats = RenderCacheStats{}
+	c.attach(roots)
+
+	c.drawing = true
+	DrawWidgets(screen, roots, viewportWidth, viewportHeight)
+	c.drawing = false
+
+	c.release(false)
+}
+
+// Invalidate 丢弃所有缓存图像的内容，下一帧全部重新绘制（图像保留以便复用）
+func (c *RenderCache) Invalidate() {
+	for _, e := range c.entries {
+		e.valid = false
+	}
+}
+
+// Dispose 释放所有缓存图像和图像池（之后仍然可以继续使用，会重新创建图像）
+func (c *RenderCache) Dispose() {
+	c.release(true)
+	c.pool.Dispose()
+}
+
+// Stats 获取最近一帧的统计
+func (c *RenderCache) Stats() RenderCacheStats {
+	return c.stats
+}
+
+// Pool 获取缓存图像使用的图像池
+func (c *RenderCache) Pool() *ImagePool {
+	return c.pool
+}
+
+// attach 为本帧的缓存块（有子控件的可见容器）分配条目
+func (c *RenderCache) attach(widgets []Widget) {
+	for _, widget := range widgets {
+		if !widget.IsVisible() {
+			continue
+		}
+		children := widget.GetChildren()
+		if w, ok := widget.(cachedWidget); ok && len(children) > 0 {
+			e := c.entries[widget]
+			if e == nil {
+				e = &cacheEntry{cache: c, widget: w}
+				c.entries[widget] = e
+			}
+			e.frame = c.frame
+			w.setRenderCacheEntry(e)
+			c.stats.Blocks++
+		}
+		c.attach(children)
+	}
+}
+
+// release 释放本帧没有分配的条目（all为true时释放全部），图像放回图像池
+func (c *RenderCache) release(all bool) {
+	for widget, e := range c.entries {
+		if !all && e.frame == c.frame {
+			continue
+		}
+		if e.widget.renderCacheEntry() == e {
+			e.widget.setRenderCacheEntry(nil)
+		}
+		if e.image != nil {
+			c.pool.Put(e.image)
+			e.image = nil
+		}
+		delete(c.entries, widget)
+	}
+}
+
+// activeCacheEntry 获取控件在正在进行的缓存绘制中分配到的条目（不是缓存块或不在缓存绘制中时返回nil）
+func activeCacheEntry(widget Widget) *cacheEntry {
+	w, ok := widget.(cachedWidget)
+	if !ok {
+		return nil
+	}
+	e := w.renderCacheEntry()
+	if e == nil || !e.cache.drawing || e.frame != e.cache.frame {
+		return nil
+	}
+	return e
+}
+
+// draw 合成缓存块（子树变脏或区域改变时先重新绘制离屏图像）
+func (e *cacheEntry) draw(screen *ebiten.Image, parent image.Rectangle) {
+	if !e.widget.IsVisible() {
+		return
+	}
+	rect := subtreeBounds(e.widget, parent).Intersect(screen.Bounds())
+	if rect.Empty() {
+		return
+	}
+
+	if !e.valid || rect != e.rect || parent != e.parent || subtreeDirty(e.widget) {
+		e.render(rect, parent)
+		e.cache.stats.Redrawn++
+	} else {
+		e.cache.stats.Reused++
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(rect.Min.X), float64(rect.Min.Y))
+	screen.DrawImage(e.image, op)
+}
+
+// render 把子树平移到离屏图像的坐标系中重新绘制，并清除子树的脏标记
+func (e *cacheEntry) render(rect, parent image.Rectangle) {
+	if e.image == nil || e.image.Bounds().Size() != rect.Size() {
+		e.cache.pool.Put(e.image)
+		e.image = e.cache.pool.Get(rect.Dx(), rect.Dy())
+	} else {
+		e.image.Clear()
+	}
+
+	local := parent.Sub(rect.Min)
+	e.widget.Draw(e.image, local.Min.X, local.Min.Y, local.Dx(), local.Dy())
+	clearSubtreeDirty(e.widget)
+	e.rect, e.parent, e.valid = rect, parent, true
+}
+
+// subtreeDirty 判断控件或其后代是否变脏（不支持脏标记的控件视为始终变脏）
+func subtreeDirty(widget Widget) bool {
+	if w, ok := widget.(cachedWidget); !ok || w.IsDirty() {
+		return true
+	}
+	for _, child := range widget.GetChildren() {
+		if subtreeDirty(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// clearSubtreeDirty 清除控件及其后代的脏标记
+func clearSubtreeDirty(widget Widget) {
+	if w, ok := widget.(cachedWidget); ok {
+		w.clearDirty()
+	}
+	for _, child := range widget.GetChildren() {
+		clearSubtreeDirty(child)
+	}
+}
+
+// subtreeBounds 计算控件及其绘制在自身之外的后代在绘制坐标系中覆盖的区域
+// 后代的边界使用布局过程缓存的绝对坐标，再按控件在绘制坐标系中的位置平移（绘制到离屏图像时两者不同）
+func subtreeBounds(widget Widget, parent image.Rectangle) image.Rectangle {
+	bounds := widgetBounds(widget, parent)
+	drawn := bounds
+	if bc, ok := widget.(boundsComputer); ok {
+		drawn = bc.ComputeBounds(parent.Min.X, parent.Min.Y, parent.Dx(), parent.Dy())
+	}
+	return descendantBounds(widget, bounds).Add(drawn.Min.Sub(bounds.Min))
+}
+
+// descendantBounds 计算控件及其后代覆盖的区域，包括绘制在边界之外的外阴影（裁剪子控件的控件只计算自身，覆盖层控件单独绘制不计算在内）
+func descendantBounds(widget Widget, bounds image.Rectangle) image.Rectangle {
+	area := bounds
+	if d, ok := widget.(boxDecorator); ok {
+		area = d.outerShadowBounds(bounds)
+	}
+	if clipsChildren(widget) {
+		return area
+	}
+	content := widgetContentBounds(widget, bounds)
+	for _, child := range widget.GetChildren() {
+		if child.IsVisible() && !isOverlay(child) {
+			area = area.Union(descendantBounds(child, widgetBounds(child, content)))
+		}
+	}
+	return area
+}
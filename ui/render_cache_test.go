@@ -0,0 +1,361 @@
+package ui
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"reflect"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// boundsRecorder 记录绘制时计算出的边界的测试控件
+type boundsRecorder struct {
+	*PanelWidget
+	drawn image.Rectangle
+}
+
+func (r *boundsRecorder) Draw(screen *ebiten.Image, parentX, parentY, parentWidth, parentHeight int) {
+	r.drawn = r.ComputeBounds(parentX, parentY, parentWidth, parentHeight)
+}
+
+// newCacheFixture 创建两层缓存块的控件树：root包含g1（子控件a）和g2（子控件b）
+func newCacheFixture(log *[]string) (root, g1, a, g2, b *drawRecorder) {
+	root = newDrawRecorder("root", 0, log)
+	root.Width, root.Height = 400, 300
+	g1 = newDrawRecorder("g1", 0, log)
+	g1.X, g1.Y, g1.Width, g1.Height = 10, 10, 100, 100
+	a = newDrawRecorder("a", 0, log)
+	a.Width, a.Height = 20, 20
+	g2 = newDrawRecorder("g2", 0, log)
+	g2.X, g2.Y, g2.Width, g2.Height = 200, 10, 100, 100
+	b = newDrawRecorder("b", 0, log)
+	b.Width, b.Height = 20, 20
+	g1.AddChild(a)
+	g2.AddChild(b)
+	root.AddChild(g1)
+	root.AddChild(g2)
+	return root, g1, a, g2, b
+}
+
+// renderCachedRGBA 按渲染缓存的方式用纯Go后端渲染：顶层缓存块只保留离屏图像覆盖的区域（subtreeBounds）
+func renderCachedRGBA(roots []Widget, width, height int) (cached, direct *image.RGBA) {
+	PerformLayout(roots, width, height)
+	direct = RenderToRGBA(roots, width, height, nil)
+	cached = image.NewRGBA(direct.Bounds())
+	viewport := image.Rect(0, 0, width, height)
+	for _, root := range roots {
+		rect := subtreeBounds(root, viewport).Intersect(viewport)
+		draw.Draw(cached, rect, direct, rect.Min, draw.Src)
+	}
+	return cached, direct
+}
+
+// TestImagePool_ReusesBySize 测试图像按尺寸复用，超出上限和Trim时释放
+func TestImagePool_ReusesBySize(t *testing.T) {
+	pool := NewImagePool()
+	pool.SizeLimit = 1
+
+	img := pool.Get(10, 20)
+	pool.Put(img)
+	if got := pool.Get(10, 20); got != img {
+		t.Error("Expected the pooled image to be reused for the same size")
+	}
+	other := pool.Get(20, 10)
+	if other == img {
+		t.Error("Expected a new image for a different size")
+	}
+
+	extra := pool.Get(10, 20)
+	pool.Put(img)
+	pool.Put(extra) // 超出每种尺寸的上限，直接释放
+	pool.Put(other)
+
+	stats := pool.Stats()
+	if stats.Allocated != 3 || stats.Reused != 1 || stats.Disposed != 1 || stats.Idle != 2 {
+		t.Errorf("Unexpected stats before trim: %+v", stats)
+	}
+
+	pool.Trim()
+	if stats := pool.Stats(); stats.Disposed != 3 || stats.Idle != 0 {
+		t.Errorf("Expected trim to dispose idle images, got %+v", stats)
+	}
+}
+
+// TestBaseWidget_DirtyTracking 测试Set方法、布局结果变化和滚动位置变化设置脏标记
+func TestBaseWidget_DirtyTracking(t *testing.T) {
+	panel := NewPanel("p")
+	panel.Width, panel.Height = 100, 100
+	if panel.IsDirty() {
+		t.Fatal("Expected a new widget to start clean")
+	}
+
+	PerformLayout([]Widget{panel}, 800, 600)
+	if !panel.IsDirty() {
+		t.Error("Expected the first layout to mark the widget dirty")
+	}
+	panel.clearDirty()
+	PerformLayout([]Widget{panel}, 800, 600)
+	if panel.IsDirty() {
+		t.Error("Expected an unchanged layout to keep the widget clean")
+	}
+
+	panel.SetVisible(false)
+	if !panel.IsDirty() {
+		t.Error("Expected SetVisible to mark the widget dirty")
+	}
+
+	label := NewLabel("l")
+	label.SetText("hello")
+	if !label.IsDirty() {
+		t.Error("Expected SetText to mark the label dirty")
+	}
+
+	radio := NewRadioButton("r", 0, 0, 100, 20)
+	radio.Select()
+	if !radio.IsDirty() {
+		t.Error("Expected Select to mark the radio button dirty")
+	}
+	radio.clearDirty()
+	radio.Select()
+	if radio.IsDirty() {
+		t.Error("Expected selecting a selected radio button to keep it clean")
+	}
+	radio.Deselect()
+	if !radio.IsDirty() {
+		t.Error("Expected Deselect to mark the radio button dirty")
+	}
+
+	scroll := NewScrollPanel("s")
+	scroll.Width, scroll.Height = 100, 50
+	child := NewButton("c")
+	child.Width, child.Height = 50, 200
+	scroll.AddChild(child)
+	PerformLayout([]Widget{scroll}, 800, 600)
+	scroll.clearDirty()
+	child.clearDirty()
+	scroll.ScrollTo(0, 30)
+	if !scroll.IsDirty() || !child.IsDirty() {
+		t.Error("Expected scrolling to mark the panel and the moved child dirty")
+	}
+}
+
+// TestRenderCache_ReusesCleanSubtree 测试未变化的子树直接合成缓存图像，不再调用控件的Draw
+func TestRenderCache_ReusesCleanSubtree(t *testing.T) {
+	var log []string
+	root, _, _, _, _ := newCacheFixture(&log)
+	roots := []Widget{root}
+	PerformLayout(roots, 800, 600)
+
+	screen := ebiten.NewImage(800, 600)
+	defer screen.Dispose()
+	cache := NewRenderCache()
+	defer cache.Dispose()
+
+	cache.Draw(screen, roots, 800, 600)
+	if want := []string{"root", "g1", "a", "g2", "b"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("Expected first frame to draw %v, got %v", want, log)
+	}
+	if stats := cache.Stats(); stats.Blocks != 3 || stats.Redrawn != 3 || stats.Reused != 0 {
+		t.Errorf("Unexpected first frame stats: %+v", stats)
+	}
+
+	log = nil
+	cache.Draw(screen, roots, 800, 600)
+	if len(log) != 0 {
+		t.Errorf("Expected a static frame to draw nothing, got %v", log)
+	}
+	if stats := cache.Stats(); stats.Redrawn != 0 || stats.Reused != 1 {
+		t.Errorf("Expected only the root block to be composited, got %+v", stats)
+	}
+}
+
+// TestRenderCache_RedrawsDirtyBlockOnly 测试控件变脏时只重新绘制包含它的缓存块，兄弟缓存块继续复用
+func TestRenderCache_RedrawsDirtyBlockOnly(t *testing.T) {
+	var log []string
+	root, _, a, _, b := newCacheFixture(&log)
+	roots := []Widget{root}
+	PerformLayout(roots, 800, 600)
+
+	screen := ebiten.NewImage(800, 600)
+	defer screen.Dispose()
+	cache := NewRenderCache()
+	defer cache.Dispose()
+	cache.Draw(screen, roots, 800, 600)
+
+	log = nil
+	a.MarkDirty()
+	cache.Draw(screen, roots, 800, 600)
+	if want := []string{"root", "g1", "a"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("Expected dirty block to redraw %v, got %v", want, log)
+	}
+	if stats := cache.Stats(); stats.Redrawn != 2 || stats.Reused != 1 {
+		t.Errorf("Expected root and g1 redrawn with g2 reused, got %+v", stats)
+	}
+
+	// 布局结果改变（尺寸变化）同样触发重新绘制
+	log = nil
+	b.Width = 40
+	PerformLayout(roots, 800, 600)
+	cache.Draw(screen, roots, 800, 600)
+	if want := []string{"root", "g2", "b"}; !reflect.DeepEqual(log, want) {
+		t.Errorf("Expected layout change to redraw %v, got %v", want, log)
+	}
+}
+
+// TestRenderCache_UntrackedWidgetsAlwaysRedrawn 测试不支持脏标记的控件所在的缓存块每帧重新绘制
+func TestRenderCache_UntrackedWidgetsAlwaysRedrawn(t *testing.T) {
+	var log []string
+	root := newDrawRecorder("root", 0, &log)
+	root.Width, root.Height = 200, 200
+	root.AddChild(&MockWidget{id: "mock", widgetType: TypeLabel})
+	roots := []Widget{root}
+	PerformLayout(roots, 800, 600)
+
+	screen := ebiten.NewImage(800, 600)
+	defer screen.Dispose()
+	cache := NewRenderCache()
+	defer cache.Dispose()
+
+	for frame := 0; frame < 2; frame++ {
+		log = nil
+		cache.Draw(screen, roots, 800, 600)
+		if !reflect.DeepEqual(log, []string{"root"}) {
+			t.Errorf("Frame %d: expected root to be redrawn, got %v", frame, log)
+		}
+	}
+}
+
+// TestRenderCache_TranslatesOffscreenDrawing 测试缓存块绘制到离屏图像时子树（包括滚动后的内容）平移到图像坐标系
+func TestRenderCache_TranslatesOffscreenDrawing(t *testing.T) {
+	root := NewPanel("root")
+	root.X, root.Y, root.Width, root.Height = 50, 40, 300, 200
+	root.Padding = Spacing{Top: 5, Left: 5}
+	scroll := NewScrollPanel("scroll")
+	scroll.X, scroll.Y, scroll.Width, scroll.Height = 10, 10, 200, 100
+	item := &boundsRecorder{PanelWidget: NewPanel("item")}
+	item.X, item.Y, item.Width, item.Height = 0, 60, 100, 100
+	scroll.AddChild(item)
+	root.AddChild(scroll)
+	roots := []Widget{root}
+	PerformLayout(roots, 800, 600)
+	scroll.ScrollTo(0, 20)
+
+	screen := ebiten.NewImage(800, 600)
+	defer screen.Dispose()
+	cache := NewRenderCache()
+	defer cache.Dispose()
+	cache.Draw(screen, roots, 800, 600)
+
+	// 滚动面板也是缓存块，其缓存图像从面板边界的左上角开始
+	box, _ := item.GetComputedLayout()
+	scrollBox, _ := scroll.GetComputedLayout()
+	want := box.Bounds.Sub(scrollBox.Bounds.Min)
+	if want.Min != image.Pt(0, 40) {
+		t.Fatalf("Unexpected fixture layout: item %v, scroll %v", box.Bounds, scrollBox.Bounds)
+	}
+	if item.drawn != want {
+		t.Errorf("Expected item drawn at %v in the offscreen image, got %v", want, item.drawn)
+	}
+
+	// 直接绘制时使用绝对坐标
+	DrawWidgets(screen, roots, 800, 600)
+	if item.drawn != box.Bounds {
+		t.Errorf("Expected item drawn at %v without cache, got %v", box.Bounds, item.drawn)
+	}
+}
+
+// TestRenderCache_ReleasesUnusedBlocks 测试不再绘制的缓存块把图像放回图像池，Dispose释放全部图像
+func TestRenderCache_ReleasesUnusedBlocks(t *testing.T) {
+	var log []string
+	root, _, _, g2, _ := newCacheFixture(&log)
+	roots := []Widget{root}
+	PerformLayout(roots, 800, 600)
+
+	screen := ebiten.NewImage(800, 600)
+	defer screen.Dispose()
+	cache := NewRenderCache()
+	cache.Draw(screen, roots, 800, 600)
+
+	root.RemoveChild("g2")
+	PerformLayout(roots, 800, 600)
+	cache.Draw(screen, roots, 800, 600)
+	if g2.renderCacheEntry() != nil {
+		t.Error("Expected the removed block to be detached")
+	}
+	if stats := cache.Pool().Stats(); stats.Idle != 1 {
+		t.Errorf("Expected the removed block's image back in the pool, got %+v", stats)
+	}
+
+	cache.Dispose()
+	if root.renderCacheEntry() != nil {
+		t.Error("Expected Dispose to detach all blocks")
+	}
+	if stats := cache.Pool().Stats(); stats.Idle != 0 || stats.Disposed != stats.Allocated {
+		t.Errorf("Expected Dispose to release every image, got %+v", stats)
+	}
+}
+
+// loadSampleLayout 加载查看器的示例布局并完成布局
+func loadSampleLayout(b *testing.B) []Widget {
+	roots, err := NewLoader().LoadFromFile("examples/viewer/sample_layout.json")
+	if err != nil {
+		b.Fatalf("Failed to load sample layout: %v", err)
+	}
+	PerformLayout(roots, 1280, 720)
+	return roots
+}
+
+// BenchmarkDrawWidgets_SampleLayout 每帧直接重新绘制示例布局的分配情况
+func BenchmarkDrawWidgets_SampleLayout(b *testing.B) {
+	roots := loadSampleLayout(b)
+	screen := ebiten.NewImage(1280, 720)
+	defer screen.Dispose()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DrawWidgets(screen, roots, 1280, 720)
+	}
+}
+
+// BenchmarkRenderCache_SampleLayout 静态画面使用渲染缓存时每帧的分配情况
+func BenchmarkRenderCache_SampleLayout(b *testing.B) {
+	roots := loadSampleLayout(b)
+	screen := ebiten.NewImage(1280, 720)
+	defer screen.Dispose()
+	cache := NewRenderCache()
+	defer cache.Dispose()
+	cache.Draw(screen, roots, 1280, 720)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.Draw(screen, roots, 1280, 720)
+	}
+}
+
+// TestRenderCache_ShadowGolden 测试缓存块的离屏图像包含外阴影（偏移、扩展和模糊），合成结果与直接绘制相同
+func TestRenderCache_ShadowGolden(t *testing.T) {
+	panel := NewPanel("panel")
+	panel.X, panel.Y, panel.Width, panel.Height = 30, 25, 90, 60
+	panel.BackgroundColor = RGBA{250, 250, 250, 255}
+	panel.BackgroundAlpha = 255
+	panel.BorderRadius = 8
+	panel.BoxShadow = []BoxShadow{
+		{OffsetX: 6, OffsetY: 8, Blur: 10, Spread: 2, Color: RGBA{0, 0, 0, 160}},
+		{OffsetX: -4, OffsetY: -3, Blur: 4, Color: RGBA{40, 90, 200, 200}},
+	}
+	child := NewPanel("child")
+	child.X, child.Y, child.Width, child.Height = 10, 10, 30, 20
+	child.BackgroundColor = RGBA{66, 135, 245, 255}
+	child.BackgroundAlpha = 255
+	panel.AddChild(child)
+
+	cached, direct := renderCachedRGBA([]Widget{panel}, 160, 130)
+	if !bytes.Equal(cached.Pix, direct.Pix) {
+		t.Errorf("expected the cached block to cover the whole shadow, got %v", subtreeBounds(panel, cached.Bounds()))
+	}
+	checkGolden(t, "render_cache_shadow", cached)
+}
@@ -0,0 +1,218 @@
+package ui
+
+import (
+	"image"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ScaleMode 设计分辨率缩放模式
+type ScaleMode string
+
+const (
+	ScaleModeNone    ScaleMode = "none"    // 不缩放：视口等于窗口的逻辑尺寸（锚点布局随窗口变化）
+	ScaleModeFit     ScaleMode = "fit"     // 等比缩放并完整显示设计尺寸，多余部分留黑边
+	ScaleModeFill    ScaleMode = "fill"    // 等比缩放并铺满窗口，裁剪超出部分
+	ScaleModeStretch ScaleMode = "stretch" // 非等比拉伸铺满窗口
+	ScaleModeInteger ScaleMode = "integer" // 按整数倍等比缩放（像素风格），窗口小于设计尺寸时退化为fit
+)
+
+// scaleModeAliases 缩放模式的别名
+var scaleModeAliases = map[string]ScaleMode{
+	"none":          ScaleModeNone,
+	"fit":           ScaleModeFit,
+	"letterbox":     ScaleModeFit,
+	"fill":          ScaleModeFill,
+	"crop":          ScaleModeFill,
+	"stretch":       ScaleModeStretch,
+	"integer":       ScaleModeInteger,
+	"integer-scale": ScaleModeInteger,
+	"pixel":         ScaleModeInteger,
+}
+
+// ParseScaleMode 解析缩放模式（支持letterbox、crop、integer-scale等别名）
+func ParseScaleMode(s string) (ScaleMode, bool) {
+	mode, ok := scaleModeAliases[strings.ToLower(strings.TrimSpace(s))]
+	return mode, ok
+}
+
+// ScreenScaler 设计分辨率到屏幕的缩放
+// 控件始终在设计坐标（视口）中布局和绘制，先绘制到离屏画布，再按缩放模式和设备缩放系数绘制到屏幕
+// 输入坐标通过ToDesign从屏幕坐标转换回设计坐标，命中测试与绘制保持一致
+type ScreenScaler struct {
+	Mode         ScaleMode
+	DesignWidth  int
+	DesignHeight int
+	DeviceScale  float64 // 设备缩放系数（HiDPI屏幕大于1），<=0时视为1
+
+	screenWidth, screenHeight     int // 帧缓冲尺寸（物理像素）
+	viewportWidth, viewportHeight int // 控件布局使用的视口尺寸
+	scaleX, scaleY                float64
+	offsetX, offsetY              float64
+
+	canvas *ebiten.Image
+}
+
+// NewScreenScaler 创建缩放器
+func NewScreenScaler(mode ScaleMode, designWidth, designHeight int) *ScreenScaler {
+	s := &ScreenScaler{
+		Mode:         mode,
+		DesignWidth:  designWidth,
+		DesignHeight: designHeight,
+		DeviceScale:  1,
+	}
+	s.Layout(designWidth, designHeight)
+	return s
+}
+
+// Layout 根据窗口的逻辑尺寸计算帧缓冲尺寸和缩放变换
+// 返回值即ebiten Game.Layout应返回的屏幕尺寸（按设备缩放系数换算的物理像素）
+func (s *ScreenScaler) Layout(outsideWidth, outsideHeight int) (int, int) {
+	dsf := s.DeviceScale
+	if dsf <= 0 {
+		dsf = 1
+	}
+	s.screenWidth = max(int(math.Ceil(float64(outsideWidth)*dsf)), 1)
+	s.screenHeight = max(int(math.Ceil(float64(outsideHeight)*dsf)), 1)
+
+	if s.Mode == ScaleModeNone || s.Mode == "" || s.DesignWidth <= 0 || s.DesignHeight <= 0 {
+		// 不缩放时视口为窗口的逻辑尺寸，只按设备缩放系数放大
+		s.viewportWidth, s.viewportHeight = max(outsideWidth, 1), max(outsideHeight, 1)
+		s.scaleX, s.scaleY = dsf, dsf
+		s.offsetX, s.offsetY = 0, 0
+		return s.screenWidth, s.screenHeight
+	}
+
+	s.viewportWidth, s.viewportHeight = s.DesignWidth, s.DesignHeight
+	fx := float64(s.screenWidth) / float64(s.DesignWidth)
+	fy := float64(s.screenHeight) / float64(s.DesignHeight)
+	switch s.Mode {
+	case ScaleModeFill:
+		s.scaleX = math.Max(fx, fy)
+		s.scaleY = s.scaleX
+	case ScaleModeStretch:
+		s.scaleX, s.scaleY = fx, fy
+	case ScaleModeInteger:
+		s.scaleX = math.Floor(math.Min(fx, fy))
+		if s.scaleX < 1 {
+			s.scaleX = math.Min(fx, fy)
+		}
+		s.scaleY = s.scaleX
+	default: // fit
+		s.scaleX = math.Min(fx, fy)
+		s.scaleY = s.scaleX
+	}
+
+	// 居中显示（fill模式下偏移为负，超出部分被裁剪）
+	s.offsetX = math.Round((float64(s.screenWidth) - float64(s.DesignWidth)*s.scaleX) / 2)
+	s.offsetY = math.Round((float64(s.screenHeight) - float64(s.DesignHeight)*s.scaleY) / 2)
+	return s.screenWidth, s.screenHeight
+}
+
+// Viewport 获取控件布局使用的视口尺寸（设计坐标）
+func (s *ScreenScaler) Viewport() (int, int) {
+	return s.viewportWidth, s.viewportHeight
+}
+
+// ScreenSize 获取帧缓冲尺寸
+func (s *ScreenScaler) ScreenSize() (int, int) {
+	return s.screenWidth, s.screenHeight
+}
+
+// Scale 获取设计坐标到屏幕坐标的缩放系数
+func (s *ScreenScaler) Scale() (float64, float64) {
+	return s.scaleX, s.scaleY
+}
+
+// DisplayRect 获取视口在屏幕上的显示区域（fit/integer模式下不含黑边）
+func (s *ScreenScaler) DisplayRect() image.Rectangle {
+	x0, y0 := s.ToScreen(0, 0)
+	x1, y1 := s.ToScreen(s.viewportWidth, s.viewportHeight)
+	return image.Rect(x0, y0, x1, y1)
+}
+
+// ToDesign 将屏幕坐标转换为设计坐标（黑边区域的坐标会落在视口之外）
+func (s *ScreenScaler) ToDesign(x, y int) (int, int) {
+	dx := math.Floor((float64(x) - s.offsetX) / s.scaleX)
+	dy := math.Floor((float64(y) - s.offsetY) / s.scaleY)
+	return int(dx), int(dy)
+}
+
+// ToScreen 将设计坐标转换为屏幕坐标
+func (s *ScreenScaler) ToScreen(x, y int) (int, int) {
+	sx := math.Round(float64(x)*s.scaleX + s.offsetX)
+	sy := math.Round(float64(y)*s.scaleY + s.offsetY)
+	return int(sx), int(sy)
+}
+
+// isIdentity 设计坐标与屏幕坐标是否一致（此时直接绘制到屏幕，不使用离屏画布）
+func (s *ScreenScaler) isIdentity() bool {
+	return s.scaleX == 1 && s.scaleY == 1 && s.offsetX == 0 && s.offsetY == 0
+}
+
+// Canvas 获取本帧绘制控件使用的画布（视口尺寸）
+// 不需要缩放时直接返回screen
+func (s *ScreenScaler) Canvas(screen *ebiten.Image) *ebiten.Image {
+	if s.isIdentity() {
+		return screen
+	}
+	if s.canvas != nil {
+		if b := s.canvas.Bounds(); b.Dx() != s.viewportWidth || b.Dy() != s.viewportHeight {
+			s.canvas.Dispose()
+			s.canvas = nil
+		}
+	}
+	if s.canvas == nil {
+		s.canvas = ebiten.NewImage(s.viewportWidth, s.viewportHeight)
+	}
+	s.canvas.Clear()
+	return s.canvas
+}
+
+// Present 将画布按缩放变换绘制到屏幕（画布就是screen时不做任何事）
+// 整数缩放使用最近邻过滤保持像素清晰，其他模式使用线性过滤
+func (s *ScreenScaler) Present(screen, canvas *ebiten.Image) {
+	if canvas == screen {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(s.scaleX, s.scaleY)
+	op.GeoM.Translate(s.offsetX, s.offsetY)
+	if s.Mode == ScaleModeInteger {
+		op.Filter = ebiten.FilterNearest
+	} else {
+		op.Filter = ebiten.FilterLinear
+	}
+	screen.DrawImage(canvas, op)
+}
+
+// ScaledInputSource 将输入源的指针和触点坐标转换为设计坐标
+type ScaledInputSource struct {
+	InputSource
+	Scaler *ScreenScaler
+}
+
+// NewScaledInputSource 创建按缩放器转换坐标的输入源
+func NewScaledInputSource(source InputSource, scaler *ScreenScaler) *ScaledInputSource {
+	return &ScaledInputSource{InputSource: source, Scaler: scaler}
+}
+
+func (s *ScaledInputSource) CursorPosition() (int, int) {
+	return s.Scaler.ToDesign(s.InputSource.CursorPosition())
+}
+
+func (s *ScaledInputSource) AppendTouchIDs(ids []ebiten.TouchID) []ebiten.TouchID {
+	if t, ok := s.InputSource.(TouchInputSource); ok {
+		return t.AppendTouchIDs(ids)
+	}
+	return ids
+}
+
+func (s *ScaledInputSource) TouchPosition(id ebiten.TouchID) (int, int) {
+	if t, ok := s.InputSource.(TouchInputSource); ok {
+		return s.Scaler.ToDesign(t.TouchPosition(id))
+	}
+	return 0, 0
+}
@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"image"
+	"testing"
+)
+
+// TestScreenScaler_Modes 测试各缩放模式的缩放系数、视口和显示区域（设计尺寸320x180）
+func TestScreenScaler_Modes(t *testing.T) {
+	tests := []struct {
+		mode           ScaleMode
+		outW, outH     int
+		scaleX, scaleY float64
+		viewport       image.Point
+		display        image.Rectangle
+	}{
+		{ScaleModeNone, 800, 600, 1, 1, image.Pt(800, 600), image.Rect(0, 0, 800, 600)},
+		{ScaleModeFit, 800, 600, 2.5, 2.5, image.Pt(320, 180), image.Rect(0, 75, 800, 525)},
+		{ScaleModeFill, 800, 600, 600.0 / 180.0, 600.0 / 180.0, image.Pt(320, 180), image.Rect(-133, 0, 934, 600)},
+		{ScaleModeStretch, 800, 600, 2.5, 600.0 / 180.0, image.Pt(320, 180), image.Rect(0, 0, 800, 600)},
+		{ScaleModeInteger, 800, 600, 2, 2, image.Pt(320, 180), image.Rect(80, 120, 720, 480)},
+		{ScaleModeInteger, 160, 90, 0.5, 0.5, image.Pt(320, 180), image.Rect(0, 0, 160, 90)},
+	}
+	for _, tt := range tests {
+		s := NewScreenScaler(tt.mode, 320, 180)
+		if w, h := s.Layout(tt.outW, tt.outH); w != tt.outW || h != tt.outH {
+			t.Errorf("%s: expected screen %dx%d, got %dx%d", tt.mode, tt.outW, tt.outH, w, h)
+		}
+		if sx, sy := s.Scale(); sx != tt.scaleX || sy != tt.scaleY {
+			t.Errorf("%s %dx%d: expected scale (%v, %v), got (%v, %v)", tt.mode, tt.outW, tt.outH, tt.scaleX, tt.scaleY, sx, sy)
+		}
+		if w, h := s.Viewport(); image.Pt(w, h) != tt.viewport {
+			t.Errorf("%s: expected viewport %v, got (%d, %d)", tt.mode, tt.viewport, w, h)
+		}
+		if got := s.DisplayRect(); got != tt.display {
+			t.Errorf("%s %dx%d: expected display rect %v, got %v", tt.mode, tt.outW, tt.outH, tt.display, got)
+		}
+	}
+}
+
+// TestScreenScaler_ToDesign 测试屏幕坐标转换回设计坐标（包括黑边区域）
+func TestScreenScaler_ToDesign(t *testing.T) {
+	s := NewScreenScaler(ScaleModeFit, 320, 180)
+	s.Layout(800, 600)
+
+	tests := []struct {
+		screen, design image.Point
+	}{
+		{image.Pt(0, 75), image.Pt(0, 0)},
+		{image.Pt(400, 300), image.Pt(160, 90)},
+		{image.Pt(799, 524), image.Pt(319, 179)},
+		{image.Pt(400, 10), image.Pt(160, -26)}, // 上方黑边
+	}
+	for _, tt := range tests {
+		if x, y := s.ToDesign(tt.screen.X, tt.screen.Y); image.Pt(x, y) != tt.design {
+			t.Errorf("%v: expected %v, got (%d, %d)", tt.screen, tt.design, x, y)
+		}
+	}
+
+	// 设计坐标转换到屏幕后再转换回来保持不变
+	for _, p := range []image.Point{{0, 0}, {17, 33}, {319, 179}} {
+		sx, sy := s.ToScreen(p.X, p.Y)
+		if x, y := s.ToDesign(sx, sy); image.Pt(x, y) != p {
+			t.Errorf("round trip %v: got (%d, %d)", p, x, y)
+		}
+	}
+}
+
+// TestScreenScaler_DeviceScale 测试设备缩放系数：帧缓冲按物理像素计算，视口不变
+func TestScreenScaler_DeviceScale(t *testing.T) {
+	s := NewScreenScaler(ScaleModeNone, 320, 180)
+	s.DeviceScale = 2
+	if w, h := s.Layout(640, 360); w != 1280 || h != 720 {
+		t.Errorf("expected screen 1280x720, got %dx%d", w, h)
+	}
+	if w, h := s.Viewport(); w != 640 || h != 360 {
+		t.Errorf("expected viewport 640x360, got %dx%d", w, h)
+	}
+	if x, y := s.ToDesign(200, 100); x != 100 || y != 50 {
+		t.Errorf("expected design point (100, 50), got (%d, %d)", x, y)
+	}
+
+	// 整数缩放按物理像素取整
+	s.Mode = ScaleModeInteger
+	s.Layout(640, 360)
+	if sx, _ := s.Scale(); sx != 4 {
+		t.Errorf("expected integer scale 4, got %v", sx)
+	}
+}
+
+// TestScaledInputSource 测试输入源把指针和触点坐标转换为设计坐标，分发器按设计坐标命中控件
+func TestScaledInputSource(t *testing.T) {
+	scaler := NewScreenScaler(ScaleModeFit, 320, 180)
+	scaler.Layout(800, 600)
+
+	source := newMockInputSource()
+	scaled := NewScaledInputSource(source, scaler)
+
+	source.x, source.y = 400, 300
+	if x, y := scaled.CursorPosition(); x != 160 || y != 90 {
+		t.Errorf("expected cursor (160, 90), got (%d, %d)", x, y)
+	}
+
+	button := NewButton("button")
+	button.X, button.Y = 100, 50
+	button.Width, button.Height = 40, 20
+
+	d := NewInputDispatcher(scaled, NewEventQueue())
+	d.SetRoots([]Widget{button})
+	d.SetViewport(scaler.Viewport())
+
+	// 屏幕(300,225) -> 设计(120,60)，落在按钮内
+	source.x, source.y = 300, 225
+	d.Update()
+	if d.GetHovered() != button {
+		t.Errorf("expected button to be hovered, got %v", d.GetHovered())
+	}
+
+	// 屏幕(300,100)位于黑边区域，不命中任何控件
+	source.x, source.y = 300, 100
+	d.Update()
+	if d.GetHovered() != nil {
+		t.Errorf("expected nothing hovered in letterbox, got %v", d.GetHovered())
+	}
+}
+
+// TestParseScaleMode 测试缩放模式解析
+func TestParseScaleMode(t *testing.T) {
+	tests := []struct {
+		value string
+		want  ScaleMode
+		ok    bool
+	}{
+		{"fit", ScaleModeFit, true},
+		{"Letterbox", ScaleModeFit, true},
+		{"crop", ScaleModeFill, true},
+		{" integer-scale ", ScaleModeInteger, true},
+		{"stretch", ScaleModeStretch, true},
+		{"zoom", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseScaleMode(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%q: expected (%v, %v), got (%v, %v)", tt.value, tt.want, tt.ok, got, ok)
+		}
+	}
+}
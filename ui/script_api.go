@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"image"
 	"log"
 
 	"github.com/dop251/goja"
@@ -118,6 +119,22 @@ func (cb *CommandBuilder) setProperty(property string, value interface{}) {
 	})
 }
 
+// focus 请求焦点命令
+func (cb *CommandBuilder) focus() {
+	cb.queue.Push(WidgetCommand{
+		Type:     CommandFocus,
+		WidgetID: cb.widgetID,
+	})
+}
+
+// blur 放弃焦点命令
+func (cb *CommandBuilder) blur() {
+	cb.queue.Push(WidgetCommand{
+		Type:     CommandBlur,
+		WidgetID: cb.widgetID,
+	})
+}
+
 // createWidgetAPI 为控件创建API对象（self参数）
 func (se *ScriptEngine) createWidgetAPI(widgetID string, widgetType WidgetType) *goja.Object {
 	api := se.vm.NewObject()
@@ -140,6 +157,43 @@ func (se *ScriptEngine) createWidgetAPI(widgetID string, widgetType WidgetType)
 		cb.setColor(uint8(r), uint8(g), uint8(b), uint8(a))
 	})
 
+	api.Set("focus", func() {
+		cb.focus()
+	})
+
+	api.Set("blur", func() {
+		cb.blur()
+	})
+
+	api.Set("setTooltip", func(text string) {
+		cb.setProperty("tooltip", text)
+	})
+
+	api.Set("setTooltipTemplate", func(templateID string) {
+		cb.setProperty("tooltipTemplate", templateID)
+	})
+
+	api.Set("setCursor", func(cursor string) {
+		cb.setProperty("cursor", cursor)
+	})
+
+	// 布局结果（绝对坐标，控件不可见或尚未布局时返回null）
+	api.Set("getBounds", func() goja.Value {
+		box, ok := se.layoutBox(widgetID)
+		if !ok {
+			return goja.Null()
+		}
+		return se.rectangleObject(box.Bounds)
+	})
+
+	api.Set("getContentBounds", func() goja.Value {
+		box, ok := se.layoutBox(widgetID)
+		if !ok {
+			return goja.Null()
+		}
+		return se.rectangleObject(box.Content)
+	})
+
 	// 控件特定方法
 	switch widgetType {
 	case TypeButton:
@@ -158,11 +212,31 @@ func (se *ScriptEngine) createWidgetAPI(widgetID string, widgetType WidgetType)
 		api.Set("setValue", func(value string) {
 			cb.setText(value)
 		})
+
+	case TypeScrollPanel:
+		// UIScrollPanel特定方法
+		api.Set("scrollTo", func(x, y float64) {
+			cb.setProperty("scrollTo", []float64{x, y})
+		})
+
+		api.Set("scrollBy", func(dx, dy float64) {
+			cb.setProperty("scrollBy", []float64{dx, dy})
+		})
 	}
 
 	return api
 }
 
+// rectangleObject 将矩形转换为脚本中的Rectangle对象
+func (se *ScriptEngine) rectangleObject(rect image.Rectangle) *goja.Object {
+	obj := se.vm.NewObject()
+	obj.Set("x", rect.Min.X)
+	obj.Set("y", rect.Min.Y)
+	obj.Set("width", rect.Dx())
+	obj.Set("height", rect.Dy())
+	return obj
+}
+
 // createEventObject 将WidgetEvent转换为JavaScript对象
 func (se *ScriptEngine) createEventObject(event WidgetEvent, selfAPI *goja.Object) *goja.Object {
 	eventObj := se.vm.NewObject()
@@ -173,14 +247,76 @@ func (se *ScriptEngine) createEventObject(event WidgetEvent, selfAPI *goja.Objec
 	eventObj.Set("timestamp", event.Timestamp.UnixMilli())
 
 	// 鼠标事件属性
-	if event.Type == EventClick || event.Type == EventMouseDown || event.Type == EventMouseUp || event.Type == EventHover {
+	switch event.Type {
+	case EventClick, EventMouseDown, EventMouseUp, EventHover, EventMouseEnter, EventMouseLeave, EventWheel,
+		EventTouchStart, EventTouchMove, EventTouchEnd,
+		EventTap, EventDoubleTap, EventLongPress, EventSwipe, EventPinch,
+		EventDragStart, EventDragOver, EventDrop, EventDragEnd, EventScroll:
 		eventObj.Set("x", event.X)
 		eventObj.Set("y", event.Y)
 		eventObj.Set("button", event.Button)
 	}
 
+	// 滚轮事件属性
+	if event.Type == EventWheel {
+		if dx, ok := event.Data["deltaX"].(float64); ok {
+			eventObj.Set("deltaX", dx)
+		}
+		if dy, ok := event.Data["deltaY"].(float64); ok {
+			eventObj.Set("deltaY", dy)
+		}
+	}
+
+	// 触摸和手势事件属性
+	for _, name := range []string{"touchId", "direction", "velocityX", "velocityY", "scale", "centerX", "centerY"} {
+		if value, ok := event.Data[name]; ok {
+			eventObj.Set(name, value)
+		}
+	}
+	if event.Type == EventSwipe {
+		eventObj.Set("deltaX", event.Data["deltaX"])
+		eventObj.Set("deltaY", event.Data["deltaY"])
+	}
+
+	// 滚动事件属性
+	if event.Type == EventScroll {
+		for _, name := range []string{"scrollX", "scrollY", "maxScrollX", "maxScrollY", "source"} {
+			if value, ok := event.Data[name]; ok {
+				eventObj.Set(name, value)
+			}
+		}
+	}
+
+	// 选择改变事件属性（下拉选择框）
+	if event.Type == EventChange {
+		for _, name := range []string{"selectedIndex", "value"} {
+			if value, ok := event.Data[name]; ok {
+				eventObj.Set(name, value)
+			}
+		}
+	}
+
+	// 交互状态变化事件属性
+	if event.Type == EventStateChange {
+		for _, name := range []string{"state", "active", "states"} {
+			if value, ok := event.Data[name]; ok {
+				eventObj.Set(name, value)
+			}
+		}
+	}
+
+	// 拖放事件属性
+	if payload, ok := event.Data["payload"].(map[string]interface{}); ok {
+		eventObj.Set("payload", payload)
+		for _, name := range []string{"index", "targetIndex", "dropped"} {
+			if value, ok := event.Data[name]; ok {
+				eventObj.Set(name, value)
+			}
+		}
+	}
+
 	// 键盘事件属性
-	if event.Type == EventKeyPress {
+	if event.Type == EventKeyPress || event.Type == EventKeyDown || event.Type == EventKeyUp {
 		if key, ok := event.Data["key"].(string); ok {
 			eventObj.Set("key", key)
 		}
@@ -189,6 +325,13 @@ func (se *ScriptEngine) createEventObject(event WidgetEvent, selfAPI *goja.Objec
 		}
 	}
 
+	// 修饰键
+	for _, name := range []string{"shiftKey", "ctrlKey", "altKey", "metaKey"} {
+		if pressed, ok := event.Data[name].(bool); ok {
+			eventObj.Set(name, pressed)
+		}
+	}
+
 	// 附加数据
 	if event.Data != nil {
 		dataObj := se.vm.NewObject()
@@ -23,6 +23,8 @@ type ScriptEngine struct {
 	vmMu         sync.Mutex         // 保护VM访问（goja不是线程安全的）
 	uiTree       *UITree            // UI树结构
 	uiTreeMu     sync.RWMutex       // 保护UI树访问
+	layout       *LayoutEngine      // 布局引擎（提供控件的计算边界）
+	layoutMu     sync.RWMutex       // 保护layout字段
 }
 
 // NewScriptEngine 创建脚本引擎
@@ -298,6 +300,24 @@ func (se *ScriptEngine) SetUITree(widgets []Widget) {
 	se.vm.Set("RootElement", se.createRootElement())
 }
 
+// SetLayoutEngine 设置布局引擎，脚本通过getBounds/getContentBounds读取最近一次布局的结果
+func (se *ScriptEngine) SetLayoutEngine(layout *LayoutEngine) {
+	se.layoutMu.Lock()
+	defer se.layoutMu.Unlock()
+	se.layout = layout
+}
+
+// layoutBox 获取控件最近一次布局的结果
+func (se *ScriptEngine) layoutBox(widgetID string) (LayoutBox, bool) {
+	se.layoutMu.RLock()
+	layout := se.layout
+	se.layoutMu.RUnlock()
+	if layout == nil {
+		return LayoutBox{}, false
+	}
+	return layout.BoundsByID(widgetID)
+}
+
 // GetUITree 获取UI树（用于测试）
 func (se *ScriptEngine) GetUITree() *UITree {
 	se.uiTreeMu.RLock()
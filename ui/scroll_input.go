@@ -0,0 +1,336 @@
+package ui
+
+import (
+	"image"
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	scrollInertiaDecay       = 4.0                    // 惯性滚动速度的指数衰减系数（1/秒）
+	scrollInertiaMinVelocity = 20.0                   // 惯性滚动速度低于该值（像素/秒）时停止
+	scrollFlingMaxIdle       = 100 * time.Millisecond // 松开前停顿超过该时间不产生惯性滚动
+)
+
+// touchScroll 触摸拖动滚动的状态
+type touchScroll struct {
+	tracking       bool
+	id             ebiten.TouchID
+	candidates     []*ScrollPanelWidget // 触点下的滚动面板（由内到外）
+	panel          *ScrollPanelWidget   // 越过阈值后选定的滚动面板
+	startX, startY int
+	lastX, lastY   int
+	lastTime       time.Time
+	velX, velY     float64 // 滚动速度（像素/秒，平滑后）
+}
+
+// scrollState 分发器的滚动状态
+type scrollState struct {
+	// 拖动滚动条
+	bar         *ScrollPanelWidget
+	barVertical bool
+	grab        int // 按下位置相对滑块起点的偏移
+
+	touch touchScroll
+
+	// 惯性滚动中的面板
+	flings   []*ScrollPanelWidget
+	lastStep time.Time
+
+	// 上一帧的焦点控件（焦点变化时滚动到可见）
+	focused Widget
+}
+
+// ScrollTo 滚动面板到指定位置，位置改变时推送scroll事件（供脚本命令等外部调用）
+func (d *InputDispatcher) ScrollTo(panel *ScrollPanelWidget, x, y float64) bool {
+	d.stopInertia(panel)
+	return d.scrollPanelTo(panel, x, y, "api", Modifiers{})
+}
+
+// ScrollIntoView 滚动控件所在的所有滚动面板（由内到外），使控件进入可视区域
+func (d *InputDispatcher) ScrollIntoView(widget Widget) {
+	d.scrollIntoView(widget, "api", Modifiers{})
+}
+
+// scrollPanelTo 滚动面板并在位置改变时推送scroll事件
+func (d *InputDispatcher) scrollPanelTo(panel *ScrollPanelWidget, x, y float64, source string, mods Modifiers) bool {
+	if !panel.ScrollTo(x, y) {
+		return false
+	}
+	maxX, maxY := panel.MaxScroll()
+	d.pushPointer(EventScroll, panel, d.cursorX, d.cursorY, 0, mods, map[string]interface{}{
+		"scrollX":    panel.ScrollX,
+		"scrollY":    panel.ScrollY,
+		"maxScrollX": maxX,
+		"maxScrollY": maxY,
+		"source":     source,
+	})
+	return true
+}
+
+// scrollPanelBy 按偏移量滚动面板
+func (d *InputDispatcher) scrollPanelBy(panel *ScrollPanelWidget, dx, dy float64, source string, mods Modifiers) bool {
+	return d.scrollPanelTo(panel, panel.ScrollX+dx, panel.ScrollY+dy, source, mods)
+}
+
+// scrollPanelsAt 查找指定坐标下的滚动面板（由内到外），存在模态面板时只在模态面板内查找
+func (d *InputDispatcher) scrollPanelsAt(x, y int) []*ScrollPanelWidget {
+	pt := image.Pt(x, y)
+	return d.stackOrderAt(pt).scrollPanelsAt(pt)
+}
+
+// scrollPanelsAt 查找坐标下最上层的滚动面板，然后沿父控件链收集同样包含该点的外层滚动面板
+func (s stackOrder) scrollPanelsAt(pt image.Point) []*ScrollPanelWidget {
+	var panels []*ScrollPanelWidget
+	for i := len(s) - 1; i >= 0; i-- {
+		if _, ok := s[i].widget.(*ScrollPanelWidget); !ok || s[i].scrollbar || !pt.In(s[i].clip.Intersect(s[i].bounds)) {
+			continue
+		}
+		for j := i; j >= 0; j = s[j].up {
+			if panel, ok := s[j].widget.(*ScrollPanelWidget); ok && pt.In(s[j].clip.Intersect(s[j].bounds)) {
+				panels = append(panels, panel)
+			}
+		}
+		break
+	}
+	return panels
+}
+
+// wheelScroll 滚轮滚动指针下最内层还能继续滚动的面板
+// 只能水平滚动的面板以及按住Shift时，垂直滚轮滚动水平方向
+func (d *InputDispatcher) wheelScroll(x, y int, wheelX, wheelY float64, mods Modifiers) {
+	for _, panel := range d.scrollPanelsAt(x, y) {
+		dx, dy := -wheelX*panel.WheelStep, -wheelY*panel.WheelStep
+		if dx == 0 && panel.CanScrollX() && (!panel.CanScrollY() || mods.Shift) {
+			dx, dy = dy, 0
+		}
+		d.stopInertia(panel)
+		if d.scrollPanelBy(panel, dx, dy, "wheel", mods) {
+			return
+		}
+	}
+}
+
+// beginScrollbarDrag 左键在滚动条上按下时开始拖动滑块，返回是否按在滚动条上
+// 按在轨道上时滑块先跳到按下位置（滑块中心对准指针）
+func (d *InputDispatcher) beginScrollbarDrag(target Widget, x, y int, mods Modifiers) bool {
+	panel, ok := target.(*ScrollPanelWidget)
+	if !ok || !panel.ScrollbarAt(image.Pt(x, y)) {
+		return false
+	}
+	box, _ := panel.GetComputedLayout()
+	pt := image.Pt(x, y)
+	for _, vertical := range []bool{true, false} {
+		track, thumb, ok := panel.scrollbarRects(box.Bounds, vertical)
+		if !ok || !pt.In(track) {
+			continue
+		}
+		d.scroll.bar = panel
+		d.scroll.barVertical = vertical
+		switch {
+		case pt.In(thumb) && vertical:
+			d.scroll.grab = y - thumb.Min.Y
+		case pt.In(thumb):
+			d.scroll.grab = x - thumb.Min.X
+		case vertical:
+			d.scroll.grab = thumb.Dy() / 2
+		default:
+			d.scroll.grab = thumb.Dx() / 2
+		}
+		d.stopInertia(panel)
+		d.updateScrollbarDrag(x, y, mods)
+		return true
+	}
+	return false
+}
+
+// updateScrollbarDrag 拖动滑块时按指针位置滚动
+func (d *InputDispatcher) updateScrollbarDrag(x, y int, mods Modifiers) {
+	panel := d.scroll.bar
+	if panel == nil {
+		return
+	}
+	if !d.buttonDown[0] {
+		d.scroll.bar = nil
+		return
+	}
+	box, ok := panel.GetComputedLayout()
+	if !ok {
+		return
+	}
+	track, thumb, ok := panel.scrollbarRects(box.Bounds, d.scroll.barVertical)
+	if !ok {
+		return
+	}
+	maxX, maxY := panel.MaxScroll()
+	if d.scroll.barVertical {
+		scrollY := scrollForThumb(y-track.Min.Y-d.scroll.grab, track.Dy(), thumb.Dy(), maxY)
+		d.scrollPanelTo(panel, panel.ScrollX, scrollY, "scrollbar", mods)
+	} else {
+		scrollX := scrollForThumb(x-track.Min.X-d.scroll.grab, track.Dx(), thumb.Dx(), maxX)
+		d.scrollPanelTo(panel, scrollX, panel.ScrollY, "scrollbar", mods)
+	}
+}
+
+// beginTouchScroll 第一个触点按下时记录其下的滚动面板，并停止这些面板的惯性滚动
+func (d *InputDispatcher) beginTouchScroll(id ebiten.TouchID, x, y int, now time.Time) {
+	panels := d.scrollPanelsAt(x, y)
+	for _, panel := range panels {
+		d.stopInertia(panel)
+	}
+	d.scroll.touch = touchScroll{
+		tracking:   len(panels) > 0,
+		id:         id,
+		candidates: panels,
+		startX:     x,
+		startY:     y,
+		lastX:      x,
+		lastY:      y,
+		lastTime:   now,
+	}
+}
+
+// moveTouchScroll 触点移动超过点击阈值后，按主要移动方向选定最内层可以滚动的面板，之后内容跟随手指移动
+func (d *InputDispatcher) moveTouchScroll(id ebiten.TouchID, x, y int, now time.Time, mods Modifiers) {
+	t := &d.scroll.touch
+	if !t.tracking || id != t.id {
+		return
+	}
+
+	if t.panel == nil {
+		mx, my := float64(x-t.startX), float64(y-t.startY)
+		if math.Hypot(mx, my) < d.gestures.Config().TapMaxMovement {
+			return
+		}
+		vertical := math.Abs(my) >= math.Abs(mx)
+		for _, panel := range t.candidates {
+			maxX, maxY := panel.MaxScroll()
+			if (vertical && panel.CanScrollY() && maxY > 0) || (!vertical && panel.CanScrollX() && maxX > 0) {
+				t.panel = panel
+				break
+			}
+		}
+		if t.panel == nil {
+			t.tracking = false
+			return
+		}
+		// 从越过阈值的位置开始跟随，避免内容突然跳动
+		t.lastX, t.lastY, t.lastTime = x, y, now
+		return
+	}
+
+	dx, dy := float64(t.lastX-x), float64(t.lastY-y)
+	if dt := now.Sub(t.lastTime).Seconds(); dt > 0 {
+		t.velX = 0.8*dx/dt + 0.2*t.velX
+		t.velY = 0.8*dy/dt + 0.2*t.velY
+	}
+	t.lastX, t.lastY, t.lastTime = x, y, now
+	d.scrollPanelBy(t.panel, dx, dy, "touch", mods)
+}
+
+// endTouchScroll 触点抬起时结束拖动，面板启用惯性并且松开前仍在移动时开始惯性滚动
+func (d *InputDispatcher) endTouchScroll(id ebiten.TouchID, now time.Time) {
+	t := d.scroll.touch
+	if !t.tracking || id != t.id {
+		return
+	}
+	d.scroll.touch = touchScroll{}
+
+	panel := t.panel
+	if panel == nil || !panel.Inertia || now.Sub(t.lastTime) > scrollFlingMaxIdle {
+		return
+	}
+	if math.Hypot(t.velX, t.velY) < scrollInertiaMinVelocity {
+		return
+	}
+	panel.velocityX, panel.velocityY = t.velX, t.velY
+	d.scroll.flings = append(d.scroll.flings, panel)
+}
+
+// updateScrollInertia 推进惯性滚动（速度按指数衰减，到达边界或速度过低时停止）
+func (d *InputDispatcher) updateScrollInertia(now time.Time, mods Modifiers) {
+	last := d.scroll.lastStep
+	d.scroll.lastStep = now
+	if last.IsZero() || len(d.scroll.flings) == 0 {
+		return
+	}
+	dt := math.Min(now.Sub(last).Seconds(), 0.1)
+	if dt <= 0 {
+		return
+	}
+
+	decay := math.Exp(-scrollInertiaDecay * dt)
+	active := d.scroll.flings[:0]
+	for _, panel := range d.scroll.flings {
+		moved := d.scrollPanelBy(panel, panel.velocityX*dt, panel.velocityY*dt, "inertia", mods)
+		panel.velocityX *= decay
+		panel.velocityY *= decay
+		if moved && math.Hypot(panel.velocityX, panel.velocityY) >= scrollInertiaMinVelocity {
+			active = append(active, panel)
+		} else {
+			panel.velocityX, panel.velocityY = 0, 0
+		}
+	}
+	d.scroll.flings = active
+}
+
+// stopInertia 停止面板的惯性滚动
+func (d *InputDispatcher) stopInertia(panel *ScrollPanelWidget) {
+	panel.velocityX, panel.velocityY = 0, 0
+	for i, p := range d.scroll.flings {
+		if p == panel {
+			d.scroll.flings = append(d.scroll.flings[:i], d.scroll.flings[i+1:]...)
+			return
+		}
+	}
+}
+
+// updateScrollIntoView 焦点变化时把新的焦点控件滚动到可见区域
+func (d *InputDispatcher) updateScrollIntoView(mods Modifiers) {
+	focused := d.focus.GetFocused()
+	if focused == d.scroll.focused {
+		return
+	}
+	d.scroll.focused = focused
+	if focused != nil {
+		d.scrollIntoView(focused, "focus", mods)
+	}
+}
+
+// scrollIntoView 由内到外滚动控件的祖先滚动面板
+// 内层面板滚动后会立即更新布局结果，外层面板使用更新后的边界
+func (d *InputDispatcher) scrollIntoView(widget Widget, source string, mods Modifiers) {
+	c, ok := widget.(computedLayout)
+	if !ok {
+		return
+	}
+	ancestors, _ := widgetAncestors(d.roots, widget)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		panel, ok := ancestors[i].(*ScrollPanelWidget)
+		if !ok {
+			continue
+		}
+		box, ok := c.GetComputedLayout()
+		if !ok {
+			return
+		}
+		d.stopInertia(panel)
+		x, y := panel.scrollPositionFor(box.Bounds)
+		d.scrollPanelTo(panel, x, y, source, mods)
+	}
+}
+
+// widgetAncestors 查找控件的祖先（从顶层控件到父控件）
+func widgetAncestors(widgets []Widget, target Widget) ([]Widget, bool) {
+	for _, widget := range widgets {
+		if widget == target {
+			return nil, true
+		}
+		if path, ok := widgetAncestors(widget.GetChildren(), target); ok {
+			return append([]Widget{widget}, path...), true
+		}
+	}
+	return nil, false
+}
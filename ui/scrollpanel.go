@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// scrollbarMinThumb 滚动条滑块的最小长度（像素）
+const scrollbarMinThumb = 16
+
+// ScrollPanelWidget 滚动面板（容器）
+// 子控件在可视区域（扣除内边距后的区域）中按面板的布局模式排列，超出面板边界的部分默认被裁剪，可以通过滚轮、拖动滚动条或触摸拖动滚动
+type ScrollPanelWidget struct {
+	PanelWidget
+
+	ScrollDirection string  `json:"scrollDirection"` // 滚动方向："vertical"（默认）、"horizontal"、"both"
+	ScrollX         float64 `json:"scrollX"`         // 当前水平滚动位置
+	ScrollY         float64 `json:"scrollY"`         // 当前垂直滚动位置
+	WheelStep       float64 `json:"wheelStep"`       // 滚轮每格滚动的距离（像素）
+	Inertia         bool    `json:"inertia"`         // 触摸拖动松开后继续惯性滚动
+
+	// 滚动条（宽度为0时不显示，内容不超出可视区域时也不显示）
+	ScrollbarWidth      int  `json:"scrollbarWidth"`
+	ScrollbarColor      RGBA `json:"scrollbarColor"`      // 滑块颜色
+	ScrollbarTrackColor RGBA `json:"scrollbarTrackColor"` // 轨道颜色
+
+	// 最近一次布局的可视区域（绝对坐标）和内容尺寸
+	viewport image.Rectangle
+	extent   image.Point
+
+	// 惯性滚动速度（像素/秒）
+	velocityX, velocityY float64
+}
+
+// NewScrollPanel 创建滚动面板
+func NewScrollPanel(id string) *ScrollPanelWidget {
+	panel := NewPanel(id)
+	panel.Type = TypeScrollPanel
+	panel.ClipChildren = true
+	return &ScrollPanelWidget{
+		PanelWidget:         *panel,
+		ScrollDirection:     "vertical",
+		WheelStep:           40,
+		Inertia:             true,
+		ScrollbarWidth:      8,
+		ScrollbarColor:      RGBA{R: 160, G: 160, B: 160, A: 200},
+		ScrollbarTrackColor: RGBA{R: 0, G: 0, B: 0, A: 60},
+	}
+}
+
+// CanScrollX 是否允许水平滚动
+func (s *ScrollPanelWidget) CanScrollX() bool {
+	return s.ScrollDirection == "horizontal" || s.ScrollDirection == "both"
+}
+
+// CanScrollY 是否允许垂直滚动
+func (s *ScrollPanelWidget) CanScrollY() bool {
+	return s.ScrollDirection != "horizontal"
+}
+
+// Viewport 获取最近一次布局的可视区域（绝对坐标）
+func (s *ScrollPanelWidget) Viewport() image.Rectangle {
+	return s.viewport
+}
+
+// ScrollSize 获取最近一次布局的内容尺寸（不小于可视区域）
+func (s *ScrollPanelWidget) ScrollSize() image.Point {
+	return s.extent
+}
+
+// MaxScroll 获取最大滚动位置
+func (s *ScrollPanelWidget) MaxScroll() (float64, float64) {
+	return float64(max(s.extent.X-s.viewport.Dx(), 0)), float64(max(s.extent.Y-s.viewport.Dy(), 0))
+}
+
+// scrollContent 根据可视区域计算内容尺寸，限制滚动位置，返回平移后的内容区域
+// 允许滚动的方向上内容尺寸取可视区域和子控件首选尺寸的较大值，其他方向与可视区域相同
+func (s *ScrollPanelWidget) scrollContent(viewport image.Rectangle) image.Rectangle {
+	s.viewport = viewport
+	s.extent = viewport.Size()
+	measured := s.MeasureChildren()
+	if s.CanScrollX() {
+		s.extent.X = max(s.extent.X, measured.X)
+	}
+	if s.CanScrollY() {
+		s.extent.Y = max(s.extent.Y, measured.Y)
+	}
+	s.ScrollX, s.ScrollY = s.clampScroll(s.ScrollX, s.ScrollY)
+	return s.contentRect()
+}
+
+// contentRect 按当前滚动位置平移后的内容区域
+func (s *ScrollPanelWidget) contentRect() image.Rectangle {
+	origin := s.viewport.Min.Sub(image.Pt(int(math.Round(s.ScrollX)), int(math.Round(s.ScrollY))))
+	return image.Rectangle{Min: origin, Max: origin.Add(s.extent)}
+}
+
+// clampScroll 将滚动位置限制在[0, 最大滚动位置]内
+func (s *ScrollPanelWidget) clampScroll(x, y float64) (float64, float64) {
+	maxX, maxY := s.MaxScroll()
+	return math.Max(0, math.Min(x, maxX)), math.Max(0, math.Min(y, maxY))
+}
+
+// ScrollTo 滚动到指定位置（超出范围时限制在范围内），返回滚动位置是否改变
+// 已经布局过时立即平移子控件的布局结果，绘制和命中测试在同一帧内使用新的位置
+func (s *ScrollPanelWidget) ScrollTo(x, y float64) bool {
+	x, y = s.clampScroll(x, y)
+	if x == s.ScrollX && y == s.ScrollY {
+		return false
+	}
+	s.ScrollX, s.ScrollY = x, y
+	s.MarkDirty()
+
+	if s.hasComputed {
+		s.computedLayout.Content = s.contentRect()
+		arrangeWidgets(s.Children, s.computedLayout.Content)
+	}
+	return true
+}
+
+// ScrollBy 按偏移量滚动，返回滚动位置是否改变
+func (s *ScrollPanelWidget) ScrollBy(dx, dy float64) bool {
+	return s.ScrollTo(s.ScrollX+dx, s.ScrollY+dy)
+}
+
+// ScrollRectIntoView 以最小的滚动距离使矩形（绝对坐标）进入可视区域，返回滚动位置是否改变
+// 矩形大于可视区域时对齐其左/上边
+func (s *ScrollPanelWidget) ScrollRectIntoView(rect image.Rectangle) bool {
+	return s.ScrollTo(s.scrollPositionFor(rect))
+}
+
+// scrollPositionFor 计算使矩形（绝对坐标）进入可视区域的滚动位置
+func (s *ScrollPanelWidget) scrollPositionFor(rect image.Rectangle) (float64, float64) {
+	dx := scrollIntoViewDelta(rect.Min.X, rect.Max.X, s.viewport.Min.X, s.viewport.Max.X)
+	dy := scrollIntoViewDelta(rect.Min.Y, rect.Max.Y, s.viewport.Min.Y, s.viewport.Max.Y)
+	return s.ScrollX + float64(dx), s.ScrollY + float64(dy)
+}
+
+// scrollIntoViewDelta 计算使[lo, hi]进入[viewLo, viewHi]所需的滚动距离
+func scrollIntoViewDelta(lo, hi, viewLo, viewHi int) int {
+	if lo < viewLo {
+		return lo - viewLo
+	}
+	if hi > viewHi {
+		return min(hi-viewHi, lo-viewLo)
+	}
+	return 0
+}
+
+// scrollbarRects 计算滚动条轨道和滑块的位置，内容没有超出可视区域的方向返回ok=false
+// 滚动条覆盖在面板边界的右侧/底部，两个方向都显示时为对方让出角落
+func (s *ScrollPanelWidget) scrollbarRects(bounds image.Rectangle, vertical bool) (track, thumb image.Rectangle, ok bool) {
+	width := s.ScrollbarWidth
+	if width <= 0 {
+		return track, thumb, false
+	}
+	maxX, maxY := s.MaxScroll()
+	showX := s.CanScrollX() && maxX > 0
+	showY := s.CanScrollY() && maxY > 0
+
+	if vertical {
+		if !showY {
+			return track, thumb, false
+		}
+		track = image.Rect(bounds.Max.X-width, bounds.Min.Y, bounds.Max.X, bounds.Max.Y)
+		if showX {
+			track.Max.Y -= width
+		}
+		offset, length := thumbSpan(track.Dy(), s.viewport.Dy(), s.extent.Y, s.ScrollY, maxY)
+		thumb = image.Rect(track.Min.X, track.Min.Y+offset, track.Max.X, track.Min.Y+offset+length)
+		return track, thumb, true
+	}
+
+	if !showX {
+		return track, thumb, false
+	}
+	track = image.Rect(bounds.Min.X, bounds.Max.Y-width, bounds.Max.X, bounds.Max.Y)
+	if showY {
+		track.Max.X -= width
+	}
+	offset, length := thumbSpan(track.Dx(), s.viewport.Dx(), s.extent.X, s.ScrollX, maxX)
+	thumb = image.Rect(track.Min.X+offset, track.Min.Y, track.Min.X+offset+length, track.Max.Y)
+	return track, thumb, true
+}
+
+// thumbSpan 计算滑块在轨道中的偏移和长度（长度与可视比例一致，但不小于scrollbarMinThumb）
+func thumbSpan(trackLen, viewLen, contentLen int, scroll, maxScroll float64) (int, int) {
+	if contentLen <= 0 || maxScroll <= 0 {
+		return 0, trackLen
+	}
+	length := min(max(trackLen*viewLen/contentLen, scrollbarMinThumb), trackLen)
+	offset := int(math.Round(float64(trackLen-length) * scroll / maxScroll))
+	return offset, length
+}
+
+// scrollForThumb 根据滑块在轨道中的偏移计算滚动位置
+func scrollForThumb(offset, trackLen, thumbLen int, maxScroll float64) float64 {
+	if trackLen <= thumbLen {
+		return 0
+	}
+	return float64(offset) * maxScroll / float64(trackLen-thumbLen)
+}
+
+// ScrollbarAt 判断点（绝对坐标）是否在滚动条上（需要已经布局过）
+func (s *ScrollPanelWidget) ScrollbarAt(pt image.Point) bool {
+	if !s.hasComputed {
+		return false
+	}
+	for _, vertical := range []bool{true, false} {
+		if track, _, ok := s.scrollbarRects(s.computedLayout.Bounds, vertical); ok && pt.In(track) {
+			return true
+		}
+	}
+	return false
+}
+
+// Draw 绘制滚动面板
+func (s *ScrollPanelWidget) Draw(screen *ebiten.Image, parentX, parentY, parentWidth, parentHeight int) {
+	if !s.Visible {
+		return
+	}
+
+	localX, localY := s.CalculatePosition(parentWidth, parentHeight)
+	absX := parentX + localX
+	absY := parentY + localY
+	renderWidth, renderHeight := s.CalculateSize(parentWidth, parentHeight, localX, localY)
+	bounds := image.Rect(absX, absY, absX+renderWidth, absY+renderHeight)
+
+	drawPainted(screen, s, bounds)
+
+	// 布局过程已经计算过平移后的内容区域时直接使用其结果
+	// （换算到本次绘制的坐标系：渲染缓存把子树绘制到离屏图像时传入平移后的父容器位置）
+	var content image.Rectangle
+	if s.hasComputed {
+		content = s.computedLayout.Content.Add(bounds.Min.Sub(s.computedLayout.Bounds.Min))
+	} else {
+		content = s.scrollContent(s.ContentRect(bounds))
+		s.LayoutChildren(content.Dx(), content.Dy())
+	}
+
+	// 开启裁剪时子控件绘制到裁剪为面板边界的子图像上
+	target, visible := screen, true
+	if s.ClipChildren {
+		target, visible = clipImage(screen, bounds)
+	}
+	if visible {
+		drawChildren(target, s.Children, content)
+	}
+
+	drawForeground(screen, s, bounds)
+}
+
+// PaintForeground 记录子控件之上的滚动条
+func (s *ScrollPanelWidget) PaintForeground(list *DrawList, bounds image.Rectangle) {
+	for _, vertical := range []bool{true, false} {
+		track, thumb, ok := s.scrollbarRects(bounds, vertical)
+		if !ok {
+			continue
+		}
+		list.FillRect(track, s.ScrollbarTrackColor)
+		list.FillRect(thumb, s.ScrollbarColor)
+	}
+}
@@ -0,0 +1,351 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// newScrollFixture 创建200x100的垂直滚动面板，包含10个高40的按钮（内容高400）
+func newScrollFixture() (*InputDispatcher, *mockInputSource, *EventQueue, *ScrollPanelWidget, []*ButtonWidget) {
+	panel := NewScrollPanel("scroll")
+	panel.Width, panel.Height = 200, 100
+	panel.Layout = "flex"
+	panel.Direction = "column"
+
+	buttons := make([]*ButtonWidget, 10)
+	for i := range buttons {
+		buttons[i] = NewButton(fmt.Sprintf("item%d", i))
+		buttons[i].Width, buttons[i].Height = 150, 40
+		panel.AddChild(buttons[i])
+	}
+
+	src := newMockInputSource()
+	eq := NewEventQueue()
+	d := NewInputDispatcher(src, eq)
+	d.SetRoots([]Widget{panel})
+	d.SetViewport(800, 600)
+	return d, src, eq, panel, buttons
+}
+
+// scrollEvents 提取scroll事件
+func scrollEvents(events []WidgetEvent) []WidgetEvent {
+	var out []WidgetEvent
+	for _, e := range events {
+		if e.Type == EventScroll {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// TestScrollPanel_LayoutOffsetsChildren 测试内容尺寸、滚动位置限制，以及滚动后立即平移子控件
+func TestScrollPanel_LayoutOffsetsChildren(t *testing.T) {
+	_, _, _, panel, buttons := newScrollFixture()
+	panel.ScrollY = 1000
+	PerformLayout([]Widget{panel}, 800, 600)
+
+	if got := panel.ScrollSize(); got != image.Pt(200, 400) {
+		t.Errorf("expected content size (200, 400), got %v", got)
+	}
+	if panel.ScrollY != 300 {
+		t.Errorf("expected scrollY clamped to 300, got %v", panel.ScrollY)
+	}
+	if got, want := computedBounds(t, buttons[9]), image.Rect(0, 60, 150, 100); got != want {
+		t.Errorf("expected last item bounds %v, got %v", want, got)
+	}
+
+	if !panel.ScrollTo(0, 40) {
+		t.Fatal("expected ScrollTo to change position")
+	}
+	if got, want := computedBounds(t, buttons[1]), image.Rect(0, 0, 150, 40); got != want {
+		t.Errorf("expected item1 bounds %v after scrolling, got %v", want, got)
+	}
+	if panel.ScrollTo(0, 40) {
+		t.Error("expected unchanged position to report no change")
+	}
+}
+
+// TestScrollPanel_HitTestClipsChildren 测试滚出可视区域的子控件不会被命中
+func TestScrollPanel_HitTestClipsChildren(t *testing.T) {
+	d, _, _, panel, buttons := newScrollFixture()
+	d.Update()
+
+	if got := d.HitTest(20, 120); got != nil {
+		t.Errorf("expected nothing hit below the viewport, got %s", got.GetID())
+	}
+	panel.ScrollTo(0, 100)
+	if got := d.HitTest(20, 10); got != buttons[2] {
+		t.Errorf("expected item2 after scrolling, got %v", got)
+	}
+}
+
+// TestScrollPanel_Wheel 测试滚轮滚动并推送scroll事件
+func TestScrollPanel_Wheel(t *testing.T) {
+	d, src, eq, panel, _ := newScrollFixture()
+	defer eq.Close()
+
+	src.x, src.y = 50, 50
+	d.Update()
+	drainEvents(eq)
+
+	src.wheelY = -1
+	d.Update()
+	src.wheelY = 0
+
+	if panel.ScrollY != 40 {
+		t.Fatalf("expected scrollY 40, got %v", panel.ScrollY)
+	}
+	events := scrollEvents(drainEvents(eq))
+	if len(events) != 1 {
+		t.Fatalf("expected one scroll event, got %d", len(events))
+	}
+	if e := events[0]; e.WidgetID != "scroll" || e.Data["scrollY"] != 40.0 || e.Data["maxScrollY"] != 300.0 || e.Data["source"] != "wheel" {
+		t.Errorf("unexpected scroll event %+v", e)
+	}
+
+	// 已经在顶部时向上滚动不产生事件
+	src.wheelY = 5
+	d.Update()
+	src.wheelY = 1
+	d.Update()
+	if panel.ScrollY != 0 {
+		t.Errorf("expected scrollY 0, got %v", panel.ScrollY)
+	}
+	if got := len(scrollEvents(drainEvents(eq))); got != 1 {
+		t.Errorf("expected one scroll event at the limit, got %d", got)
+	}
+}
+
+// TestScrollPanel_WheelChainsToOuter 测试内层面板到达边界后滚轮滚动外层面板
+func TestScrollPanel_WheelChainsToOuter(t *testing.T) {
+	outer := NewScrollPanel("outer")
+	outer.Width, outer.Height = 300, 200
+	inner := NewScrollPanel("inner")
+	inner.Width, inner.Height = 200, 100
+	content := newFlexChild("content", 200, 150)
+	spacer := newFlexChild("spacer", 10, 400)
+	inner.AddChild(content)
+	outer.AddChild(inner)
+	outer.AddChild(spacer)
+
+	src := newMockInputSource()
+	d := NewInputDispatcher(src, NewEventQueue())
+	d.SetRoots([]Widget{outer})
+	d.SetViewport(800, 600)
+
+	src.x, src.y = 50, 50
+	src.wheelY = -1
+	for i := 0; i < 3; i++ {
+		d.Update()
+	}
+	if inner.ScrollY != 50 || outer.ScrollY != 40 {
+		t.Errorf("expected inner 50 and outer 40, got inner %v and outer %v", inner.ScrollY, outer.ScrollY)
+	}
+}
+
+// TestScrollPanel_ScrollbarDrag 测试拖动滚动条滑块
+func TestScrollPanel_ScrollbarDrag(t *testing.T) {
+	d, src, eq, panel, _ := newScrollFixture()
+	defer eq.Close()
+	d.Update()
+
+	// 轨道高100，内容400，滑块高25，可移动75
+	track, thumb, ok := panel.scrollbarRects(computedBounds(t, panel), true)
+	if !ok || track != image.Rect(192, 0, 200, 100) || thumb != image.Rect(192, 0, 200, 25) {
+		t.Fatalf("unexpected scrollbar track %v thumb %v (ok=%v)", track, thumb, ok)
+	}
+	if got := d.HitTest(195, 10); got != panel {
+		t.Fatalf("expected scrollbar to hit the panel, got %v", got)
+	}
+
+	src.x, src.y = 195, 10
+	src.buttons[ebiten.MouseButtonLeft] = true
+	d.Update()
+	src.y = 40
+	d.Update()
+	if panel.ScrollY != 120 {
+		t.Errorf("expected scrollY 120 after dragging 30px, got %v", panel.ScrollY)
+	}
+
+	src.y = 500
+	d.Update()
+	if panel.ScrollY != 300 {
+		t.Errorf("expected scrollY clamped to 300, got %v", panel.ScrollY)
+	}
+	src.buttons[ebiten.MouseButtonLeft] = false
+	d.Update()
+
+	events := scrollEvents(drainEvents(eq))
+	if len(events) != 2 || events[0].Data["source"] != "scrollbar" {
+		t.Errorf("expected two scrollbar scroll events, got %v", events)
+	}
+
+	// 点击轨道时滑块中心跳到指针位置
+	src.y = 50
+	src.buttons[ebiten.MouseButtonLeft] = true
+	d.Update()
+	src.buttons[ebiten.MouseButtonLeft] = false
+	d.Update()
+	// 滑块偏移 50-25/2=38，对应滚动位置 38*300/75=152
+	if panel.ScrollY != 152 {
+		t.Errorf("expected track click to center the thumb (scrollY 152), got %v", panel.ScrollY)
+	}
+}
+
+// TestScrollPanel_TouchDragWithInertia 测试触摸拖动和松开后的惯性滚动
+func TestScrollPanel_TouchDragWithInertia(t *testing.T) {
+	d, src, eq, panel, _ := newScrollFixture()
+	defer eq.Close()
+	clock := time.Unix(0, 0)
+	d.now = func() time.Time { return clock }
+
+	// 在按钮右侧的空白处拖动，避免按下时移动焦点
+	id := ebiten.TouchID(1)
+	step := func(y int) {
+		clock = clock.Add(16 * time.Millisecond)
+		src.touches[id] = [2]int{170, y}
+		d.Update()
+	}
+
+	step(90)
+	step(70) // 越过阈值，选定面板
+	if panel.ScrollY != 0 {
+		t.Fatalf("expected no scroll when crossing the threshold, got %v", panel.ScrollY)
+	}
+	step(60)
+	step(50)
+	if panel.ScrollY != 20 {
+		t.Fatalf("expected content to follow the finger (scrollY 20), got %v", panel.ScrollY)
+	}
+
+	delete(src.touches, id)
+	clock = clock.Add(16 * time.Millisecond)
+	d.Update()
+	released := panel.ScrollY
+
+	for i := 0; i < 10; i++ {
+		clock = clock.Add(16 * time.Millisecond)
+		d.Update()
+	}
+	if panel.ScrollY <= released {
+		t.Errorf("expected inertia to keep scrolling after release (%v), got %v", released, panel.ScrollY)
+	}
+
+	// 惯性逐渐停止
+	for i := 0; i < 200; i++ {
+		clock = clock.Add(16 * time.Millisecond)
+		d.Update()
+	}
+	stopped := panel.ScrollY
+	clock = clock.Add(16 * time.Millisecond)
+	d.Update()
+	if panel.ScrollY != stopped {
+		t.Errorf("expected inertia to stop, still moving at %v", panel.ScrollY)
+	}
+
+	var sources = map[interface{}]bool{}
+	for _, e := range scrollEvents(drainEvents(eq)) {
+		sources[e.Data["source"]] = true
+	}
+	if !sources["touch"] || !sources["inertia"] {
+		t.Errorf("expected touch and inertia scroll events, got %v", sources)
+	}
+}
+
+// TestScrollPanel_NoInertiaWhenDisabled 测试关闭惯性后松开立即停止
+func TestScrollPanel_NoInertiaWhenDisabled(t *testing.T) {
+	d, src, _, panel, _ := newScrollFixture()
+	panel.Inertia = false
+	clock := time.Unix(0, 0)
+	d.now = func() time.Time { return clock }
+
+	id := ebiten.TouchID(1)
+	for _, y := range []int{90, 70, 50, 30} {
+		clock = clock.Add(16 * time.Millisecond)
+		src.touches[id] = [2]int{170, y}
+		d.Update()
+	}
+	delete(src.touches, id)
+	d.Update()
+	released := panel.ScrollY
+	for i := 0; i < 10; i++ {
+		clock = clock.Add(16 * time.Millisecond)
+		d.Update()
+	}
+	if panel.ScrollY != released {
+		t.Errorf("expected no inertia, scrollY moved from %v to %v", released, panel.ScrollY)
+	}
+}
+
+// TestScrollPanel_ScrollFocusedIntoView 测试焦点移动到可视区域外的控件时自动滚动
+func TestScrollPanel_ScrollFocusedIntoView(t *testing.T) {
+	d, _, eq, panel, buttons := newScrollFixture()
+	defer eq.Close()
+	d.Update()
+
+	d.FocusManager().Focus(buttons[4])
+	d.Update()
+	// item4位于160-200，可视区域高100：滚动到100使其底边对齐
+	if panel.ScrollY != 100 {
+		t.Errorf("expected scrollY 100, got %v", panel.ScrollY)
+	}
+	if got, want := computedBounds(t, buttons[4]), image.Rect(0, 60, 150, 100); got != want {
+		t.Errorf("expected focused item bounds %v, got %v", want, got)
+	}
+
+	d.FocusManager().Focus(buttons[0])
+	d.Update()
+	if panel.ScrollY != 0 {
+		t.Errorf("expected scrollY 0 after focusing the first item, got %v", panel.ScrollY)
+	}
+
+	events := scrollEvents(drainEvents(eq))
+	if len(events) != 2 || events[0].Data["source"] != "focus" {
+		t.Errorf("expected two focus scroll events, got %v", events)
+	}
+}
+
+// TestScrollPanel_HorizontalWheel 测试只能水平滚动的面板使用垂直滚轮滚动
+func TestScrollPanel_HorizontalWheel(t *testing.T) {
+	panel := NewScrollPanel("scroll")
+	panel.Width, panel.Height = 100, 50
+	panel.ScrollDirection = "horizontal"
+	panel.WheelStep = 10
+	panel.AddChild(newFlexChild("wide", 300, 200))
+
+	src := newMockInputSource()
+	d := NewInputDispatcher(src, NewEventQueue())
+	d.SetRoots([]Widget{panel})
+	d.SetViewport(800, 600)
+
+	src.x, src.y = 10, 10
+	src.wheelY = -2
+	d.Update()
+	if panel.ScrollX != 20 || panel.ScrollY != 0 {
+		t.Errorf("expected scroll (20, 0), got (%v, %v)", panel.ScrollX, panel.ScrollY)
+	}
+	if got := panel.ScrollSize(); got != image.Pt(300, 50) {
+		t.Errorf("expected content size (300, 50), got %v", got)
+	}
+}
+
+// TestScrollIntoViewDelta 测试使区间进入可视范围的最小滚动距离
+func TestScrollIntoViewDelta(t *testing.T) {
+	tests := []struct {
+		lo, hi, want int
+	}{
+		{20, 40, 0},     // 已经可见
+		{-30, -10, -30}, // 在上方
+		{90, 130, 30},   // 在下方
+		{50, 250, 50},   // 大于可视区域时对齐上边
+	}
+	for _, tt := range tests {
+		if got := scrollIntoViewDelta(tt.lo, tt.hi, 0, 100); got != tt.want {
+			t.Errorf("[%d, %d]: expected %d, got %d", tt.lo, tt.hi, tt.want, got)
+		}
+	}
+}
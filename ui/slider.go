@@ -2,11 +2,9 @@ package ui
 
 import (
 	"image"
-	"image/color"
 	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text"
 	"golang.org/x/image/font/basicfont"
 )
 
@@ -53,8 +51,8 @@ type SliderWidget struct {
 	// 状态
 	ShowValue  bool `json:"showValue"`  // 是否显示数值
 	Enabled    bool `json:"enabled"`    // 是否启用
-	IsHovering bool `json:"isHovering"` // 是否悬停
-	IsDragging bool `json:"isDragging"` // 是否拖拽中
+	IsHovering bool `json:"isHovering"` // 是否悬停（随BaseWidget.State同步）
+	IsDragging bool `json:"isDragging"` // 是否拖拽中（随BaseWidget.State同步）
 }
 
 // NewSlider 创建滑动条
@@ -108,22 +106,29 @@ func (s *SliderWidget) Draw(screen *ebiten.Image, parentX, parentY, parentWidth,
 
 	// 计算响应式尺寸
 	width, height := s.CalculateSize(parentWidth, parentHeight, localX, localY)
+	drawPainted(screen, s, image.Rect(x, y, x+width, y+height))
+}
+
+// Paint 记录轨道、滑块和数值文本（在内容区域内绘制）
+func (s *SliderWidget) Paint(list *DrawList, bounds image.Rectangle) {
+	content := s.ContentRect(bounds)
+	x, y, width, height := content.Min.X, content.Min.Y, content.Dx(), content.Dy()
 
 	if s.Orientation == SliderOrientationHorizontal {
-		s.drawHorizontal(screen, x, y, width, height)
+		s.paintHorizontal(list, x, y, width, height)
 	} else {
-		s.drawVertical(screen, x, y, width, height)
+		s.paintVertical(list, x, y, width, height)
 	}
 }
 
-// drawHorizontal 绘制水平滑动条
-func (s *SliderWidget) drawHorizontal(screen *ebiten.Image, x, y, width, height int) {
+// paintHorizontal 记录水平滑动条
+func (s *SliderWidget) paintHorizontal(list *DrawList, x, y, width, height int) {
 	// 计算轨道位置（垂直居中）
 	trackY := y + (height-s.TrackHeight)/2
 	trackWidth := width - s.ThumbSize // 留出滑块空间
 
 	// 绘制轨道背景
-	s.drawTrackBackground(screen, x+s.ThumbSize/2, trackY, trackWidth, s.TrackHeight)
+	s.paintTrackBackground(list, x+s.ThumbSize/2, trackY, trackWidth, s.TrackHeight)
 
 	// 计算滑块位置
 	ratio := (s.Value - s.MinValue) / (s.MaxValue - s.MinValue)
@@ -135,26 +140,26 @@ func (s *SliderWidget) drawHorizontal(screen *ebiten.Image, x, y, width, height
 
 	// 绘制已滑过部分
 	fillWidth := int(float64(trackWidth) * ratio)
-	s.drawTrackFill(screen, x+s.ThumbSize/2, trackY, fillWidth, s.TrackHeight)
+	s.paintTrackFill(list, x+s.ThumbSize/2, trackY, fillWidth, s.TrackHeight)
 
 	// 绘制滑块
 	thumbY := y + height/2
-	s.drawThumb(screen, thumbX, thumbY, s.ThumbSize)
+	s.paintThumb(list, thumbX, thumbY, s.ThumbSize)
 
 	// 显示数值
 	if s.ShowValue {
-		s.drawValueText(screen, x+width+5, y+height/2)
+		s.paintValueText(list, x+width+5, y+height/2)
 	}
 }
 
-// drawVertical 绘制垂直滑动条
-func (s *SliderWidget) drawVertical(screen *ebiten.Image, x, y, width, height int) {
+// paintVertical 记录垂直滑动条
+func (s *SliderWidget) paintVertical(list *DrawList, x, y, width, height int) {
 	// 计算轨道位置（水平居中）
 	trackX := x + (width-s.TrackHeight)/2
 	trackHeight := height - s.ThumbSize // 留出滑块空间
 
 	// 绘制轨道背景
-	s.drawTrackBackgroundVertical(screen, trackX, y+s.ThumbSize/2, s.TrackHeight, trackHeight)
+	s.paintTrackBackgroundVertical(list, trackX, y+s.ThumbSize/2, s.TrackHeight, trackHeight)
 
 	// 计算滑块位置（从底部开始）
 	ratio := (s.Value - s.MinValue) / (s.MaxValue - s.MinValue)
@@ -166,21 +171,21 @@ func (s *SliderWidget) drawVertical(screen *ebiten.Image, x, y, width, height in
 
 	// 绘制已滑过部分（从滑块到底部）
 	fillHeight := y + height - s.ThumbSize/2 - thumbY
-	s.drawTrackFillVertical(screen, trackX, thumbY, s.TrackHeight, fillHeight)
+	s.paintTrackFillVertical(list, trackX, thumbY, s.TrackHeight, fillHeight)
 
 	// 绘制滑块
 	thumbX := x + width/2
-	s.drawThumb(screen, thumbX, thumbY, s.ThumbSize)
+	s.paintThumb(list, thumbX, thumbY, s.ThumbSize)
 
 	// 显示数值
 	if s.ShowValue {
-		s.drawValueText(screen, x+width+5, y+height/2)
+		s.paintValueText(list, x+width+5, y+height/2)
 	}
 }
 
-// drawTrackBackground 绘制轨道背景
-func (s *SliderWidget) drawTrackBackground(screen *ebiten.Image, x, y, width, height int) {
-	trackBg := color.RGBA{
+// paintTrackBackground 记录轨道背景
+func (s *SliderWidget) paintTrackBackground(list *DrawList, x, y, width, height int) {
+	trackBg := RGBA{
 		R: s.TrackBgColor.R,
 		G: s.TrackBgColor.G,
 		B: s.TrackBgColor.B,
@@ -191,31 +196,26 @@ func (s *SliderWidget) drawTrackBackground(screen *ebiten.Image, x, y, width, he
 		trackBg.A = 128
 	}
 
-	bounds := image.Rect(x, y, x+width, y+height)
-	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
-		for px := bounds.Min.X; px < bounds.Max.X; px++ {
-			screen.Set(px, py, trackBg)
-		}
-	}
+	list.FillRect(image.Rect(x, y, x+width, y+height), trackBg)
 
 	// 绘制边框
 	if s.BorderWidth > 0 {
-		s.drawRectBorder(screen, x, y, width, height)
+		s.paintRectBorder(list, x, y, width, height)
 	}
 }
 
-// drawTrackBackgroundVertical 绘制垂直轨道背景
-func (s *SliderWidget) drawTrackBackgroundVertical(screen *ebiten.Image, x, y, width, height int) {
-	s.drawTrackBackground(screen, x, y, width, height)
+// paintTrackBackgroundVertical 记录垂直轨道背景
+func (s *SliderWidget) paintTrackBackgroundVertical(list *DrawList, x, y, width, height int) {
+	s.paintTrackBackground(list, x, y, width, height)
 }
 
-// drawTrackFill 绘制已滑过部分
-func (s *SliderWidget) drawTrackFill(screen *ebiten.Image, x, y, width, height int) {
+// paintTrackFill 记录已滑过部分
+func (s *SliderWidget) paintTrackFill(list *DrawList, x, y, width, height int) {
 	if width <= 0 {
 		return
 	}
 
-	fillColor := color.RGBA{
+	fillColor := RGBA{
 		R: s.TrackFillColor.R,
 		G: s.TrackFillColor.G,
 		B: s.TrackFillColor.B,
@@ -226,34 +226,29 @@ func (s *SliderWidget) drawTrackFill(screen *ebiten.Image, x, y, width, height i
 		fillColor.A = 128
 	}
 
-	bounds := image.Rect(x, y, x+width, y+height)
-	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
-		for px := bounds.Min.X; px < bounds.Max.X; px++ {
-			screen.Set(px, py, fillColor)
-		}
-	}
+	list.FillRect(image.Rect(x, y, x+width, y+height), fillColor)
 }
 
-// drawTrackFillVertical 绘制垂直已滑过部分
-func (s *SliderWidget) drawTrackFillVertical(screen *ebiten.Image, x, y, width, height int) {
+// paintTrackFillVertical 记录垂直已滑过部分
+func (s *SliderWidget) paintTrackFillVertical(list *DrawList, x, y, width, height int) {
 	if height <= 0 {
 		return
 	}
-	s.drawTrackFill(screen, x, y, width, height)
+	s.paintTrackFill(list, x, y, width, height)
 }
 
-// drawThumb 绘制滑块
-func (s *SliderWidget) drawThumb(screen *ebiten.Image, centerX, centerY, size int) {
-	thumbColor := color.RGBA{
+// paintThumb 记录滑块
+func (s *SliderWidget) paintThumb(list *DrawList, centerX, centerY, size int) {
+	thumbColor := RGBA{
 		R: s.ThumbColor.R,
 		G: s.ThumbColor.G,
 		B: s.ThumbColor.B,
 		A: s.ThumbColorAlpha,
 	}
 
-	// 如果悬停或拖拽，使用悬停颜色
-	if s.IsHovering || s.IsDragging {
-		thumbColor = color.RGBA{
+	// 如果悬停或按下（拖拽），使用悬停颜色
+	if s.Hovering() || s.Dragging() {
+		thumbColor = RGBA{
 			R: s.ThumbHoverColor.R,
 			G: s.ThumbHoverColor.G,
 			B: s.ThumbHoverColor.B,
@@ -267,66 +262,36 @@ func (s *SliderWidget) drawThumb(screen *ebiten.Image, centerX, centerY, size in
 
 	// 绘制圆形滑块
 	radius := size / 2
-	for dy := -radius; dy <= radius; dy++ {
-		for dx := -radius; dx <= radius; dx++ {
-			if dx*dx+dy*dy <= radius*radius {
-				screen.Set(centerX+dx, centerY+dy, thumbColor)
-			}
-		}
-	}
+	list.FillCircle(centerX, centerY, radius, thumbColor)
 
 	// 绘制滑块边框
 	if s.BorderWidth > 0 {
-		borderColor := color.RGBA{
+		borderColor := RGBA{
 			R: s.BorderColor.R,
 			G: s.BorderColor.G,
 			B: s.BorderColor.B,
 			A: s.BorderColorAlpha,
 		}
-		for dy := -radius; dy <= radius; dy++ {
-			for dx := -radius; dx <= radius; dx++ {
-				dist := dx*dx + dy*dy
-				if dist <= radius*radius && dist >= (radius-s.BorderWidth)*(radius-s.BorderWidth) {
-					screen.Set(centerX+dx, centerY+dy, borderColor)
-				}
-			}
-		}
+		list.StrokeCircle(centerX, centerY, radius, s.BorderWidth, borderColor)
 	}
 }
 
-// drawRectBorder 绘制矩形边框
-func (s *SliderWidget) drawRectBorder(screen *ebiten.Image, x, y, width, height int) {
-	borderColor := color.RGBA{
+// paintRectBorder 记录矩形边框
+func (s *SliderWidget) paintRectBorder(list *DrawList, x, y, width, height int) {
+	borderColor := RGBA{
 		R: s.BorderColor.R,
 		G: s.BorderColor.G,
 		B: s.BorderColor.B,
 		A: s.BorderColorAlpha,
 	}
 
-	for i := 0; i < s.BorderWidth; i++ {
-		// 上边
-		for px := x; px < x+width; px++ {
-			screen.Set(px, y+i, borderColor)
-		}
-		// 下边
-		for px := x; px < x+width; px++ {
-			screen.Set(px, y+height-1-i, borderColor)
-		}
-		// 左边
-		for py := y; py < y+height; py++ {
-			screen.Set(x+i, py, borderColor)
-		}
-		// 右边
-		for py := y; py < y+height; py++ {
-			screen.Set(x+width-1-i, py, borderColor)
-		}
-	}
+	list.StrokeRect(image.Rect(x, y, x+width, y+height), s.BorderWidth, borderColor)
 }
 
-// drawValueText 绘制数值文本
-func (s *SliderWidget) drawValueText(screen *ebiten.Image, x, y int) {
+// paintValueText 记录数值文本
+func (s *SliderWidget) paintValueText(list *DrawList, x, y int) {
 	face := basicfont.Face7x13
-	textColor := color.RGBA{R: 80, G: 80, B: 80, A: 255}
+	textColor := RGBA{R: 80, G: 80, B: 80, A: 255}
 
 	if !s.Enabled {
 		textColor.A = 128
@@ -340,7 +305,7 @@ func (s *SliderWidget) drawValueText(screen *ebiten.Image, x, y int) {
 		valueStr = formatFloat(s.Value)
 	}
 
-	text.Draw(screen, valueStr, face, x, y+4, textColor)
+	list.DrawText(valueStr, face, x, y+4, textColor)
 }
 
 // formatInt 格式化整数
@@ -377,6 +342,23 @@ func (s *SliderWidget) SetValue(value float64) {
 	}
 
 	s.Value = value
+	s.MarkDirty()
+}
+
+// Hovering 指针是否位于滑动条上
+func (s *SliderWidget) Hovering() bool {
+	return s.state.Has(WidgetStateHover)
+}
+
+// Dragging 滑块是否正在拖动（左键在滑动条上按下且尚未抬起）
+func (s *SliderWidget) Dragging() bool {
+	return s.state.Has(WidgetStatePressed)
+}
+
+// syncLegacyState 按交互状态同步IsHovering和IsDragging
+func (s *SliderWidget) syncLegacyState() {
+	s.IsHovering = s.Hovering()
+	s.IsDragging = s.Dragging()
 }
 
 // Update 更新滑动条状态
@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"image"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// overlayWidget 可以提升到覆盖层的控件
+// 所有嵌入BaseWidget的控件都实现了该接口
+type overlayWidget interface {
+	IsOverlay() bool
+}
+
+// isOverlay 判断控件是否提升到覆盖层（脱离父容器的裁剪和层叠顺序，绘制在所有普通控件之上）
+func isOverlay(widget Widget) bool {
+	o, ok := widget.(overlayWidget)
+	return ok && o.IsOverlay()
+}
+
+// sortedByZ 按z-index升序返回控件（z相同时保持文档顺序）
+// 已经有序时（常见情况）直接返回原切片以免每帧分配，否则返回排序后的副本；调用者不能修改返回的切片
+func sortedByZ(widgets []Widget) []Widget {
+	ordered := true
+	for i := 1; i < len(widgets) && ordered; i++ {
+		ordered = widgets[i-1].GetZIndex() <= widgets[i].GetZIndex()
+	}
+	if ordered {
+		return widgets
+	}
+
+	sorted := make([]Widget, len(widgets))
+	copy(sorted, widgets)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GetZIndex() < sorted[j].GetZIndex()
+	})
+	return sorted
+}
+
+// stackEntry 层叠顺序中的一项
+type stackEntry struct {
+	widget    Widget
+	parent    image.Rectangle // 父容器的内容区域（绝对坐标）
+	bounds    image.Rectangle // 控件的绝对边界
+	clip      image.Rectangle // 祖先裁剪后的可见区域
+	up        int             // 父控件在层叠顺序中的下标（根控件和覆盖层控件为-1）
+	overlay   bool            // 提升到覆盖层的控件
+	scrollbar bool            // 滚动面板的滚动条（位于面板的子控件之上）
+}
+
+// stackOrder 按绘制顺序展开的可见控件树
+// 兄弟控件按z-index升序（相同时按文档顺序）排列，父控件在其后代之前；
+// 覆盖层控件连同其子树移到所有普通控件之后，按z-index升序排列，并且不受祖先裁剪
+// 绘制和所有指针查找（命中测试、放置目标、提示、滚动面板、模态面板）都使用该顺序
+type stackOrder []stackEntry
+
+// buildStackOrder 根据控件树计算层叠顺序（使用布局过程缓存的边界）
+func buildStackOrder(roots []Widget, parent, viewport image.Rectangle) stackOrder {
+	var order stackOrder
+	var overlays []stackEntry
+	order.appendWidgets(roots, parent, viewport, -1, &overlays)
+
+	// 覆盖层控件内部嵌套的覆盖层控件继续追加到队列末尾
+	for i := 0; i < len(overlays); i++ {
+		sort.SliceStable(overlays[i:], func(a, b int) bool {
+			return overlays[i+a].widget.GetZIndex() < overlays[i+b].widget.GetZIndex()
+		})
+		o := overlays[i]
+		order.appendWidget(o.widget, o.parent, viewport, -1, true, &overlays)
+	}
+	return order
+}
+
+// appendWidgets 按z顺序追加兄弟控件及其子树，覆盖层控件收集到overlays中
+func (s *stackOrder) appendWidgets(widgets []Widget, parent, clip image.Rectangle, up int, overlays *[]stackEntry) {
+	for _, widget := range sortedByZ(widgets) {
+		if !widget.IsVisible() {
+			continue
+		}
+		if isOverlay(widget) {
+			*overlays = append(*overlays, stackEntry{widget: widget, parent: parent})
+			continue
+		}
+		s.appendWidget(widget, parent, clip, up, false, overlays)
+	}
+}
+
+// appendWidget 追加控件及其子树
+func (s *stackOrder) appendWidget(widget Widget, parent, clip image.Rectangle, up int, overlay bool, overlays *[]stackEntry) {
+	bounds := widgetBounds(widget, parent)
+	index := len(*s)
+	*s = append(*s, stackEntry{widget: widget, parent: parent, bounds: bounds, clip: clip, up: up, overlay: overlay})
+
+	childClip := clip
+	if clipsChildren(widget) {
+		childClip = clip.Intersect(bounds)
+	}
+	s.appendWidgets(widget.GetChildren(), widgetContentBounds(widget, bounds), childClip, index, overlays)
+
+	if _, ok := widget.(*ScrollPanelWidget); ok {
+		*s = append(*s, stackEntry{widget: widget, parent: parent, bounds: bounds, clip: clip, up: index, scrollbar: true})
+	}
+}
+
+// topmost 查找坐标下满足条件的最上层控件（遵循祖先裁剪，不包括滚动条）
+func (s stackOrder) topmost(pt image.Point, accept func(e stackEntry) bool) Widget {
+	for i := len(s) - 1; i >= 0; i-- {
+		e := s[i]
+		if e.scrollbar || !pt.In(e.clip) || !pt.In(e.bounds) {
+			continue
+		}
+		if accept(e) {
+			return e.widget
+		}
+	}
+	return nil
+}
+
+// DrawWidgets 按层叠顺序绘制控件树
+// 先按z-index（相同时按文档顺序）绘制根控件及其子树，再绘制提升到覆盖层的控件
+func DrawWidgets(screen *ebiten.Image, roots []Widget, viewportWidth, viewportHeight int) {
+	viewport := image.Rect(0, 0, viewportWidth, viewportHeight)
+	drawChildren(screen, roots, viewport)
+
+	for _, e := range buildStackOrder(roots, viewport, viewport) {
+		if e.overlay {
+			e.widget.Draw(screen, e.parent.Min.X, e.parent.Min.Y, e.parent.Dx(), e.parent.Dy())
+		}
+	}
+}
+
+// drawChildren 在内容区域中按层叠顺序绘制子控件（覆盖层控件由DrawWidgets在最后绘制）
+// 渲染缓存绘制过程中，作为缓存块的子控件合成其缓存图像
+func drawChildren(screen *ebiten.Image, children []Widget, content image.Rectangle) {
+	for _, child := range sortedByZ(children) {
+		if isOverlay(child) {
+			continue
+		}
+		if e := activeCacheEntry(child); e != nil {
+			e.draw(screen, content)
+			continue
+		}
+		child.Draw(screen, content.Min.X, content.Min.Y, content.Dx(), content.Dy())
+	}
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// drawRecorder 记录绘制顺序的测试控件（不创建图像）
+type drawRecorder struct {
+	*PanelWidget
+	log *[]string
+}
+
+func (r *drawRecorder) Draw(screen *ebiten.Image, parentX, parentY, parentWidth, parentHeight int) {
+	*r.log = append(*r.log, r.ID)
+	r.BaseWidget.DrawChildren(screen, parentX, parentY, parentWidth, parentHeight)
+}
+
+// newDrawRecorder 创建指定z-index的记录控件
+func newDrawRecorder(id string, z int, log *[]string) *drawRecorder {
+	r := &drawRecorder{PanelWidget: NewPanel(id), log: log}
+	r.ZIndex = z
+	return r
+}
+
+// stackIDs 返回层叠顺序中各控件的ID（不包括滚动条）
+func stackIDs(order stackOrder) []string {
+	var ids []string
+	for _, e := range order {
+		if !e.scrollbar {
+			ids = append(ids, e.widget.GetID())
+		}
+	}
+	return ids
+}
+
+// TestStackOrder_DrawMatchesHitTestOrder 测试各层级的兄弟控件按z-index再按文档顺序绘制，覆盖层控件最后绘制，且与命中测试使用的顺序一致
+func TestStackOrder_DrawMatchesHitTestOrder(t *testing.T) {
+	var log []string
+	a := newDrawRecorder("a", 0, &log)
+	a1 := newDrawRecorder("a1", 2, &log)
+	a2 := newDrawRecorder("a2", 0, &log)
+	a3 := newDrawRecorder("a3", 0, &log)
+	a3.Overlay = true
+	a4 := newDrawRecorder("a4", 0, &log)
+	a.AddChild(a1)
+	a.AddChild(a2)
+	a.AddChild(a3)
+	a.AddChild(a4)
+	b := newDrawRecorder("b", -1, &log)
+	c := newDrawRecorder("c", 1, &log)
+	c1 := newDrawRecorder("c1", 5, &log)
+	c1.Overlay = true
+	c.AddChild(c1)
+	roots := []Widget{a, b, c}
+
+	PerformLayout(roots, 800, 600)
+	DrawWidgets(nil, roots, 800, 600)
+
+	want := []string{"b", "a", "a2", "a4", "a1", "c", "a3", "c1"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("Expected draw order %v, got %v", want, log)
+	}
+	viewport := image.Rect(0, 0, 800, 600)
+	if got := stackIDs(buildStackOrder(roots, viewport, viewport)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected stack order %v, got %v", want, got)
+	}
+}
+
+// TestHitTest_ChildZIndex 测试容器内重叠的兄弟控件按z-index命中，而不是按文档顺序
+func TestHitTest_ChildZIndex(t *testing.T) {
+	panel := NewPanel("panel")
+	low := NewButton("low")
+	high := NewButton("high")
+	high.ZIndex = 1
+	panel.AddChild(high)
+	panel.AddChild(low)
+	roots := []Widget{panel}
+
+	if got := hitID(HitTest(roots, 800, 600, 10, 10)); got != "high" {
+		t.Errorf("Expected higher z-index sibling, got %q", got)
+	}
+
+	// z相同时后添加的控件在上层
+	high.ZIndex = 0
+	if got := hitID(HitTest(roots, 800, 600, 10, 10)); got != "low" {
+		t.Errorf("Expected later sibling with equal z-index, got %q", got)
+	}
+}
+
+// TestHitTest_OverlayEscapesClipAndStacking 测试覆盖层控件不受父容器裁剪，并位于后绘制的根控件之上
+func TestHitTest_OverlayEscapesClipAndStacking(t *testing.T) {
+	list := NewPanel("list")
+	list.Width, list.Height = 100, 100
+	list.ClipChildren = true
+	popup := NewButton("popup")
+	popup.Y = 80
+	popup.Width, popup.Height = 100, 100
+	list.AddChild(popup)
+
+	cover := NewButton("cover")
+	cover.Y = 100
+	cover.Width, cover.Height = 200, 200
+	cover.ZIndex = 10
+	roots := []Widget{list, cover}
+
+	if got := hitID(HitTest(roots, 800, 600, 50, 150)); got != "cover" {
+		t.Errorf("Expected clipped popup to be hidden under cover, got %q", got)
+	}
+
+	popup.Overlay = true
+	if got := hitID(HitTest(roots, 800, 600, 50, 150)); got != "popup" {
+		t.Errorf("Expected overlay popup above cover, got %q", got)
+	}
+	// 仍按父容器定位
+	if got, want := computedBounds(t, popup), image.Rect(0, 80, 100, 180); got != want {
+		t.Errorf("Expected overlay bounds %v, got %v", want, got)
+	}
+}
+
+// TestFindModal_TopmostInStackOrder 测试模态面板按层叠顺序查找，z-index更高的模态面板优先
+func TestFindModal_TopmostInStackOrder(t *testing.T) {
+	first := NewPanel("first")
+	first.Modal = true
+	first.ZIndex = 2
+	second := NewPanel("second")
+	second.Modal = true
+	roots := []Widget{first, second}
+	PerformLayout(roots, 800, 600)
+
+	modal, _, ok := findModal(roots, image.Rect(0, 0, 800, 600))
+	if !ok || modal != first {
+		t.Errorf("Expected modal with higher z-index, got %v", modal)
+	}
+}
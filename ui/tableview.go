@@ -3,11 +3,8 @@ package ui
 import (
 	"fmt"
 	"image"
-	"image/color"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text"
-	"github.com/hajimehoshi/ebiten/v2/vector"
 	"golang.org/x/image/font/basicfont"
 )
 
@@ -63,13 +60,14 @@ type TableViewWidget struct {
 func NewTableView(id string) *TableViewWidget {
 	return &TableViewWidget{
 		BaseWidget: BaseWidget{
-			ID:          id,
-			Type:        TypeTableView,
-			Visible:     true,
-			Interactive: true,
-			Width:       400,
-			Height:      300,
-			Opacity:     100,
+			ID:           id,
+			Type:         TypeTableView,
+			Visible:      true,
+			Interactive:  true,
+			ClipChildren: true,
+			Width:        400,
+			Height:       300,
+			Opacity:      100,
 		},
 		RowHeight:       30,
 		HeaderHeight:    35,
@@ -106,64 +104,57 @@ func (t *TableViewWidget) Draw(screen *ebiten.Image, parentX, parentY, parentWid
 	absX := parentX + localX
 	absY := parentY + localY
 
-	// 创建子图像作为裁剪区域
-	subImg := screen.SubImage(image.Rect(absX, absY, absX+renderWidth, absY+renderHeight)).(*ebiten.Image)
+	drawPainted(screen, t, image.Rect(absX, absY, absX+renderWidth, absY+renderHeight))
+}
+
+// Paint 记录背景、表头、数据行（或占位符）和边框，全部裁剪到表格边界内
+func (t *TableViewWidget) Paint(list *DrawList, bounds image.Rectangle) {
+	list.PushClip(bounds)
 
 	// 绘制背景
-	t.drawBackground(subImg, 0, 0, renderWidth, renderHeight)
+	list.FillRect(bounds, RGBA{R: t.BackgroundColor.R, G: t.BackgroundColor.G, B: t.BackgroundColor.B, A: t.BackgroundAlpha})
 
-	// 绘制表头和数据
+	// 绘制表头和数据（在内容区域内）
+	content := t.ContentRect(bounds)
 	if len(t.Columns) > 0 {
-		contentY := 0
+		contentY := content.Min.Y
 		if t.ShowHeader {
 			// 绘制表头
-			t.drawHeader(subImg, 0, 0, renderWidth, t.HeaderHeight)
-			contentY = t.HeaderHeight
+			t.paintHeader(list, content.Min.X, contentY, content.Dx(), t.HeaderHeight)
+			contentY += t.HeaderHeight
 		}
 
 		// 绘制数据行
-		contentHeight := renderHeight - contentY
+		contentHeight := content.Max.Y - contentY
 		if len(t.Items) > 0 {
-			t.drawRows(subImg, 0, contentY, renderWidth, contentHeight)
+			t.paintRows(list, content.Min.X, contentY, content.Dx(), contentHeight)
 		} else {
 			// 绘制空数据提示
-			t.drawEmptyHint(subImg, 0, contentY, renderWidth, contentHeight)
+			t.paintEmptyHint(list, content.Min.X, contentY, content.Dx(), contentHeight)
 		}
 	} else {
 		// 没有列定义，显示占位符
-		t.drawPlaceholder(subImg, 0, 0, renderWidth, renderHeight)
+		t.paintPlaceholder(list, content.Min.X, content.Min.Y, content.Dx(), content.Dy())
 	}
 
 	// 绘制边框
-	t.drawBorder(subImg, 0, 0, renderWidth, renderHeight)
-}
-
-// drawBackground 绘制背景
-func (t *TableViewWidget) drawBackground(screen *ebiten.Image, x, y, width, height int) {
-	bgColor := RGBA{R: t.BackgroundColor.R, G: t.BackgroundColor.G, B: t.BackgroundColor.B, A: t.BackgroundAlpha}
-	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(height), bgColor.ToColor(), false)
-}
-
-// drawBorder 绘制边框
-func (t *TableViewWidget) drawBorder(screen *ebiten.Image, x, y, width, height int) {
-	if t.BorderWidth <= 0 {
-		return
+	if t.BorderWidth > 0 {
+		list.StrokeRect(bounds, t.BorderWidth, RGBA{R: t.BorderColor.R, G: t.BorderColor.G, B: t.BorderColor.B, A: t.BorderAlpha})
 	}
 
-	borderColor := RGBA{R: t.BorderColor.R, G: t.BorderColor.G, B: t.BorderColor.B, A: t.BorderAlpha}
-	bw := float32(t.BorderWidth)
+	list.PopClip()
+}
 
-	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), bw, borderColor.ToColor(), false)
-	vector.DrawFilledRect(screen, float32(x), float32(y+height)-bw, float32(width), bw, borderColor.ToColor(), false)
-	vector.DrawFilledRect(screen, float32(x), float32(y), bw, float32(height), borderColor.ToColor(), false)
-	vector.DrawFilledRect(screen, float32(x+width)-bw, float32(y), bw, float32(height), borderColor.ToColor(), false)
+// gridLineColor 网格线颜色
+func (t *TableViewWidget) gridLineColor() RGBA {
+	return RGBA{R: t.GridLineColor.R, G: t.GridLineColor.G, B: t.GridLineColor.B, A: t.GridLineAlpha}
 }
 
-// drawHeader 绘制表头
-func (t *TableViewWidget) drawHeader(screen *ebiten.Image, x, y, width, height int) {
+// paintHeader 记录表头
+func (t *TableViewWidget) paintHeader(list *DrawList, x, y, width, height int) {
 	// 表头背景
 	headerBg := RGBA{R: t.HeaderBgColor.R, G: t.HeaderBgColor.G, B: t.HeaderBgColor.B, A: t.HeaderBgAlpha}
-	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(height), headerBg.ToColor(), false)
+	list.FillRect(image.Rect(x, y, x+width, y+height), headerBg)
 
 	// 绘制列头
 	currentX := x + 2
@@ -180,7 +171,7 @@ func (t *TableViewWidget) drawHeader(screen *ebiten.Image, x, y, width, height i
 		}
 
 		// 绘制列头文本
-		text.Draw(screen, headerText, basicfont.Face7x13, currentX+5, y+height/2+5, color.RGBA{50, 50, 50, 255})
+		list.DrawText(headerText, basicfont.Face7x13, currentX+5, y+height/2+5, RGBA{50, 50, 50, 255})
 
 		// 排序指示器
 		if t.SortColumn == col.Key {
@@ -188,13 +179,12 @@ func (t *TableViewWidget) drawHeader(screen *ebiten.Image, x, y, width, height i
 			if t.SortDirection == "desc" {
 				sortSymbol = "▼"
 			}
-			text.Draw(screen, sortSymbol, basicfont.Face7x13, currentX+colWidth-15, y+height/2+5, color.RGBA{100, 100, 100, 255})
+			list.DrawText(sortSymbol, basicfont.Face7x13, currentX+colWidth-15, y+height/2+5, RGBA{100, 100, 100, 255})
 		}
 
 		// 列分隔线
 		if t.ShowGridLines && i < len(t.Columns)-1 {
-			gridColor := RGBA{R: t.GridLineColor.R, G: t.GridLineColor.G, B: t.GridLineColor.B, A: t.GridLineAlpha}
-			vector.DrawFilledRect(screen, float32(currentX+colWidth), float32(y), 1, float32(height), gridColor.ToColor(), false)
+			list.FillRect(image.Rect(currentX+colWidth, y, currentX+colWidth+1, y+height), t.gridLineColor())
 		}
 
 		currentX += colWidth
@@ -202,13 +192,12 @@ func (t *TableViewWidget) drawHeader(screen *ebiten.Image, x, y, width, height i
 
 	// 表头底部分隔线
 	if t.ShowGridLines {
-		gridColor := RGBA{R: t.GridLineColor.R, G: t.GridLineColor.G, B: t.GridLineColor.B, A: t.GridLineAlpha}
-		vector.DrawFilledRect(screen, float32(x), float32(y+height), float32(width), 1, gridColor.ToColor(), false)
+		list.FillRect(image.Rect(x, y+height, x+width, y+height+1), t.gridLineColor())
 	}
 }
 
-// drawRows 绘制数据行
-func (t *TableViewWidget) drawRows(screen *ebiten.Image, x, y, width, height int) {
+// paintRows 记录数据行
+func (t *TableViewWidget) paintRows(list *DrawList, x, y, width, height int) {
 	rowHeight := t.RowHeight
 	if rowHeight <= 0 {
 		rowHeight = 30
@@ -238,7 +227,7 @@ func (t *TableViewWidget) drawRows(screen *ebiten.Image, x, y, width, height int
 
 		// 行背景（交替颜色）
 		if t.AlternateRowBg && i%2 == 1 {
-			vector.DrawFilledRect(screen, float32(x), float32(rowY), float32(width), float32(rowHeight), color.RGBA{248, 248, 248, 255}, false)
+			list.FillRect(image.Rect(x, rowY, x+width, rowY+rowHeight), RGBA{248, 248, 248, 255})
 		}
 
 		// 绘制单元格
@@ -256,12 +245,11 @@ func (t *TableViewWidget) drawRows(screen *ebiten.Image, x, y, width, height int
 			}
 
 			// 绘制单元格文本
-			text.Draw(screen, cellValue, basicfont.Face7x13, currentX+5, rowY+rowHeight/2+5, color.RGBA{50, 50, 50, 255})
+			list.DrawText(cellValue, basicfont.Face7x13, currentX+5, rowY+rowHeight/2+5, RGBA{50, 50, 50, 255})
 
 			// 列分隔线
 			if t.ShowGridLines && j < len(t.Columns)-1 {
-				gridColor := RGBA{R: t.GridLineColor.R, G: t.GridLineColor.G, B: t.GridLineColor.B, A: t.GridLineAlpha}
-				vector.DrawFilledRect(screen, float32(currentX+colWidth), float32(rowY), 1, float32(rowHeight), gridColor.ToColor(), false)
+				list.FillRect(image.Rect(currentX+colWidth, rowY, currentX+colWidth+1, rowY+rowHeight), t.gridLineColor())
 			}
 
 			currentX += colWidth
@@ -269,24 +257,22 @@ func (t *TableViewWidget) drawRows(screen *ebiten.Image, x, y, width, height int
 
 		// 行底部分隔线
 		if t.ShowGridLines {
-			gridColor := RGBA{R: t.GridLineColor.R, G: t.GridLineColor.G, B: t.GridLineColor.B, A: t.GridLineAlpha}
-			vector.DrawFilledRect(screen, float32(x), float32(rowY+rowHeight), float32(width), 1, gridColor.ToColor(), false)
+			list.FillRect(image.Rect(x, rowY+rowHeight, x+width, rowY+rowHeight+1), t.gridLineColor())
 		}
 	}
 }
 
-// drawEmptyHint 绘制空数据提示
-func (t *TableViewWidget) drawEmptyHint(screen *ebiten.Image, x, y, width, height int) {
+// paintEmptyHint 记录空数据提示
+func (t *TableViewWidget) paintEmptyHint(list *DrawList, x, y, width, height int) {
 	hintText := "无数据"
-	text.Draw(screen, hintText, basicfont.Face7x13, x+width/2-20, y+height/2, color.RGBA{150, 150, 150, 255})
+	list.DrawText(hintText, basicfont.Face7x13, x+width/2-20, y+height/2, RGBA{150, 150, 150, 255})
 }
 
-// drawPlaceholder 绘制占位符（无列定义时）
-func (t *TableViewWidget) drawPlaceholder(screen *ebiten.Image, x, y, width, height int) {
+// paintPlaceholder 记录占位符（无列定义时）
+func (t *TableViewWidget) paintPlaceholder(list *DrawList, x, y, width, height int) {
 	// 绘制示例表头
 	headerHeight := 35
-	headerBg := color.RGBA{240, 240, 240, 255}
-	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(headerHeight), headerBg, false)
+	list.FillRect(image.Rect(x, y, x+width, y+headerHeight), RGBA{240, 240, 240, 255})
 
 	// 示例列头
 	colCount := 3
@@ -294,16 +280,16 @@ func (t *TableViewWidget) drawPlaceholder(screen *ebiten.Image, x, y, width, hei
 	for i := 0; i < colCount; i++ {
 		colX := x + i*colWidth
 		headerText := fmt.Sprintf("列 %d", i+1)
-		text.Draw(screen, headerText, basicfont.Face7x13, colX+10, y+headerHeight/2+5, color.RGBA{50, 50, 50, 255})
+		list.DrawText(headerText, basicfont.Face7x13, colX+10, y+headerHeight/2+5, RGBA{50, 50, 50, 255})
 
 		// 列分隔线
 		if i < colCount-1 {
-			vector.DrawFilledRect(screen, float32(colX+colWidth), float32(y), 1, float32(headerHeight), color.RGBA{200, 200, 200, 255}, false)
+			list.FillRect(image.Rect(colX+colWidth, y, colX+colWidth+1, y+headerHeight), RGBA{200, 200, 200, 255})
 		}
 	}
 
 	// 表头底部线
-	vector.DrawFilledRect(screen, float32(x), float32(y+headerHeight), float32(width), 1, color.RGBA{200, 200, 200, 255}, false)
+	list.FillRect(image.Rect(x, y+headerHeight, x+width, y+headerHeight+1), RGBA{200, 200, 200, 255})
 
 	// 绘制示例数据行
 	rowHeight := 30
@@ -313,23 +299,23 @@ func (t *TableViewWidget) drawPlaceholder(screen *ebiten.Image, x, y, width, hei
 
 		// 交替背景
 		if i%2 == 1 {
-			vector.DrawFilledRect(screen, float32(x), float32(currentRowY), float32(width), float32(rowHeight), color.RGBA{248, 248, 248, 255}, false)
+			list.FillRect(image.Rect(x, currentRowY, x+width, currentRowY+rowHeight), RGBA{248, 248, 248, 255})
 		}
 
 		// 示例单元格
 		for j := 0; j < colCount; j++ {
 			colX := x + j*colWidth
 			cellText := fmt.Sprintf("数据 %d-%d", i+1, j+1)
-			text.Draw(screen, cellText, basicfont.Face7x13, colX+10, currentRowY+rowHeight/2+5, color.RGBA{80, 80, 80, 255})
+			list.DrawText(cellText, basicfont.Face7x13, colX+10, currentRowY+rowHeight/2+5, RGBA{80, 80, 80, 255})
 
 			// 列分隔线
 			if j < colCount-1 {
-				vector.DrawFilledRect(screen, float32(colX+colWidth), float32(currentRowY), 1, float32(rowHeight), color.RGBA{220, 220, 220, 255}, false)
+				list.FillRect(image.Rect(colX+colWidth, currentRowY, colX+colWidth+1, currentRowY+rowHeight), RGBA{220, 220, 220, 255})
 			}
 		}
 
 		// 行底部线
-		vector.DrawFilledRect(screen, float32(x), float32(currentRowY+rowHeight), float32(width), 1, color.RGBA{220, 220, 220, 255}, false)
+		list.FillRect(image.Rect(x, currentRowY+rowHeight, x+width, currentRowY+rowHeight+1), RGBA{220, 220, 220, 255})
 	}
 }
 
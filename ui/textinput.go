@@ -1,7 +1,7 @@
 package ui
 
 import (
-	"image/color"
+	"image"
 	"strings"
 	"time"
 
@@ -50,7 +50,7 @@ type TextInputWidget struct {
 	FontSize        int    `json:"fontSize"`
 	MaxLength       int    `json:"maxLength"`
 
-	// 状态
+	// 状态（焦点状态记录在BaseWidget.State中，CurrentState和Focused随其同步）
 	CurrentState TextInputState
 	Enabled      bool
 	Focused      bool
@@ -109,7 +109,7 @@ func (t *TextInputWidget) GetStateBackground() (RGBA, *ebiten.Image) {
 		return bgColor, t.backgroundImageDisabled
 	}
 
-	if t.CurrentState == TextInputStateEditing {
+	if t.state.Has(WidgetStateFocused) {
 		bgColor := RGBA{
 			R: t.BackgroundColorEditing.R,
 			G: t.BackgroundColorEditing.G,
@@ -134,20 +134,10 @@ func (t *TextInputWidget) Update() error {
 		return t.BaseWidget.Update()
 	}
 
-	// 处理焦点
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		x, y := ebiten.CursorPosition()
-		if t.OnHover(x, y) {
-			t.Focused = true
-			t.CurrentState = TextInputStateEditing
-		} else {
-			t.Focused = false
-			t.CurrentState = TextInputStateNormal
-		}
-	}
+	text, cursorPos, cursorVisible := t.Text, t.CursorPos, t.CursorVisible
 
-	// 处理输入
-	if t.Focused {
+	// 处理输入（焦点由FocusManager统一管理）
+	if t.HasFocus() {
 		// 光标闪烁
 		if time.Since(t.cursorTimer) > 500*time.Millisecond {
 			t.CursorVisible = !t.CursorVisible
@@ -197,6 +187,11 @@ func (t *TextInputWidget) Update() error {
 		}
 	}
 
+	// 文本、光标位置或光标闪烁状态改变时标记重绘
+	if t.Text != text || t.CursorPos != cursorPos || t.CursorVisible != cursorVisible {
+		t.MarkDirty()
+	}
+
 	return t.BaseWidget.Update()
 }
 
@@ -214,53 +209,39 @@ func (t *TextInputWidget) Draw(screen *ebiten.Image, parentX, parentY, parentWid
 	// 计算响应式尺寸（支持边界锚定）
 	renderWidth, renderHeight := t.CalculateSize(parentWidth, parentHeight, localX, localY)
 
-	// 创建输入框图像
-	inputImage := ebiten.NewImage(renderWidth, renderHeight)
+	drawPainted(screen, t, image.Rect(absX, absY, absX+renderWidth, absY+renderHeight))
+
+	// 绘制子控件
+	t.BaseWidget.DrawChildren(screen, absX, absY, renderWidth, renderHeight)
+}
+
+// Paint 记录输入框的阴影、背景、边框、文本和光标（内容裁剪到输入框边界，透明度整体应用）
+func (t *TextInputWidget) Paint(list *DrawList, bounds image.Rectangle) {
+	t.paintOuterShadows(list, bounds, t.Opacity)
+	pushGroup(list, bounds, t.Opacity)
 
 	// 绘制背景
 	bgColor, bgImage := t.GetStateBackground()
-	if bgColor.A > 0 {
-		inputImage.Fill(bgColor.ToColor())
-	}
-
-	if bgImage != nil {
-		op := &ebiten.DrawImageOptions{}
-		srcW, srcH := bgImage.Size()
-		scaleX := float64(renderWidth) / float64(srcW)
-		scaleY := float64(renderHeight) / float64(srcH)
-		op.GeoM.Scale(scaleX, scaleY)
-		inputImage.DrawImage(bgImage, op)
-	}
+	t.paintFill(list, bounds, bgColor)
+	paintBackgroundImage(list, bgImage, t.BackgroundSliceFor(bgImage), bounds)
+	t.paintInnerShadows(list, bounds)
 
 	// 绘制边框
-	if t.BorderWidth > 0 {
-		// TODO: 实现边框绘制
-	}
+	t.paintBorder(list, bounds)
 
 	// 绘制文本
-	t.drawText(inputImage)
-
-	// 将输入框绘制到屏幕
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(absX), float64(absY))
+	t.paintText(list, bounds)
 
-	if t.Opacity < 100 {
-		op.ColorScale.ScaleAlpha(float32(t.Opacity) / 100.0)
-	}
-
-	screen.DrawImage(inputImage, op)
-
-	// 绘制子控件
-	t.BaseWidget.DrawChildren(screen, absX, absY, renderWidth, renderHeight)
+	popGroup(list, t.Opacity)
 }
 
-// drawText 绘制文本和光标
-func (t *TextInputWidget) drawText(dst *ebiten.Image) {
+// paintText 记录文本和光标
+func (t *TextInputWidget) paintText(list *DrawList, bounds image.Rectangle) {
 	if t.Font == nil {
 		t.Font = basicfont.Face7x13
 	}
 
-	textColor := color.RGBA{
+	textColor := RGBA{
 		R: t.TextColor.R,
 		G: t.TextColor.G,
 		B: t.TextColor.B,
@@ -268,42 +249,74 @@ func (t *TextInputWidget) drawText(dst *ebiten.Image) {
 	}
 
 	displayText := t.Text
-	if displayText == "" && !t.Focused {
+	if displayText == "" && !t.HasFocus() {
 		// 显示占位符
 		displayText = t.PlaceholderText
 		textColor.A = 128 // 半透明
 	}
 
-	// 绘制文本
-	textX := t.Padding.Left + 5
-	textY := t.Height/2 + 7
-	text.Draw(dst, displayText, t.Font, textX, textY, textColor)
+	// 绘制文本（在内容区域内垂直居中）
+	content := t.ContentRect(bounds)
+	centerY := content.Min.Y + content.Dy()/2
+	textX := content.Min.X + 5
+	textY := centerY + 7
+	list.DrawText(displayText, t.Font, textX, textY, textColor)
 
 	// 绘制光标
-	if t.Focused && t.CursorVisible {
+	if t.HasFocus() && t.CursorVisible {
 		cursorText := string([]rune(t.Text)[:t.CursorPos])
 		cursorBounds := text.BoundString(t.Font, cursorText)
 		cursorX := textX + cursorBounds.Dx()
-		cursorY1 := t.Height/2 - 8
-		cursorY2 := t.Height/2 + 8
+		cursorY1 := centerY - 8
+		cursorY2 := centerY + 8
 
-		// 绘制光标线
-		for i := 0; i < 2; i++ {
-			for y := cursorY1; y <= cursorY2; y++ {
-				dst.Set(cursorX+i, y, textColor)
-			}
-		}
+		// 绘制光标线（2像素宽）
+		list.FillRect(image.Rect(cursorX, cursorY1, cursorX+2, cursorY2+1), textColor)
 	}
 }
 
 // SetEnabled 设置启用状态
 func (t *TextInputWidget) SetEnabled(enabled bool) {
+	t.MarkDirty()
 	t.Enabled = enabled
 	if !enabled {
-		t.CurrentState = TextInputStateDisabled
-		t.Focused = false
-	} else {
-		t.CurrentState = TextInputStateNormal
+		setWidgetState(t, WidgetStateFocused, false, nil)
+	}
+	t.syncLegacyState()
+}
+
+// HasFocus 是否持有焦点
+func (t *TextInputWidget) HasFocus() bool {
+	return t.state.Has(WidgetStateFocused)
+}
+
+// InteractionState 按交互状态推导的文本输入状态
+func (t *TextInputWidget) InteractionState() TextInputState {
+	switch {
+	case !t.Enabled:
+		return TextInputStateDisabled
+	case t.HasFocus():
+		return TextInputStateEditing
+	}
+	return TextInputStateNormal
+}
+
+// syncLegacyState 按交互状态同步CurrentState和Focused
+func (t *TextInputWidget) syncLegacyState() {
+	t.CurrentState = t.InteractionState()
+	t.Focused = t.HasFocus()
+}
+
+// SetFocused 设置焦点状态（由FocusManager在更新焦点状态后调用，此时只重置光标；直接调用时静默设置焦点状态）
+func (t *TextInputWidget) SetFocused(focused bool) {
+	t.MarkDirty()
+	setWidgetState(t, WidgetStateFocused, focused, nil)
+	if !t.Enabled {
+		return
+	}
+	if focused {
+		t.CursorVisible = true
+		t.cursorTimer = time.Now()
 	}
 }
 
@@ -315,6 +328,7 @@ func (t *TextInputWidget) SetText(text string) {
 	}
 	t.Text = text
 	t.CursorPos = len([]rune(text))
+	t.MarkDirty()
 }
 
 // GetText 获取文本
@@ -0,0 +1,646 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 状态伪类
+const (
+	StateHover    = "hover"
+	StatePressed  = "pressed"
+	StateFocused  = "focused"
+	StateDisabled = "disabled"
+	StateSelected = "selected" // 复选框和单选按钮的选中状态、下拉框的选中项
+)
+
+// Selector 样式规则的选择器
+// 语法：类型、.类名、#ID和:状态的组合，例如 button、.primary、#okButton、button.primary:pressed、*
+// 不支持后代和子代选择器
+type Selector struct {
+	Type    WidgetType // 控件类型（空表示任意类型）
+	ID      string
+	Classes []string
+	State   string // 状态伪类（空表示默认状态）
+}
+
+// ParseSelector 解析选择器
+func ParseSelector(s string) (Selector, error) {
+	var sel Selector
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return sel, fmt.Errorf("empty selector")
+	}
+
+	// 按.#:拆分为若干部分，第一部分为类型
+	start, kind := 0, byte(0)
+	flush := func(end int) error {
+		part := s[start:end]
+		if part == "" && kind != 0 {
+			return fmt.Errorf("invalid selector %q", s)
+		}
+		switch kind {
+		case 0:
+			if part != "" && part != "*" {
+				sel.Type = WidgetType(part)
+			}
+		case '.':
+			sel.Classes = append(sel.Classes, part)
+		case '#':
+			if sel.ID != "" {
+				return fmt.Errorf("selector %q has more than one id", s)
+			}
+			sel.ID = part
+		case ':':
+			if sel.State != "" {
+				return fmt.Errorf("selector %q has more than one state", s)
+			}
+			switch part {
+			case StateHover, StatePressed, StateFocused, StateDisabled, StateSelected:
+				sel.State = part
+			default:
+				return fmt.Errorf("unknown state %q in selector %q", part, s)
+			}
+		}
+		return nil
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '.', '#', ':':
+			if err := flush(i); err != nil {
+				return sel, err
+			}
+			start, kind = i+1, c
+		case ' ', '>', '+', '~', ',':
+			return sel, fmt.Errorf("unsupported selector %q", s)
+		}
+	}
+	if err := flush(len(s)); err != nil {
+		return sel, err
+	}
+	return sel, nil
+}
+
+// Specificity 选择器的优先级：ID > 类和状态 > 类型
+func (s Selector) Specificity() int {
+	spec := 0
+	if s.ID != "" {
+		spec += 10000
+	}
+	spec += 100 * len(s.Classes)
+	if s.State != "" {
+		spec += 100
+	}
+	if s.Type != "" {
+		spec++
+	}
+	return spec
+}
+
+// Matches 选择器是否匹配控件（不考虑状态）
+func (s Selector) Matches(widget Widget) bool {
+	if s.Type != "" && s.Type != widget.GetType() {
+		return false
+	}
+	if s.ID != "" && s.ID != widget.GetID() {
+		return false
+	}
+	if len(s.Classes) > 0 {
+		c, ok := widget.(interface{ HasClass(string) bool })
+		if !ok {
+			return false
+		}
+		for _, class := range s.Classes {
+			if !c.HasClass(class) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// StyleRule 样式规则
+type StyleRule struct {
+	Selector Selector
+	// Properties 属性（键与.ui文件中控件的属性名相同，例如backgroundColor、textColor、borderWidth、padding）
+	Properties map[string]interface{}
+}
+
+// Theme 主题：按选择器匹配控件的样式规则
+// 优先级从低到高：控件默认值、主题规则（按选择器优先级，相同时后定义的规则优先）、.ui中直接声明的属性
+// 文本属性（inheritedProperties）没有被任何规则或声明设置时继承最近的祖先控件的值
+type Theme struct {
+	Name  string
+	Rules []StyleRule
+}
+
+// inheritedProperties 从祖先控件继承的属性
+var inheritedProperties = []string{"textColor", "textColorAlpha", "fontSize"}
+
+// ParseTheme 解析主题数据，例如
+//
+//	{"name": "dark", "extends": "base.theme.json", "rules": [{"selector": "button, label.title", "style": {"textColor": "#ffffff"}}]}
+//
+// selector可以用逗号分隔多个选择器；extends（基础主题）由Loader.LoadTheme解析
+func ParseTheme(data map[string]interface{}) (*Theme, error) {
+	theme := &Theme{}
+	theme.Name, _ = data["name"].(string)
+
+	rules, _ := data["rules"].([]interface{})
+	for i, ruleData := range rules {
+		ruleObj, ok := ruleData.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("theme rule %d is not an object", i)
+		}
+		selectors, _ := ruleObj["selector"].(string)
+		style, _ := ruleObj["style"].(map[string]interface{})
+		for _, part := range strings.Split(selectors, ",") {
+			sel, err := ParseSelector(part)
+			if err != nil {
+				return nil, fmt.Errorf("theme rule %d: %w", i, err)
+			}
+			theme.Rules = append(theme.Rules, StyleRule{Selector: sel, Properties: style})
+		}
+	}
+	return theme, nil
+}
+
+// Extend 返回在基础主题之上添加本主题规则的新主题（本主题的规则在优先级相同时覆盖基础主题）
+func (t *Theme) Extend(base *Theme) *Theme {
+	if base == nil {
+		return t
+	}
+	rules := make([]StyleRule, 0, len(base.Rules)+len(t.Rules))
+	rules = append(rules, base.Rules...)
+	rules = append(rules, t.Rules...)
+	return &Theme{Name: t.Name, Rules: rules}
+}
+
+// resolve 计算控件的主题属性（键为控件的具体属性名，已经按状态映射）
+// 状态规则中控件没有对应状态属性的属性放入states，在控件处于该状态时覆盖默认状态的属性
+// parent为父控件计算出的可继承属性，返回本控件的可继承属性供子控件使用
+func (t *Theme) resolve(widget Widget, inline map[string]interface{}, parent map[string]interface{}) (props map[string]interface{}, states map[string]map[string]interface{}, inherited map[string]interface{}) {
+	type match struct {
+		rule *StyleRule
+		spec int
+	}
+	var matches []match
+	if t != nil {
+		for i := range t.Rules {
+			if t.Rules[i].Selector.Matches(widget) {
+				matches = append(matches, match{&t.Rules[i], t.Rules[i].Selector.Specificity()})
+			}
+		}
+	}
+	// 优先级相同时保持定义顺序，后定义的规则覆盖先定义的
+	sort.SliceStable(matches, func(a, b int) bool { return matches[a].spec < matches[b].spec })
+
+	props = make(map[string]interface{})
+	generic := make(map[string]interface{}) // 默认状态下的通用属性（用于继承）
+	for _, m := range matches {
+		state := m.rule.Selector.State
+		for key, value := range m.rule.Properties {
+			if prop := stateProperty(widget.GetType(), state, key); prop != "" {
+				props[prop] = value
+			} else {
+				if states == nil {
+					states = make(map[string]map[string]interface{})
+				}
+				if states[state] == nil {
+					states[state] = make(map[string]interface{})
+				}
+				states[state][stateProperty(widget.GetType(), "", key)] = value
+			}
+			if state == "" {
+				generic[key] = value
+			}
+		}
+	}
+
+	inherited = make(map[string]interface{})
+	for _, key := range inheritedProperties {
+		value, ok := inline[key]
+		if !ok {
+			value, ok = generic[key]
+		}
+		if !ok {
+			value, ok = parent[key]
+			if ok {
+				if prop := stateProperty(widget.GetType(), "", key); prop != "" {
+					props[prop] = value
+				}
+			}
+		}
+		if ok {
+			inherited[key] = value
+		}
+	}
+	return props, states, inherited
+}
+
+// stateProperties 各控件的通用属性在各状态下对应的具体属性
+// 没有列出的控件和属性在默认状态下使用通用属性名；状态规则中没有对应具体属性的属性作为状态样式，在控件处于该状态时覆盖默认状态的属性
+var stateProperties = map[WidgetType]map[string]map[string]string{
+	TypeButton: {
+		"": {
+			"backgroundColor":      "backgroundColorNormal",
+			"backgroundColorAlpha": "backgroundColorNormalAlpha",
+		},
+		StatePressed: {
+			"backgroundColor":      "backgroundColorPressed",
+			"backgroundColorAlpha": "backgroundColorPressedAlpha",
+		},
+		StateDisabled: {
+			"backgroundColor":      "backgroundColorDisabled",
+			"backgroundColorAlpha": "backgroundColorDisabledAlpha",
+		},
+	},
+	TypeTextInput: {
+		"": {
+			"backgroundColor":      "backgroundColorNormal",
+			"backgroundColorAlpha": "backgroundColorNormalAlpha",
+		},
+		StateFocused: {
+			"backgroundColor":      "backgroundColorEditing",
+			"backgroundColorAlpha": "backgroundColorEditingAlpha",
+		},
+		StateDisabled: {
+			"backgroundColor":      "backgroundColorDisabled",
+			"backgroundColorAlpha": "backgroundColorDisabledAlpha",
+		},
+	},
+	TypeCheckBox: {
+		"": {
+			"backgroundColor":      "boxBgColor",
+			"backgroundColorAlpha": "boxBgColorAlpha",
+			"borderColor":          "boxBorderColor",
+			"borderColorAlpha":     "boxBorderColorAlpha",
+		},
+		StateSelected: {
+			"backgroundColor":      "checkedBgColor",
+			"backgroundColorAlpha": "checkedBgColorAlpha",
+			"color":                "checkMarkColor",
+			"colorAlpha":           "checkMarkColorAlpha",
+		},
+	},
+	TypeRadioButton: {
+		"": {
+			"backgroundColor":      "buttonBgColor",
+			"backgroundColorAlpha": "buttonBgColorAlpha",
+		},
+		StateSelected: {
+			"backgroundColor":      "selectedBgColor",
+			"backgroundColorAlpha": "selectedBgColorAlpha",
+			"color":                "dotColor",
+			"colorAlpha":           "dotColorAlpha",
+		},
+	},
+	TypeComboBox: {
+		StateHover: {
+			"backgroundColor":      "hoverBgColor",
+			"backgroundColorAlpha": "hoverBgColorAlpha",
+		},
+		StateSelected: {
+			"backgroundColor":      "selectedBgColor",
+			"backgroundColorAlpha": "selectedBgColorAlpha",
+		},
+	},
+	TypeSlider: {
+		"": {
+			"backgroundColor":      "trackBgColor",
+			"backgroundColorAlpha": "trackBgColorAlpha",
+			"color":                "thumbColor",
+			"colorAlpha":           "thumbColorAlpha",
+		},
+		StateHover: {
+			"color":      "thumbHoverColor",
+			"colorAlpha": "thumbHoverAlpha",
+		},
+	},
+}
+
+// stateProperty 获取通用属性在控件类型的某个状态下对应的具体属性（没有对应属性时返回空）
+func stateProperty(widgetType WidgetType, state, key string) string {
+	if prop, ok := stateProperties[widgetType][state][key]; ok {
+		return prop
+	}
+	if state == "" {
+		return key
+	}
+	return ""
+}
+
+// styleState 控件的样式状态
+type styleState struct {
+	inline map[string]interface{} // .ui中直接声明的属性（优先于主题）
+	themed map[string]interface{} // 主题修改过的属性在修改前的值（切换主题时恢复）
+
+	// 状态样式：控件处于某个交互状态时覆盖的属性（键为状态名和具体属性名）
+	states       map[string]map[string]interface{} // .ui中"states"声明的状态样式（优先于主题）
+	themedStates map[string]map[string]interface{} // 主题状态规则给出的状态样式
+	stateApplied map[string]interface{}            // 当前生效的状态样式覆盖的属性在覆盖前的值
+}
+
+// HasClass 控件是否具有样式类
+func (w *BaseWidget) HasClass(class string) bool {
+	for _, c := range strings.Fields(w.Class) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// style 获取控件的样式状态
+func (w *BaseWidget) style() *styleState {
+	return &w.styling
+}
+
+// styledWidget 能应用主题的控件（嵌入BaseWidget的控件都实现了该接口）
+type styledWidget interface {
+	style() *styleState
+}
+
+// SetTheme 运行时为控件树应用主题（theme为nil时移除主题）
+// 先恢复上一个主题修改过的属性，再按新主题重新计算，.ui中直接声明的属性不受影响；被修改的控件标记为需要重绘
+func SetTheme(roots []Widget, theme *Theme) {
+	applyTheme(roots, theme, nil)
+}
+
+// applyTheme 递归应用主题
+func applyTheme(widgets []Widget, theme *Theme, parent map[string]interface{}) {
+	for _, widget := range widgets {
+		inherited := parent
+		if styled, ok := widget.(styledWidget); ok {
+			state := styled.style()
+			// 先撤销状态样式，使主题记录和恢复的都是默认状态的值
+			changed := restoreStateStyles(widget, state)
+			if restoreThemed(widget, state) {
+				changed = true
+			}
+			var props map[string]interface{}
+			props, state.themedStates, inherited = theme.resolve(widget, state.inline, parent)
+			if applyProperties(widget, state, props) {
+				changed = true
+			}
+			if holder, ok := widget.(stateHolder); ok && applyStateStyles(widget, state, *holder.stateFlags()) {
+				changed = true
+			}
+			if changed {
+				if d, ok := widget.(interface{ MarkDirty() }); ok {
+					d.MarkDirty()
+				}
+			}
+		}
+		applyTheme(widget.GetChildren(), theme, inherited)
+	}
+}
+
+// restoreThemed 恢复主题修改过的属性
+func restoreThemed(widget Widget, state *styleState) bool {
+	if len(state.themed) == 0 {
+		return false
+	}
+	v := reflect.ValueOf(widget)
+	for key, old := range state.themed {
+		if field, ok := styleField(v, key); ok {
+			field.Set(reflect.ValueOf(old))
+		}
+	}
+	state.themed = nil
+	return true
+}
+
+// applyProperties 把属性设置到控件的字段上（跳过.ui中直接声明的属性），返回是否有属性被设置
+func applyProperties(widget Widget, state *styleState, props map[string]interface{}) bool {
+	v := reflect.ValueOf(widget)
+	changed := false
+	for key, value := range props {
+		if _, ok := state.inline[key]; ok {
+			continue
+		}
+		field, ok := styleField(v, key)
+		if !ok {
+			continue
+		}
+		old := reflect.New(field.Type()).Elem()
+		old.Set(field)
+		if err := setStyleField(field, value); err != nil {
+			log.Printf("[Theme] %s: property %s: %v", widget.GetID(), key, err)
+			continue
+		}
+		if state.themed == nil {
+			state.themed = make(map[string]interface{})
+		}
+		if _, ok := state.themed[key]; !ok {
+			state.themed[key] = old.Interface()
+		}
+		changed = true
+	}
+	return changed
+}
+
+// applyStateStyles 按控件当前的交互状态重新应用状态样式，返回是否有属性被覆盖
+// 成立的状态按hover、focused、selected、pressed、disabled的顺序应用，后面的状态优先；同一状态中.ui声明的优先于主题，主题的状态样式不覆盖.ui中直接声明的属性
+func applyStateStyles(widget Widget, state *styleState, flags WidgetState) bool {
+	restoreStateStyles(widget, state)
+	if len(state.states) == 0 && len(state.themedStates) == 0 {
+		return false
+	}
+
+	props := make(map[string]interface{})
+	for _, s := range widgetStates {
+		if !flags.Has(s.flag) {
+			continue
+		}
+		for key, value := range state.themedStates[s.name] {
+			if _, ok := state.inline[key]; !ok {
+				props[key] = value
+			}
+		}
+		for key, value := range state.states[s.name] {
+			props[key] = value
+		}
+	}
+
+	v := reflect.ValueOf(widget)
+	for key, value := range props {
+		field, ok := styleField(v, key)
+		if !ok {
+			continue
+		}
+		old := reflect.New(field.Type()).Elem()
+		old.Set(field)
+		if err := setStyleField(field, value); err != nil {
+			log.Printf("[Theme] %s: state property %s: %v", widget.GetID(), key, err)
+			continue
+		}
+		if state.stateApplied == nil {
+			state.stateApplied = make(map[string]interface{})
+		}
+		state.stateApplied[key] = old.Interface()
+	}
+	return len(state.stateApplied) > 0
+}
+
+// restoreStateStyles 撤销当前生效的状态样式
+func restoreStateStyles(widget Widget, state *styleState) bool {
+	if len(state.stateApplied) == 0 {
+		return false
+	}
+	v := reflect.ValueOf(widget)
+	for key, old := range state.stateApplied {
+		if field, ok := styleField(v, key); ok {
+			field.Set(reflect.ValueOf(old))
+		}
+	}
+	state.stateApplied = nil
+	return true
+}
+
+// parseStateStyles 解析.ui中控件的"states"声明，例如
+// {"hover": {"backgroundColor": "#5a9cff"}, "pressed": {"opacity": 80}}
+// 通用属性名按控件类型映射为默认状态的具体属性（例如按钮的backgroundColor映射为backgroundColorNormal）
+func parseStateStyles(widgetType WidgetType, data map[string]interface{}) map[string]map[string]interface{} {
+	states := make(map[string]map[string]interface{}, len(data))
+	for name, value := range data {
+		props, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if ParseWidgetState(name) == 0 {
+			log.Printf("[Theme] unknown state %q", name)
+			continue
+		}
+		mapped := make(map[string]interface{}, len(props))
+		for key, v := range props {
+			mapped[stateProperty(widgetType, "", key)] = v
+		}
+		states[name] = mapped
+	}
+	return states
+}
+
+// styleField 按JSON标签查找控件的可设置字段（与encoding/json相同，外层字段优先于嵌入结构体的字段）
+func styleField(v reflect.Value, key string) (reflect.Value, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+
+	level := []reflect.Value{v}
+	for len(level) > 0 {
+		var next []reflect.Value
+		for _, s := range level {
+			t := s.Type()
+			for i := 0; i < t.NumField(); i++ {
+				f := t.Field(i)
+				tag := strings.Split(f.Tag.Get("json"), ",")[0]
+				if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+					next = append(next, s.Field(i))
+					continue
+				}
+				if f.IsExported() && tag == key {
+					return s.Field(i), true
+				}
+			}
+		}
+		level = next
+	}
+	return reflect.Value{}, false
+}
+
+// setStyleField 把主题中的属性值（JSON解码得到的值）转换后设置到字段
+func setStyleField(field reflect.Value, value interface{}) error {
+	if field.Type() == reflect.TypeOf(RGBA{}) {
+		if s, ok := value.(string); ok {
+			c, err := parseHexColor(s)
+			if err != nil {
+				return err
+			}
+			field.Set(reflect.ValueOf(c))
+			return nil
+		}
+	}
+	// 渐变和阴影与.ui使用相同的解析（支持#RRGGBBAA颜色和省略的色标位置）
+	switch field.Type() {
+	case reflect.TypeOf(Gradient{}):
+		if m, ok := value.(map[string]interface{}); ok {
+			field.Set(reflect.ValueOf(parseGradient(m)))
+			return nil
+		}
+	case reflect.TypeOf([]BoxShadow(nil)):
+		field.Set(reflect.ValueOf(parseBoxShadows(value)))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := value.(float64); ok {
+			field.SetInt(int64(n))
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, ok := value.(float64); ok && n >= 0 {
+			if field.OverflowUint(uint64(n)) {
+				return fmt.Errorf("value %v out of range", n)
+			}
+			field.SetUint(uint64(n))
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, ok := value.(float64); ok {
+			field.SetFloat(n)
+			return nil
+		}
+	case reflect.String:
+		if s, ok := value.(string); ok {
+			field.SetString(s)
+			return nil
+		}
+	case reflect.Bool:
+		if b, ok := value.(bool); ok {
+			field.SetBool(b)
+			return nil
+		}
+	default:
+		// 结构体等复合类型（例如padding、backgroundSlice）按JSON解码
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		target := reflect.New(field.Type())
+		target.Elem().Set(field)
+		if err := json.Unmarshal(data, target.Interface()); err != nil {
+			return err
+		}
+		field.Set(target.Elem())
+		return nil
+	}
+	return fmt.Errorf("cannot use %v (%T) as %s", value, value, field.Type())
+}
+
+// parseHexColor 解析#RRGGBB或#RRGGBBAA格式的颜色
+func parseHexColor(s string) (RGBA, error) {
+	if (len(s) != 7 && len(s) != 9) || s[0] != '#' {
+		return RGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+	n, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return RGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+	if len(s) == 7 {
+		n = n<<8 | 0xff
+	}
+	return RGBA{R: uint8(n >> 24), G: uint8(n >> 16), B: uint8(n >> 8), A: uint8(n)}, nil
+}
@@ -0,0 +1,258 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// mustTheme 解析测试主题
+func mustTheme(t *testing.T, rules ...interface{}) *Theme {
+	t.Helper()
+	theme, err := ParseTheme(map[string]interface{}{"name": "test", "rules": rules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return theme
+}
+
+// rule 创建主题规则数据
+func rule(selector string, style map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"selector": selector, "style": style}
+}
+
+// TestTheme_ParseSelector 测试选择器解析
+func TestTheme_ParseSelector(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Selector
+	}{
+		{"button", Selector{Type: TypeButton}},
+		{"*", Selector{}},
+		{".primary", Selector{Classes: []string{"primary"}}},
+		{"#ok", Selector{ID: "ok"}},
+		{"button.primary.large:pressed", Selector{Type: TypeButton, Classes: []string{"primary", "large"}, State: StatePressed}},
+		{" textinput#name:focused ", Selector{Type: TypeTextInput, ID: "name", State: StateFocused}},
+	}
+	for _, tt := range tests {
+		got, err := ParseSelector(tt.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected %+v, got %+v", tt.in, tt.want, got)
+		}
+	}
+
+	for _, bad := range []string{"", "button:active", "panel button", "#a#b", "button.", "label:hover:pressed"} {
+		if _, err := ParseSelector(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+// TestTheme_Precedence 测试优先级：类型 < 类 < ID < .ui中直接声明的属性，相同优先级后定义的规则优先
+func TestTheme_Precedence(t *testing.T) {
+	theme := mustTheme(t,
+		rule("#title", map[string]interface{}{"fontSize": float64(30)}),
+		rule("label", map[string]interface{}{"fontSize": float64(10), "textColor": "#111111"}),
+		rule(".big", map[string]interface{}{"fontSize": float64(20)}),
+		rule("label", map[string]interface{}{"textColor": "#222222"}),
+		rule("*", map[string]interface{}{"borderWidth": float64(3)}),
+	)
+
+	plain := NewLabel("plain")
+	big := NewLabel("big")
+	big.Class = "big"
+	title := NewLabel("title")
+	title.Class = "big"
+	inline := NewLabel("inline")
+	inline.Class = "big"
+	inline.FontSize = 12
+	inline.styling.inline = map[string]interface{}{"fontSize": float64(12)}
+
+	SetTheme([]Widget{plain, big, title, inline}, theme)
+
+	for _, c := range []struct {
+		w    *LabelWidget
+		size int
+	}{{plain, 10}, {big, 20}, {title, 30}, {inline, 12}} {
+		if c.w.FontSize != c.size {
+			t.Errorf("%s: expected font size %d, got %d", c.w.ID, c.size, c.w.FontSize)
+		}
+	}
+	if plain.TextColor != (RGBA{0x22, 0x22, 0x22, 255}) {
+		t.Errorf("expected later rule to win, got %v", plain.TextColor)
+	}
+	if plain.BorderWidth != 3 {
+		t.Errorf("expected universal rule to apply, got border width %d", plain.BorderWidth)
+	}
+}
+
+// TestTheme_StateVariants 测试状态伪类映射到控件的状态属性
+func TestTheme_StateVariants(t *testing.T) {
+	theme := mustTheme(t,
+		rule("button", map[string]interface{}{"backgroundColor": "#101010"}),
+		rule("button:pressed", map[string]interface{}{"backgroundColor": "#202020", "backgroundColorAlpha": float64(128)}),
+		rule("button:disabled", map[string]interface{}{"backgroundColor": "#303030"}),
+		rule("button:hover", map[string]interface{}{"backgroundColor": "#404040"}), // 按钮没有悬停背景，作为状态样式在悬停时覆盖默认背景
+		rule("textinput:focused", map[string]interface{}{"backgroundColor": "#505050"}),
+		rule("checkbox:selected", map[string]interface{}{"backgroundColor": "#606060", "color": "#ff0000"}),
+		rule("combobox:hover", map[string]interface{}{"backgroundColor": "#707070"}),
+	)
+	btn := NewButton("b")
+	input := NewTextInput("in")
+	check := NewCheckBox("c", 0, 0, 100, 20)
+	combo := NewComboBox("cb", 0, 0, 100, 20)
+	SetTheme([]Widget{btn, input, check, combo}, theme)
+
+	if btn.BackgroundColorNormal != (RGBA{0x10, 0x10, 0x10, 255}) {
+		t.Errorf("expected normal background from button rule, got %v", btn.BackgroundColorNormal)
+	}
+	if btn.BackgroundColorPressed != (RGBA{0x20, 0x20, 0x20, 255}) || btn.BackgroundColorPressedAlpha != 128 {
+		t.Errorf("unexpected pressed background %v/%d", btn.BackgroundColorPressed, btn.BackgroundColorPressedAlpha)
+	}
+	if btn.BackgroundColorDisabled != (RGBA{0x30, 0x30, 0x30, 255}) {
+		t.Errorf("unexpected disabled background %v", btn.BackgroundColorDisabled)
+	}
+	if input.BackgroundColorEditing != (RGBA{0x50, 0x50, 0x50, 255}) {
+		t.Errorf("expected focused rule to set editing background, got %v", input.BackgroundColorEditing)
+	}
+	if check.CheckedBgColor != (RGBA{0x60, 0x60, 0x60, 255}) || check.CheckMarkColor != (RGBA{255, 0, 0, 255}) {
+		t.Errorf("unexpected checked colors %v %v", check.CheckedBgColor, check.CheckMarkColor)
+	}
+	if combo.HoverBgColor != (RGBA{0x70, 0x70, 0x70, 255}) {
+		t.Errorf("expected hover rule to set hover background, got %v", combo.HoverBgColor)
+	}
+}
+
+// TestTheme_InheritsTextProperties 测试文本属性从祖先控件继承
+func TestTheme_InheritsTextProperties(t *testing.T) {
+	theme := mustTheme(t,
+		rule(".dark", map[string]interface{}{"textColor": "#eeeeee", "fontSize": float64(18)}),
+		rule("#override", map[string]interface{}{"textColor": "#ff0000"}),
+	)
+	root := NewPanel("root")
+	root.Class = "dark"
+	inner := NewPanel("inner")
+	label := NewLabel("label")
+	override := NewLabel("override")
+	inline := NewLabel("inline")
+	inline.styling.inline = map[string]interface{}{"textColor": "#00ff00"}
+	inline.TextColor = RGBA{0, 255, 0, 255}
+	inner.AddChild(label)
+	inner.AddChild(override)
+	root.AddChild(inner)
+	root.AddChild(inline)
+
+	SetTheme([]Widget{root}, theme)
+
+	if label.TextColor != (RGBA{0xee, 0xee, 0xee, 255}) || label.FontSize != 18 {
+		t.Errorf("expected label to inherit through panels, got %v size %d", label.TextColor, label.FontSize)
+	}
+	if override.TextColor != (RGBA{255, 0, 0, 255}) || override.FontSize != 18 {
+		t.Errorf("expected own rule to beat inherited color, got %v size %d", override.TextColor, override.FontSize)
+	}
+	if inline.TextColor != (RGBA{0, 255, 0, 255}) {
+		t.Errorf("expected inline color to be kept, got %v", inline.TextColor)
+	}
+}
+
+// TestTheme_SetThemeRestylesLive 测试运行时切换主题恢复上一个主题修改的属性并标记重绘
+func TestTheme_SetThemeRestylesLive(t *testing.T) {
+	dark := mustTheme(t,
+		rule("button", map[string]interface{}{"backgroundColor": "#000000", "borderWidth": float64(2)}),
+	)
+	light := mustTheme(t,
+		rule("button", map[string]interface{}{"backgroundColor": "#ffffff"}),
+	)
+	btn := NewButton("b")
+	defaultBg, defaultBorder := btn.BackgroundColorNormal, btn.BorderWidth
+
+	SetTheme([]Widget{btn}, dark)
+	if btn.BackgroundColorNormal != (RGBA{0, 0, 0, 255}) || btn.BorderWidth != 2 {
+		t.Fatalf("dark theme not applied: %v %d", btn.BackgroundColorNormal, btn.BorderWidth)
+	}
+
+	btn.dirty = false
+	SetTheme([]Widget{btn}, light)
+	if btn.BackgroundColorNormal != (RGBA{255, 255, 255, 255}) {
+		t.Errorf("light theme not applied: %v", btn.BackgroundColorNormal)
+	}
+	if btn.BorderWidth != defaultBorder {
+		t.Errorf("expected border width set only by dark theme to be restored to %d, got %d", defaultBorder, btn.BorderWidth)
+	}
+	if !btn.dirty {
+		t.Errorf("expected restyled widget to be marked dirty")
+	}
+
+	SetTheme([]Widget{btn}, nil)
+	if btn.BackgroundColorNormal != defaultBg {
+		t.Errorf("expected removing the theme to restore %v, got %v", defaultBg, btn.BackgroundColorNormal)
+	}
+}
+
+// TestTheme_LoaderAppliesThemeWithExtends 测试Loader加载.ui中声明的主题文件（包括基础主题），直接声明的属性优先
+func TestTheme_LoaderAppliesThemeWithExtends(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("base.theme.json", `{"name": "base", "rules": [
+		{"selector": "button", "style": {"borderRadius": 6, "textColor": "#010101"}}]}`)
+	write("game.theme.json", `{"name": "game", "extends": "base.theme.json", "rules": [
+		{"selector": "button", "style": {"textColor": "#020202"}},
+		{"selector": ".primary", "style": {"backgroundColor": "#0000ff"}},
+		{"selector": "panel", "style": {"padding": {"top": 5, "left": 7}}}]}`)
+
+	loader := NewLoader()
+	loader.resourcePath = dir
+	roots, err := loader.LoadFromData(map[string]interface{}{
+		"theme": "game.theme.json",
+		"widgets": []interface{}{
+			map[string]interface{}{"id": "root", "type": "panel"},
+			map[string]interface{}{"id": "ok", "type": "button", "parentId": "root", "class": "primary"},
+			map[string]interface{}{"id": "cancel", "type": "button", "parentId": "root", "textColor": "#030303"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := roots[0].(*PanelWidget)
+	ok := root.Children[0].(*ButtonWidget)
+	cancel := root.Children[1].(*ButtonWidget)
+	if root.Padding != (Spacing{Top: 5, Left: 7}) {
+		t.Errorf("expected panel padding from theme, got %+v", root.Padding)
+	}
+	if ok.BorderRadius != 6 || ok.TextColor != (RGBA{2, 2, 2, 255}) || ok.BackgroundColorNormal != (RGBA{0, 0, 255, 255}) {
+		t.Errorf("unexpected themed button: radius %d text %v bg %v", ok.BorderRadius, ok.TextColor, ok.BackgroundColorNormal)
+	}
+	if cancel.TextColor != (RGBA{3, 3, 3, 255}) {
+		t.Errorf("expected inline text color to win, got %v", cancel.TextColor)
+	}
+
+	// 循环继承
+	write("a.theme.json", `{"extends": "b.theme.json"}`)
+	write("b.theme.json", `{"extends": "a.theme.json"}`)
+	if _, err := loader.LoadTheme(filepath.Join(dir, "a.theme.json")); err == nil {
+		t.Errorf("expected inheritance cycle to be reported")
+	}
+}
+
+// TestTheme_ViewerExampleThemes 测试示例查看器附带的主题文件可以加载
+func TestTheme_ViewerExampleThemes(t *testing.T) {
+	for _, name := range []string{"dark", "light"} {
+		theme, err := NewLoader().LoadTheme(filepath.Join("examples", "viewer", "themes", name+".theme.json"))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if theme.Name != name || len(theme.Rules) == 0 {
+			t.Errorf("%s: unexpected theme %q with %d rules", name, theme.Name, len(theme.Rules))
+		}
+	}
+}
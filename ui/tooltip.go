@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+// TooltipConfig 提示显示配置
+type TooltipConfig struct {
+	Delay   time.Duration // 悬停多久后显示
+	Offset  int           // 与光标的垂直距离（放不下时翻转到光标上方）
+	Padding int           // 文本提示的内边距
+
+	Font            font.Face
+	BackgroundColor color.RGBA
+	BorderColor     color.RGBA
+	TextColor       color.RGBA
+}
+
+// DefaultTooltipConfig 返回默认提示配置
+func DefaultTooltipConfig() TooltipConfig {
+	return TooltipConfig{
+		Delay:           500 * time.Millisecond,
+		Offset:          16,
+		Padding:         6,
+		Font:            basicfont.Face7x13,
+		BackgroundColor: color.RGBA{40, 40, 40, 235},
+		BorderColor:     color.RGBA{100, 100, 100, 255},
+		TextColor:       color.RGBA{255, 255, 255, 255},
+	}
+}
+
+// tooltipProvider 声明了提示的控件（所有嵌入BaseWidget的控件都实现了该接口）
+type tooltipProvider interface {
+	GetTooltip() string
+	GetTooltipTemplate() string
+}
+
+// hasTooltip 判断控件是否声明了提示
+func hasTooltip(widget Widget) bool {
+	t, ok := widget.(tooltipProvider)
+	return ok && (t.GetTooltip() != "" || t.GetTooltipTemplate() != "")
+}
+
+// TooltipManager 提示管理器
+// 光标在声明了tooltip的控件上停留超过Delay后显示提示；离开、点击或控件滚出可见区域时隐藏
+type TooltipManager struct {
+	config TooltipConfig
+	roots  []Widget
+
+	target     Widget    // 光标下声明了提示的控件
+	hoverStart time.Time // 进入target的时间
+	suppressed bool      // 点击后隐藏，直到离开该控件
+
+	visible          bool
+	cursorX, cursorY int // 显示时的光标位置
+	viewport         image.Rectangle
+	bounds           image.Rectangle // 提示在屏幕上的位置
+}
+
+// NewTooltipManager 创建提示管理器
+func NewTooltipManager(config TooltipConfig) *TooltipManager {
+	return &TooltipManager{config: config}
+}
+
+// Config 获取当前配置
+func (m *TooltipManager) Config() TooltipConfig {
+	return m.config
+}
+
+// SetConfig 修改配置
+func (m *TooltipManager) SetConfig(config TooltipConfig) {
+	m.config = config
+}
+
+// IsVisible 提示是否正在显示
+func (m *TooltipManager) IsVisible() bool {
+	return m.visible
+}
+
+// GetTarget 获取当前提示所属的控件（未显示时返回光标下等待显示的控件）
+func (m *TooltipManager) GetTarget() Widget {
+	return m.target
+}
+
+// Bounds 获取提示在屏幕上的位置
+func (m *TooltipManager) Bounds() image.Rectangle {
+	return m.bounds
+}
+
+// Hide 立即隐藏提示（光标离开并重新进入后才会再次显示）
+func (m *TooltipManager) Hide() {
+	m.visible = false
+	m.suppressed = true
+}
+
+// Update 根据光标位置更新提示状态（每帧调用一次）
+// pressed表示本帧有按键或触点按下
+func (m *TooltipManager) Update(roots []Widget, viewport image.Rectangle, x, y int, pressed bool, now time.Time) {
+	m.roots = roots
+	m.viewport = viewport
+
+	// 查找时遵循父容器裁剪，控件滚出可见区域后不再命中
+	target := findTooltipTarget(roots, viewport, image.Pt(x, y))
+	if target != m.target {
+		m.target = target
+		m.hoverStart = now
+		m.suppressed = false
+		m.visible = false
+	}
+	if target == nil {
+		return
+	}
+
+	if pressed {
+		m.Hide()
+		return
+	}
+	if m.suppressed {
+		return
+	}
+
+	if !m.visible && now.Sub(m.hoverStart) >= m.config.Delay {
+		m.visible = true
+		m.cursorX, m.cursorY = x, y
+	}
+	if m.visible {
+		// 每帧重新计算，脚本修改提示内容后立即生效
+		m.bounds = PlaceTooltip(m.contentSize(), image.Pt(m.cursorX, m.cursorY), viewport, m.config.Offset)
+	}
+}
+
+// findTooltipTarget 查找坐标下最上层声明了提示的可见控件（与命中测试相同的层叠顺序和裁剪规则）
+func findTooltipTarget(roots []Widget, viewport image.Rectangle, pt image.Point) Widget {
+	return buildStackOrder(roots, viewport, viewport).topmost(pt, func(e stackEntry) bool {
+		return hasTooltip(e.widget)
+	})
+}
+
+// PlaceTooltip 计算提示位置
+// 默认显示在光标下方，下方放不下时翻转到光标上方，然后水平和垂直平移使其留在视口内
+func PlaceTooltip(size, cursor image.Point, viewport image.Rectangle, offset int) image.Rectangle {
+	x := cursor.X
+	y := cursor.Y + offset
+	if y+size.Y > viewport.Max.Y {
+		y = cursor.Y - offset - size.Y
+	}
+
+	if x+size.X > viewport.Max.X {
+		x = viewport.Max.X - size.X
+	}
+	if x < viewport.Min.X {
+		x = viewport.Min.X
+	}
+	if y+size.Y > viewport.Max.Y {
+		y = viewport.Max.Y - size.Y
+	}
+	if y < viewport.Min.Y {
+		y = viewport.Min.Y
+	}
+	return image.Rect(x, y, x+size.X, y+size.Y)
+}
+
+// template 获取当前目标引用的模板控件
+func (m *TooltipManager) template() Widget {
+	t, ok := m.target.(tooltipProvider)
+	if !ok || t.GetTooltipTemplate() == "" {
+		return nil
+	}
+	return findWidgetByID(m.roots, t.GetTooltipTemplate())
+}
+
+// text 获取当前目标的提示文本
+func (m *TooltipManager) text() string {
+	if t, ok := m.target.(tooltipProvider); ok {
+		return t.GetTooltip()
+	}
+	return ""
+}
+
+// contentSize 计算提示内容尺寸（模板使用自身尺寸，文本按行测量）
+func (m *TooltipManager) contentSize() image.Point {
+	if tpl := m.template(); tpl != nil {
+		return image.Pt(tpl.GetWidth(), tpl.GetHeight())
+	}
+
+	pad := m.config.Padding
+	return image.Pt(textContentSize(m.face(), m.text(), Spacing{Top: pad, Right: pad, Bottom: pad, Left: pad}))
+}
+
+func (m *TooltipManager) face() font.Face {
+	if m.config.Font == nil {
+		return basicfont.Face7x13
+	}
+	return m.config.Font
+}
+
+// Draw 绘制提示（在所有控件和其他覆盖层之后调用）
+func (m *TooltipManager) Draw(screen *ebiten.Image) {
+	if !m.visible || m.target == nil {
+		return
+	}
+
+	if tpl := m.template(); tpl != nil {
+		m.drawTemplate(screen, tpl)
+		return
+	}
+
+	b := m.bounds
+	x, y := float32(b.Min.X), float32(b.Min.Y)
+	w, h := float32(b.Dx()), float32(b.Dy())
+	vector.DrawFilledRect(screen, x, y, w, h, m.config.BackgroundColor, false)
+	vector.StrokeRect(screen, x, y, w, h, 1, m.config.BorderColor, false)
+
+	face := m.face()
+	metrics := face.Metrics()
+	lineHeight := metrics.Height.Ceil()
+	baseline := b.Min.Y + m.config.Padding + metrics.Ascent.Ceil()
+	for i, line := range strings.Split(m.text(), "\n") {
+		text.Draw(screen, line, face, b.Min.X+m.config.Padding, baseline+i*lineHeight, m.config.TextColor)
+	}
+}
+
+// drawTemplate 在提示位置绘制模板控件
+// 模板控件通常在布局中设为不可见，绘制时临时显示
+func (m *TooltipManager) drawTemplate(screen *ebiten.Image, tpl Widget) {
+	wasVisible := tpl.IsVisible()
+	tpl.SetVisible(true)
+	defer tpl.SetVisible(wasVisible)
+
+	b := m.bounds
+	local := widgetBounds(tpl, image.Rect(0, 0, b.Dx(), b.Dy()))
+	tpl.Draw(screen, b.Min.X-local.Min.X, b.Min.Y-local.Min.Y, b.Dx(), b.Dy())
+}
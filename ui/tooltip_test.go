@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// newTooltipFixture 创建一个带提示的按钮，并使用可控的时钟
+func newTooltipFixture() (*InputDispatcher, *mockInputSource, *ButtonWidget, *time.Time) {
+	btn := NewButton("btn")
+	btn.X, btn.Y = 10, 10
+	btn.Tooltip = "Save the file"
+
+	src := newMockInputSource()
+	d := NewInputDispatcher(src, nil)
+	d.SetViewport(400, 300)
+	d.SetRoots([]Widget{btn})
+
+	clock := time.Unix(1000, 0)
+	d.now = func() time.Time { return clock }
+	return d, src, btn, &clock
+}
+
+// TestTooltip_ShowAfterDelay 测试悬停超过延迟后显示，离开后隐藏
+func TestTooltip_ShowAfterDelay(t *testing.T) {
+	d, src, btn, clock := newTooltipFixture()
+	tips := d.TooltipManager()
+
+	src.x, src.y = 20, 20
+	d.Update()
+	*clock = clock.Add(300 * time.Millisecond)
+	d.Update()
+	if tips.IsVisible() {
+		t.Fatal("Tooltip should not be visible before the delay")
+	}
+
+	*clock = clock.Add(300 * time.Millisecond)
+	d.Update()
+	if !tips.IsVisible() || tips.GetTarget() != btn {
+		t.Fatal("Expected tooltip to be visible after the delay")
+	}
+	if b := tips.Bounds(); b.Min.X != 20 || b.Min.Y != 20+tips.Config().Offset {
+		t.Errorf("Expected tooltip below the cursor, got %v", b)
+	}
+
+	src.x, src.y = 300, 200
+	d.Update()
+	if tips.IsVisible() {
+		t.Error("Tooltip should hide when the cursor leaves the widget")
+	}
+}
+
+// TestTooltip_HideOnClick 测试点击后隐藏，重新进入后才再次显示
+func TestTooltip_HideOnClick(t *testing.T) {
+	d, src, _, clock := newTooltipFixture()
+	tips := d.TooltipManager()
+
+	src.x, src.y = 20, 20
+	d.Update()
+	*clock = clock.Add(time.Second)
+	d.Update()
+	if !tips.IsVisible() {
+		t.Fatal("Expected tooltip to be visible")
+	}
+
+	src.buttons[ebiten.MouseButtonLeft] = true
+	d.Update()
+	src.buttons[ebiten.MouseButtonLeft] = false
+	d.Update()
+	*clock = clock.Add(time.Second)
+	d.Update()
+	if tips.IsVisible() {
+		t.Fatal("Tooltip should stay hidden after a click")
+	}
+
+	src.x, src.y = 300, 200
+	d.Update()
+	src.x, src.y = 20, 20
+	d.Update()
+	*clock = clock.Add(time.Second)
+	d.Update()
+	if !tips.IsVisible() {
+		t.Error("Expected tooltip to show again after re-entering")
+	}
+}
+
+// TestTooltip_HideWhenScrolledOut 测试控件滚出父容器可见区域后隐藏
+func TestTooltip_HideWhenScrolledOut(t *testing.T) {
+	list := NewListView("list")
+	list.X, list.Y = 0, 0
+	list.Width, list.Height = 200, 100
+
+	item := NewLabel("item")
+	item.X, item.Y = 0, 10
+	item.Width, item.Height = 200, 30
+	item.Tooltip = "Item details"
+	list.AddChild(item)
+
+	src := newMockInputSource()
+	d := NewInputDispatcher(src, nil)
+	d.SetViewport(400, 300)
+	d.SetRoots([]Widget{list})
+	clock := time.Unix(1000, 0)
+	d.now = func() time.Time { return clock }
+	tips := d.TooltipManager()
+
+	src.x, src.y = 50, 20
+	d.Update()
+	clock = clock.Add(time.Second)
+	d.Update()
+	if tips.GetTarget() != item || !tips.IsVisible() {
+		t.Fatal("Expected tooltip for the list item")
+	}
+
+	// 模拟滚动：子控件移出列表的裁剪区域
+	item.Y = 150
+	d.Update()
+	if tips.IsVisible() {
+		t.Error("Tooltip should hide when the widget scrolls out of view")
+	}
+}
+
+// TestTooltip_TemplateSize 测试模板提示使用模板控件的尺寸
+func TestTooltip_TemplateSize(t *testing.T) {
+	d, src, btn, clock := newTooltipFixture()
+	tpl := NewPanel("tipTemplate")
+	tpl.Width, tpl.Height = 120, 40
+	tpl.Visible = false
+	btn.TooltipTemplate = "tipTemplate"
+	d.SetRoots([]Widget{btn, tpl})
+
+	src.x, src.y = 20, 20
+	d.Update()
+	*clock = clock.Add(time.Second)
+	d.Update()
+
+	b := d.TooltipManager().Bounds()
+	if b.Dx() != 120 || b.Dy() != 40 {
+		t.Errorf("Expected template size 120x40, got %dx%d", b.Dx(), b.Dy())
+	}
+}
+
+// TestPlaceTooltip 测试翻转和平移
+func TestPlaceTooltip(t *testing.T) {
+	viewport := image.Rect(0, 0, 400, 300)
+	size := image.Pt(100, 40)
+
+	tests := []struct {
+		name   string
+		cursor image.Point
+		want   image.Point
+	}{
+		{"below", image.Pt(50, 50), image.Pt(50, 66)},
+		{"flip above", image.Pt(50, 280), image.Pt(50, 224)},
+		{"shift left", image.Pt(350, 50), image.Pt(300, 66)},
+		{"flip and shift", image.Pt(390, 290), image.Pt(300, 234)},
+	}
+	for _, tt := range tests {
+		got := PlaceTooltip(size, tt.cursor, viewport, 16)
+		if got.Min != tt.want || got.Size() != size {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+		if !got.In(viewport) {
+			t.Errorf("%s: tooltip %v outside viewport", tt.name, got)
+		}
+	}
+
+	// 视口比提示还矮时贴住视口顶部
+	got := PlaceTooltip(size, image.Pt(10, 10), image.Rect(0, 0, 400, 30), 16)
+	if got.Min.Y != 0 {
+		t.Errorf("Expected tooltip clamped to top, got %v", got)
+	}
+}
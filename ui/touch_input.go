@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// TouchInputSource 支持触摸的输入源（InputSource的可选扩展）
+type TouchInputSource interface {
+	AppendTouchIDs(ids []ebiten.TouchID) []ebiten.TouchID
+	TouchPosition(id ebiten.TouchID) (int, int)
+}
+
+func (EbitenInputSource) AppendTouchIDs(ids []ebiten.TouchID) []ebiten.TouchID {
+	return ebiten.AppendTouchIDs(ids)
+}
+
+func (EbitenInputSource) TouchPosition(id ebiten.TouchID) (int, int) {
+	return ebiten.TouchPosition(id)
+}
+
+// touchPoint 分发器跟踪的触点
+type touchPoint struct {
+	x, y    int
+	target  Widget // 按下时命中的控件（后续move/end都发往该控件）
+	pressed bool   // 是否为第一个触点（使目标控件处于按下状态）
+}
+
+// GestureRecognizer 获取分发器使用的手势识别器（可用于调整阈值）
+func (d *InputDispatcher) GestureRecognizer() *GestureRecognizer {
+	return d.gestures
+}
+
+// updateTouches 处理触摸开始/移动/结束，并把触摸轨迹交给手势识别器
+func (d *InputDispatcher) updateTouches(mods Modifiers) {
+	source, ok := d.source.(TouchInputSource)
+	if !ok {
+		return
+	}
+
+	now := d.now()
+	d.touchBuf = source.AppendTouchIDs(d.touchBuf[:0])
+
+	active := make(map[ebiten.TouchID]bool, len(d.touchBuf))
+	for _, id := range d.touchBuf {
+		active[id] = true
+		x, y := source.TouchPosition(id)
+
+		point, exists := d.touches[id]
+		if !exists {
+			// 在弹出内容外按下时先关闭弹出内容，关闭捕获输入的弹出内容时本次触摸不命中任何控件
+			var target Widget
+			if !d.layers.dismissOnPress(image.Pt(x, y)) {
+				target = d.HitTest(x, y)
+			}
+			point := &touchPoint{x: x, y: y, target: target, pressed: len(d.touches) == 0}
+			d.touches[id] = point
+			d.pushTouch(EventTouchStart, target, id, x, y, mods)
+			// 第一个触点等同于左键按下，用于移动焦点，也可以拖动滚动面板
+			if point.pressed {
+				d.setPressed(target, true)
+				d.updateFocusOnPress(target)
+				d.beginTouchScroll(id, x, y, now)
+			}
+			d.dispatchGestures(d.gestures.Begin(int(id), x, y, now), mods)
+			continue
+		}
+
+		if x != point.x || y != point.y {
+			point.x, point.y = x, y
+			d.pushTouch(EventTouchMove, point.target, id, x, y, mods)
+			d.moveTouchScroll(id, x, y, now, mods)
+			d.dispatchGestures(d.gestures.Move(int(id), x, y, now), mods)
+		}
+	}
+
+	for id, point := range d.touches {
+		if active[id] {
+			continue
+		}
+		delete(d.touches, id)
+		d.pushTouch(EventTouchEnd, point.target, id, point.x, point.y, mods)
+		if point.pressed {
+			d.setPressed(point.target, false)
+		}
+		d.endTouchScroll(id, now)
+		d.dispatchGestures(d.gestures.End(int(id), point.x, point.y, now), mods)
+	}
+
+	d.dispatchGestures(d.gestures.Update(now), mods)
+}
+
+// pushTouch 推送原始触摸事件
+func (d *InputDispatcher) pushTouch(eventType EventType, target Widget, id ebiten.TouchID, x, y int, mods Modifiers) {
+	if target == nil {
+		return
+	}
+	d.pushPointer(eventType, target, x, y, 0, mods, map[string]interface{}{
+		"touchId": int(id),
+	})
+}
+
+// dispatchGestures 将手势发送到手势起点下的控件
+// 点击手势同时产生click，使按钮等控件在触屏上无需额外处理
+func (d *InputDispatcher) dispatchGestures(gestures []Gesture, mods Modifiers) {
+	for _, g := range gestures {
+		target := d.HitTest(g.X, g.Y)
+		if target == nil {
+			continue
+		}
+
+		data := map[string]interface{}{}
+		switch g.Type {
+		case GestureSwipe:
+			data["direction"] = g.Direction
+			data["deltaX"] = g.DeltaX
+			data["deltaY"] = g.DeltaY
+			data["velocityX"] = g.VelocityX
+			data["velocityY"] = g.VelocityY
+		case GesturePinch:
+			data["scale"] = g.Scale
+			data["centerX"] = g.CenterX
+			data["centerY"] = g.CenterY
+		}
+		d.pushPointer(EventType(g.Type), target, g.X, g.Y, 0, mods, data)
+
+		if g.Type == GestureTap {
+			d.pushPointer(EventClick, target, g.X, g.Y, 0, mods, map[string]interface{}{
+				"source": "touch",
+			})
+		}
+	}
+}
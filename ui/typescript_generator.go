@@ -22,6 +22,7 @@ func NewTypeScriptGenerator() *TypeScriptGenerator {
 			TypeLabel,
 			TypeTextInput,
 			TypePanel,
+			TypeScrollPanel,
 			TypeImage,
 			TypeCheckBox,
 			TypeRadioButton,
@@ -89,6 +90,11 @@ func (g *TypeScriptGenerator) writeBaseTypes() {
 	g.writeLine("}")
 	g.writeLine("")
 
+	// 指针形状
+	g.writeLine("type CursorShape = 'default' | 'text' | 'crosshair' | 'pointer' | 'ew-resize' | 'ns-resize' |")
+	g.writeLine("    'nesw-resize' | 'nwse-resize' | 'move' | 'not-allowed';")
+	g.writeLine("")
+
 	// 矩形类型
 	g.writeLine("interface Rectangle {")
 	g.writeLine("    x: number;")
@@ -117,7 +123,8 @@ func (g *TypeScriptGenerator) writeBaseTypes() {
 	g.writeLine("    setWidth(width: number): void;")
 	g.writeLine("    setHeight(height: number): void;")
 	g.writeLine("    setBounds(x: number, y: number, width: number, height: number): void;")
-	g.writeLine("    getBounds(): Rectangle;")
+	g.writeLine("    getBounds(): Rectangle | null;")
+	g.writeLine("    getContentBounds(): Rectangle | null;")
 	g.writeLine("")
 	g.writeLine("    // Visibility")
 	g.writeLine("    setVisible(visible: boolean): void;")
@@ -130,6 +137,17 @@ func (g *TypeScriptGenerator) writeBaseTypes() {
 	g.writeLine("    // Interaction")
 	g.writeLine("    setInteractive(interactive: boolean): void;")
 	g.writeLine("    isInteractive(): boolean;")
+	g.writeLine("")
+	g.writeLine("    // Focus")
+	g.writeLine("    focus(): void;")
+	g.writeLine("    blur(): void;")
+	g.writeLine("")
+	g.writeLine("    // Tooltip")
+	g.writeLine("    setTooltip(text: string): void;")
+	g.writeLine("    setTooltipTemplate(templateId: string): void;")
+	g.writeLine("")
+	g.writeLine("    // Cursor")
+	g.writeLine("    setCursor(cursor: CursorShape): void;")
 	g.writeLine("}")
 	g.writeLine("")
 }
@@ -159,6 +177,21 @@ func (g *TypeScriptGenerator) writeEventTypes() {
 	g.writeLine("    x: number;")
 	g.writeLine("    y: number;")
 	g.writeLine("    button: number;")
+	g.writeLine("    shiftKey: boolean;")
+	g.writeLine("    ctrlKey: boolean;")
+	g.writeLine("    altKey: boolean;")
+	g.writeLine("    metaKey: boolean;")
+	g.writeLine("}")
+	g.writeLine("")
+
+	// 滚轮事件
+	g.writeLine("/**")
+	g.writeLine(" * Mouse wheel event")
+	g.writeLine(" */")
+	g.writeLine("interface WheelEvent extends MouseEvent {")
+	g.writeLine("    type: 'wheel';")
+	g.writeLine("    deltaX: number;")
+	g.writeLine("    deltaY: number;")
 	g.writeLine("}")
 	g.writeLine("")
 
@@ -182,13 +215,94 @@ func (g *TypeScriptGenerator) writeEventTypes() {
 	g.writeLine("}")
 	g.writeLine("")
 
+	// 选择改变事件
+	g.writeLine("/**")
+	g.writeLine(" * Selection change event (combo boxes)")
+	g.writeLine(" */")
+	g.writeLine("interface SelectionChangeEvent extends BaseEvent {")
+	g.writeLine("    type: 'change';")
+	g.writeLine("    target: UIComboBox;")
+	g.writeLine("    selectedIndex: number;")
+	g.writeLine("    value: string;")
+	g.writeLine("}")
+	g.writeLine("")
+
 	// 键盘事件
 	g.writeLine("/**")
 	g.writeLine(" * Keyboard event")
 	g.writeLine(" */")
 	g.writeLine("interface KeyEvent extends BaseEvent {")
+	g.writeLine("    type: 'keydown' | 'keyup' | 'keypress';")
 	g.writeLine("    key: string;")
 	g.writeLine("    keyCode: number;")
+	g.writeLine("    shiftKey: boolean;")
+	g.writeLine("    ctrlKey: boolean;")
+	g.writeLine("    altKey: boolean;")
+	g.writeLine("    metaKey: boolean;")
+	g.writeLine("}")
+	g.writeLine("")
+
+	// 触摸事件
+	g.writeLine("/**")
+	g.writeLine(" * Touch event")
+	g.writeLine(" */")
+	g.writeLine("interface TouchEvent extends MouseEvent {")
+	g.writeLine("    type: 'touchstart' | 'touchmove' | 'touchend';")
+	g.writeLine("    touchId: number;")
+	g.writeLine("}")
+	g.writeLine("")
+
+	// 手势事件
+	g.writeLine("/**")
+	g.writeLine(" * Gesture event")
+	g.writeLine(" */")
+	g.writeLine("interface GestureEvent extends MouseEvent {")
+	g.writeLine("    type: 'tap' | 'doubletap' | 'longpress' | 'swipe' | 'pinch';")
+	g.writeLine("    direction?: 'left' | 'right' | 'up' | 'down';")
+	g.writeLine("    deltaX?: number;")
+	g.writeLine("    deltaY?: number;")
+	g.writeLine("    velocityX?: number;")
+	g.writeLine("    velocityY?: number;")
+	g.writeLine("    scale?: number;")
+	g.writeLine("    centerX?: number;")
+	g.writeLine("    centerY?: number;")
+	g.writeLine("}")
+	g.writeLine("")
+
+	// 拖放事件
+	g.writeLine("/**")
+	g.writeLine(" * Drag and drop event")
+	g.writeLine(" */")
+	g.writeLine("interface DragEvent extends MouseEvent {")
+	g.writeLine("    type: 'dragstart' | 'dragover' | 'drop' | 'dragend';")
+	g.writeLine("    payload: { type: string; sourceId: string; data: Record<string, any> };")
+	g.writeLine("    index?: number;")
+	g.writeLine("    targetIndex?: number;")
+	g.writeLine("    dropped?: boolean;")
+	g.writeLine("}")
+	g.writeLine("")
+
+	// 滚动事件
+	g.writeLine("/**")
+	g.writeLine(" * Scroll event (scroll panels)")
+	g.writeLine(" */")
+	g.writeLine("interface ScrollEvent extends MouseEvent {")
+	g.writeLine("    type: 'scroll';")
+	g.writeLine("    target: UIScrollPanel;")
+	g.writeLine("    scrollX: number;")
+	g.writeLine("    scrollY: number;")
+	g.writeLine("    maxScrollX: number;")
+	g.writeLine("    maxScrollY: number;")
+	g.writeLine("    source: 'wheel' | 'scrollbar' | 'touch' | 'inertia' | 'focus' | 'api';")
+	g.writeLine("}")
+	g.writeLine("")
+
+	// 焦点事件
+	g.writeLine("/**")
+	g.writeLine(" * Focus event")
+	g.writeLine(" */")
+	g.writeLine("interface FocusEvent extends BaseEvent {")
+	g.writeLine("    type: 'focus' | 'blur';")
 	g.writeLine("}")
 	g.writeLine("")
 
@@ -197,7 +311,24 @@ func (g *TypeScriptGenerator) writeEventTypes() {
 	g.writeLine(" * Hover event")
 	g.writeLine(" */")
 	g.writeLine("interface HoverEvent extends MouseEvent {")
-	g.writeLine("    type: 'hover';")
+	g.writeLine("    type: 'hover' | 'mouseenter' | 'mouseleave';")
+	g.writeLine("}")
+	g.writeLine("")
+
+	// 交互状态变化事件
+	g.writeLine("/**")
+	g.writeLine(" * Interaction state name")
+	g.writeLine(" */")
+	g.writeLine("type WidgetStateName = 'hover' | 'pressed' | 'focused' | 'disabled' | 'selected';")
+	g.writeLine("")
+	g.writeLine("/**")
+	g.writeLine(" * Interaction state change event (fired for every widget on hover, press, focus, disable and select transitions)")
+	g.writeLine(" */")
+	g.writeLine("interface StateChangeEvent extends BaseEvent {")
+	g.writeLine("    type: 'statechange';")
+	g.writeLine("    state: WidgetStateName;")
+	g.writeLine("    active: boolean;")
+	g.writeLine("    states: WidgetStateName[];")
 	g.writeLine("}")
 	g.writeLine("")
 }
@@ -242,6 +373,7 @@ func (g *TypeScriptGenerator) getTypeScriptTypeName(widgetType WidgetType) strin
 		"label":       "UILabel",
 		"textinput":   "UITextInput",
 		"panel":       "UIPanel",
+		"scrollpanel": "UIScrollPanel",
 		"image":       "UIImage",
 		"checkbox":    "UICheckBox",
 		"radiobutton": "UIRadioButton",
@@ -308,6 +440,16 @@ func (g *TypeScriptGenerator) getWidgetMethods(widgetType WidgetType) []string {
 			"getChildCount(): number",
 		}
 
+	case TypeScrollPanel:
+		return []string{
+			"addChild(widget: UIWidget): void",
+			"removeChild(id: string): void",
+			"clear(): void",
+			"getChildCount(): number",
+			"scrollTo(x: number, y: number): void",
+			"scrollBy(dx: number, dy: number): void",
+		}
+
 	case TypeImage:
 		return []string{
 			"setImage(path: string): void",
@@ -3,6 +3,7 @@ package ui
 import (
 	"image"
 	"image/color"
+	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -23,6 +24,7 @@ const (
 	TypeGridView    WidgetType = "gridview"
 	TypeTableView   WidgetType = "tableview"
 	TypePanel       WidgetType = "panel"
+	TypeScrollPanel WidgetType = "scrollpanel"
 )
 
 // Spacing 间距结构
@@ -33,6 +35,24 @@ type Spacing struct {
 	Left   int `json:"left"`
 }
 
+// Horizontal 左右之和
+func (s Spacing) Horizontal() int { return s.Left + s.Right }
+
+// Vertical 上下之和
+func (s Spacing) Vertical() int { return s.Top + s.Bottom }
+
+// Inset 按间距收缩矩形（宽高不小于0）
+func (s Spacing) Inset(r image.Rectangle) image.Rectangle {
+	inner := image.Rect(r.Min.X+s.Left, r.Min.Y+s.Top, r.Max.X-s.Right, r.Max.Y-s.Bottom)
+	if inner.Max.X < inner.Min.X {
+		inner.Max.X = inner.Min.X
+	}
+	if inner.Max.Y < inner.Min.Y {
+		inner.Max.Y = inner.Min.Y
+	}
+	return inner
+}
+
 // RGBA 颜色结构 (0-255)
 type RGBA struct {
 	R uint8 `json:"r"`
@@ -113,6 +133,7 @@ type BaseWidget struct {
 	OffsetY      int    `json:"offsetY"`
 
 	// 边界锚定系统（控制尺寸响应）
+	// X/Y坐标隐含左/上锚定，因此锚定右/底边时在X/Y与父容器右/底边之间拉伸
 	AnchorLeft   bool `json:"anchorLeft"`   // 锚定左边
 	AnchorRight  bool `json:"anchorRight"`  // 锚定右边
 	AnchorTop    bool `json:"anchorTop"`    // 锚定上边
@@ -122,14 +143,80 @@ type BaseWidget struct {
 	DesignMarginRight  int `json:"designMarginRight"`  // 设计时右边距
 	DesignMarginBottom int `json:"designMarginBottom"` // 设计时底边距
 
+	// 尺寸约束（0表示不限制）
+	MinWidth    int     `json:"minWidth"`
+	MaxWidth    int     `json:"maxWidth"`
+	MinHeight   int     `json:"minHeight"`
+	MaxHeight   int     `json:"maxHeight"`
+	AspectRatio float64 `json:"aspectRatio"` // 宽高比（宽/高）
+
 	// 层级和可见性
 	ZIndex      int  `json:"zIndex"`
 	Visible     bool `json:"visible"`
 	Interactive bool `json:"interactive"`
 
+	// 将子控件的绘制和命中测试裁剪到自身边界内（滚动面板和列表类控件默认开启）
+	ClipChildren bool `json:"clipChildren"`
+
+	// 提升到覆盖层：仍按父容器布局定位，但不受祖先裁剪，并在所有普通控件之上绘制和命中（用于弹出内容）
+	Overlay bool `json:"overlay"`
+
+	// 焦点导航
+	TabIndex int `json:"tabIndex"` // >0 优先按升序，0 按文档顺序，<0 不参与Tab导航
+
+	// 拖放
+	Draggable   bool     `json:"draggable"`   // 是否可拖拽
+	DragType    string   `json:"dragType"`    // 拖拽数据类型
+	DropTarget  bool     `json:"dropTarget"`  // 是否为放置目标
+	AcceptTypes []string `json:"acceptTypes"` // 接受的数据类型（为空时接受全部）
+
+	// 方向导航显式目标（控件ID，为空时使用空间查找）
+	NavUp    string `json:"navUp"`
+	NavDown  string `json:"navDown"`
+	NavLeft  string `json:"navLeft"`
+	NavRight string `json:"navRight"`
+
+	// 提示（悬停一段时间后显示，模板优先于文本）
+	Tooltip         string `json:"tooltip"`         // 提示文本（支持\n换行）
+	TooltipTemplate string `json:"tooltipTemplate"` // 作为提示内容的模板控件ID
+
+	// 悬停时的指针形状（"pointer"、"text"、"ew-resize"等，为空时按控件类型决定）
+	Cursor string `json:"cursor"`
+
+	// 弹性布局子项属性（父容器layout为"flex"时生效）
+	FlexGrow   float64 `json:"flexGrow"`   // 分配剩余空间的比例
+	FlexShrink float64 `json:"flexShrink"` // 空间不足时收缩的比例（0表示不收缩）
+	FlexBasis  int     `json:"flexBasis"`  // 主轴基础尺寸：>0为像素，0使用设计尺寸，FlexBasisContent使用内容尺寸
+	AlignSelf  string  `json:"alignSelf"`  // 覆盖父容器的alignItems
+
+	// 网格布局子项属性（父容器layout为"grid"时生效，行列从1开始，0表示自动放置）
+	GridRow        int    `json:"gridRow"`
+	GridColumn     int    `json:"gridColumn"`
+	GridRowSpan    int    `json:"gridRowSpan"`
+	GridColumnSpan int    `json:"gridColumnSpan"`
+	JustifySelf    string `json:"justifySelf"` // 覆盖父容器的justifyItems
+
+	// 父容器布局计算出的局部边界（设置后代替X/Y/Width/Height参与定位和尺寸计算）
+	layoutBounds    image.Rectangle
+	hasLayoutBounds bool
+
+	// 布局引擎写入的结果（测量得到的首选尺寸、相对父容器内容区域的边界和绝对边界）
+	measuredSize   image.Point
+	hasMeasured    bool
+	computedLocal  image.Rectangle
+	computedLayout LayoutBox
+	hasComputed    bool
+
+	// 外观自上次被渲染缓存绘制以来是否改变（由Set方法、布局结果变化和交互状态变化设置）
+	dirty      bool
+	cacheEntry *cacheEntry // 作为缓存块时渲染缓存分配的条目
+
+	// 交互状态（由输入分发器和焦点管理器维护）
+	state WidgetState
+
 	// 样式
-	Padding         Spacing `json:"padding"`
-	Margin          Spacing `json:"margin"`
+	Padding         Spacing `json:"padding"` // 内边距：文本和子控件所在的内容区域与边界的距离
+	Margin          Spacing `json:"margin"`  // 外边距：参与锚定、弹性布局和网格布局的间距
 	BackgroundColor RGBA    `json:"backgroundColor"`
 	BackgroundAlpha uint8   `json:"backgroundColorAlpha"`
 	BorderWidth     int     `json:"borderWidth"`
@@ -138,9 +225,19 @@ type BaseWidget struct {
 	BorderRadius    int     `json:"borderRadius"`
 	Opacity         int     `json:"opacity"` // 0-100
 
+	// 扩展外观（均按圆角绘制）
+	BackgroundGradient Gradient    `json:"backgroundGradient"` // 背景渐变（设置后代替背景颜色）
+	BoxShadow          []BoxShadow `json:"boxShadow"`          // 外阴影和内阴影（按顺序绘制）
+	BorderStyle        BorderStyle `json:"borderStyle"`        // 边框样式：solid、dashed、double
+	BorderDash         int         `json:"borderDash"`         // 虚线边框的线段长度（0表示边框宽度的3倍）
+
 	// 背景资源
-	BackgroundResourceID string `json:"backgroundResourceId"`
+	Class                string    `json:"class"` // 样式类（空格分隔多个），供主题的类选择器匹配
+	BackgroundResourceID string    `json:"backgroundResourceId"`
+	BackgroundSlice      NineSlice `json:"backgroundSlice"` // 背景图片（包括三态背景）的九宫格切片，优先于资源清单中的声明
 	backgroundImage      *ebiten.Image
+	resourceSlices       map[*ebiten.Image]NineSlice // 背景图片资源在资源清单中声明的切片
+	styling              styleState                  // 主题相关的样式状态
 
 	// 子控件
 	Children []Widget `json:"-"`
@@ -168,6 +265,7 @@ func (w *BaseWidget) SetBounds(rect image.Rectangle) {
 	w.Y = rect.Min.Y
 	w.Width = rect.Dx()
 	w.Height = rect.Dy()
+	w.dirty = true
 }
 
 func (w *BaseWidget) GetX() int      { return w.X }
@@ -176,14 +274,139 @@ func (w *BaseWidget) GetWidth() int  { return w.Width }
 func (w *BaseWidget) GetHeight() int { return w.Height }
 
 func (w *BaseWidget) GetZIndex() int  { return w.ZIndex }
-func (w *BaseWidget) SetZIndex(z int) { w.ZIndex = z }
+func (w *BaseWidget) SetZIndex(z int) { w.ZIndex, w.dirty = z, true }
 
 func (w *BaseWidget) IsVisible() bool         { return w.Visible }
-func (w *BaseWidget) SetVisible(visible bool) { w.Visible = visible }
+func (w *BaseWidget) SetVisible(visible bool) { w.Visible, w.dirty = visible, true }
 
 func (w *BaseWidget) IsInteractive() bool             { return w.Interactive }
 func (w *BaseWidget) SetInteractive(interactive bool) { w.Interactive = interactive }
 
+// MarkDirty 标记控件的外观已经改变，渲染缓存在下一帧重新绘制其所在的子树
+// Set方法会自动调用；直接修改导出字段后需要手动调用
+func (w *BaseWidget) MarkDirty() { w.dirty = true }
+
+// IsDirty 控件的外观自上次被渲染缓存绘制以来是否改变
+func (w *BaseWidget) IsDirty() bool { return w.dirty }
+
+func (w *BaseWidget) clearDirty()                       { w.dirty = false }
+func (w *BaseWidget) renderCacheEntry() *cacheEntry     { return w.cacheEntry }
+func (w *BaseWidget) setRenderCacheEntry(e *cacheEntry) { w.cacheEntry = e }
+
+func (w *BaseWidget) ClipsChildren() bool { return w.ClipChildren }
+func (w *BaseWidget) IsOverlay() bool     { return w.Overlay }
+
+func (w *BaseWidget) GetTabIndex() int         { return w.TabIndex }
+func (w *BaseWidget) SetTabIndex(tabIndex int) { w.TabIndex = tabIndex }
+
+func (w *BaseWidget) IsDraggable() bool   { return w.Draggable }
+func (w *BaseWidget) GetDragType() string { return w.DragType }
+
+// AcceptsDrop 判断是否接受指定类型的拖拽数据
+func (w *BaseWidget) AcceptsDrop(payloadType string) bool {
+	if !w.DropTarget {
+		return false
+	}
+	if len(w.AcceptTypes) == 0 {
+		return true
+	}
+	for _, t := range w.AcceptTypes {
+		if t == payloadType {
+			return true
+		}
+	}
+	return false
+}
+
+// GetNavTargets 获取方向导航的显式目标ID
+func (w *BaseWidget) GetNavTargets() (up, down, left, right string) {
+	return w.NavUp, w.NavDown, w.NavLeft, w.NavRight
+}
+
+func (w *BaseWidget) GetTooltip() string           { return w.Tooltip }
+func (w *BaseWidget) SetTooltip(text string)       { w.Tooltip = text }
+func (w *BaseWidget) GetTooltipTemplate() string   { return w.TooltipTemplate }
+func (w *BaseWidget) SetTooltipTemplate(id string) { w.TooltipTemplate = id }
+
+func (w *BaseWidget) GetCursor() string       { return w.Cursor }
+func (w *BaseWidget) SetCursor(cursor string) { w.Cursor = cursor }
+
+// GetFlexItem 获取弹性布局子项属性
+func (w *BaseWidget) GetFlexItem() FlexItem {
+	return FlexItem{
+		Grow:      w.FlexGrow,
+		Shrink:    w.FlexShrink,
+		Basis:     w.FlexBasis,
+		AlignSelf: w.AlignSelf,
+	}
+}
+
+// GetGridItem 获取网格布局子项属性
+func (w *BaseWidget) GetGridItem() GridItem {
+	return GridItem{
+		Row:         w.GridRow,
+		Column:      w.GridColumn,
+		RowSpan:     w.GridRowSpan,
+		ColumnSpan:  w.GridColumnSpan,
+		JustifySelf: w.JustifySelf,
+		AlignSelf:   w.AlignSelf,
+	}
+}
+
+// SetLayoutBounds 设置由父容器布局计算出的局部边界
+func (w *BaseWidget) SetLayoutBounds(rect image.Rectangle) {
+	w.layoutBounds = rect
+	w.hasLayoutBounds = true
+}
+
+// ClearLayoutBounds 清除布局边界，恢复使用X/Y/Width/Height
+func (w *BaseWidget) ClearLayoutBounds() {
+	w.hasLayoutBounds = false
+}
+
+// GetLayoutBounds 获取父容器布局计算出的局部边界
+func (w *BaseWidget) GetLayoutBounds() (image.Rectangle, bool) {
+	return w.layoutBounds, w.hasLayoutBounds
+}
+
+// MeasuredSize 获取测量阶段得到的首选尺寸
+func (w *BaseWidget) MeasuredSize() (image.Point, bool) {
+	return w.measuredSize, w.hasMeasured
+}
+
+// GetComputedLayout 获取最近一次布局过程计算出的绝对边界和内容区域
+func (w *BaseWidget) GetComputedLayout() (LayoutBox, bool) {
+	return w.computedLayout, w.hasComputed
+}
+
+func (w *BaseWidget) setMeasuredSize(size image.Point) {
+	w.measuredSize = size
+	w.hasMeasured = true
+}
+
+func (w *BaseWidget) setComputedLayout(local image.Rectangle, box LayoutBox) {
+	if !w.hasComputed || local != w.computedLocal || box != w.computedLayout {
+		w.dirty = true
+	}
+	w.computedLocal = local
+	w.computedLayout = box
+	w.hasComputed = true
+}
+
+func (w *BaseWidget) invalidateComputedLayout() {
+	w.hasComputed = false
+}
+
+// computeLocalBounds 计算相对父容器内容区域的边界（不使用缓存的布局结果）
+func (w *BaseWidget) computeLocalBounds(parentWidth, parentHeight int) image.Rectangle {
+	if w.hasLayoutBounds {
+		return w.layoutBounds
+	}
+	x, y := w.anchorPosition(parentWidth, parentHeight)
+	width, height := w.anchorSize(parentWidth, parentHeight, x, y)
+	return image.Rect(x, y, x+width, y+height)
+}
+
 func (w *BaseWidget) GetPadding() Spacing { return w.Padding }
 func (w *BaseWidget) GetMargin() Spacing  { return w.Margin }
 
@@ -216,12 +439,14 @@ func (w *BaseWidget) GetOpacity() int      { return w.Opacity }
 
 func (w *BaseWidget) AddChild(child Widget) {
 	w.Children = append(w.Children, child)
+	w.dirty = true
 }
 
 func (w *BaseWidget) RemoveChild(id string) {
 	for i, child := range w.Children {
 		if child.GetID() == id {
 			w.Children = append(w.Children[:i], w.Children[i+1:]...)
+			w.dirty = true
 			break
 		}
 	}
@@ -252,22 +477,46 @@ func (w *BaseWidget) Draw(screen *ebiten.Image, parentX, parentY, parentWidth, p
 	absX := parentX + localX
 	absY := parentY + localY
 
+	// 计算响应式尺寸（支持边界锚定）
+	renderWidth, renderHeight := w.CalculateSize(parentWidth, parentHeight, localX, localY)
+
 	// 绘制子控件
-	for _, child := range w.Children {
-		child.Draw(screen, absX, absY, w.Width, w.Height)
-	}
+	w.DrawChildren(screen, absX, absY, renderWidth, renderHeight)
 }
 
 // DrawChildren 绘制子控件（供容器控件使用）
+// 参数为控件自身的绝对位置和尺寸，子控件在扣除内边距后的内容区域中定位
 func (w *BaseWidget) DrawChildren(screen *ebiten.Image, parentX, parentY, parentWidth, parentHeight int) {
 	if !w.Visible {
 		return
 	}
 
-	// 绘制所有子控件，传递当前容器的尺寸信息
-	for _, child := range w.Children {
-		child.Draw(screen, parentX, parentY, parentWidth, parentHeight)
+	bounds := image.Rect(parentX, parentY, parentX+parentWidth, parentY+parentHeight)
+	if w.ClipChildren {
+		clipped, ok := clipImage(screen, bounds)
+		if !ok {
+			return
+		}
+		screen = clipped
+	}
+
+	// 按层叠顺序绘制子控件，传递内容区域的位置和尺寸
+	drawChildren(screen, w.Children, w.ContentRect(bounds))
+}
+
+// clipImage 获取裁剪到矩形（绝对坐标）内的子图像，裁剪后为空时返回ok=false
+// 子图像与screen共用坐标系，并且与screen已有的裁剪区域取交集，因此嵌套的裁剪自然生效
+func clipImage(screen *ebiten.Image, rect image.Rectangle) (*ebiten.Image, bool) {
+	clipped, ok := screen.SubImage(rect).(*ebiten.Image)
+	if !ok || clipped.Bounds().Empty() {
+		return nil, false
 	}
+	return clipped, true
+}
+
+// ContentRect 计算边界扣除内边距后的内容区域（文本和子控件在该区域内布局）
+func (w *BaseWidget) ContentRect(bounds image.Rectangle) image.Rectangle {
+	return w.Padding.Inset(bounds)
 }
 
 // OnClick 默认点击处理
@@ -295,7 +544,17 @@ func (w *BaseWidget) OnHover(x, y int) bool {
 
 // CalculatePosition 根据定位模式计算实际坐标
 // parentWidth, parentHeight 为父容器的尺寸
+// 布局过程运行后直接返回缓存的结果，绘制与命中测试使用相同的数值
 func (w *BaseWidget) CalculatePosition(parentWidth, parentHeight int) (int, int) {
+	if w.hasComputed {
+		return w.computedLocal.Min.X, w.computedLocal.Min.Y
+	}
+	r := w.computeLocalBounds(parentWidth, parentHeight)
+	return r.Min.X, r.Min.Y
+}
+
+// anchorPosition 根据锚点或X/Y计算坐标
+func (w *BaseWidget) anchorPosition(parentWidth, parentHeight int) (int, int) {
 	if w.PositionMode == "anchor" {
 		// 锚点模式：根据锚点和偏移计算实际坐标
 		anchorX := 0
@@ -325,12 +584,35 @@ func (w *BaseWidget) CalculatePosition(parentWidth, parentHeight int) (int, int)
 			anchorY = 0
 		}
 
-		// 返回锚点位置 + 偏移
-		return anchorX + w.OffsetX, anchorY + w.OffsetY
+		// 返回锚点位置 + 偏移（外边距使控件远离锚定的一侧）
+		return anchorX + w.OffsetX + anchorMargin(w.AnchorX, "right", w.Margin.Left, w.Margin.Right),
+			anchorY + w.OffsetY + anchorMargin(w.AnchorY, "bottom", w.Margin.Top, w.Margin.Bottom)
+	}
+
+	// 绝对定位模式：X, Y 为外边距框的位置
+	return w.X + w.Margin.Left, w.Y + w.Margin.Top
+}
+
+// anchorMargin 计算锚点定位时外边距造成的偏移
+// 锚定在起始边时向内推开起始外边距，锚定在结束边时向内推开结束外边距，居中时取差值的一半
+func anchorMargin(anchor, end string, start, stop int) int {
+	switch anchor {
+	case end:
+		return -stop
+	case "center", "middle":
+		return (start - stop) / 2
 	}
+	return start
+}
 
-	// 绝对定位模式：直接使用 X, Y
-	return w.X, w.Y
+// ComputeBounds 计算控件在父容器中的绝对边界
+// 与Draw使用相同的定位和尺寸计算，命中测试依赖该结果与绘制保持一致
+func (w *BaseWidget) ComputeBounds(parentX, parentY, parentWidth, parentHeight int) image.Rectangle {
+	localX, localY := w.CalculatePosition(parentWidth, parentHeight)
+	width, height := w.CalculateSize(parentWidth, parentHeight, localX, localY)
+	absX := parentX + localX
+	absY := parentY + localY
+	return image.Rect(absX, absY, absX+width, absY+height)
 }
 
 // CalculateSize 根据边界锚定计算响应式尺寸
@@ -338,26 +620,108 @@ func (w *BaseWidget) CalculatePosition(parentWidth, parentHeight int) (int, int)
 // localX, localY: 控件在父容器中的局部坐标
 // 返回: 计算后的宽度和高度
 func (w *BaseWidget) CalculateSize(parentWidth, parentHeight, localX, localY int) (int, int) {
+	if w.hasComputed {
+		return w.computedLocal.Dx(), w.computedLocal.Dy()
+	}
+	if w.hasLayoutBounds {
+		return w.layoutBounds.Dx(), w.layoutBounds.Dy()
+	}
+	return w.anchorSize(parentWidth, parentHeight, localX, localY)
+}
+
+// anchorSize 根据设计尺寸和边界锚定计算尺寸（设计尺寸为0时使用测量尺寸）
+// 结果受最小/最大尺寸和宽高比约束
+func (w *BaseWidget) anchorSize(parentWidth, parentHeight, localX, localY int) (int, int) {
 	width := w.Width
 	height := w.Height
+	if width <= 0 && w.hasMeasured {
+		width = w.measuredSize.X
+	}
+	if height <= 0 && w.hasMeasured {
+		height = w.measuredSize.Y
+	}
 
 	// 如果锚定了右边，计算响应式宽度
-	if w.AnchorRight {
-		// 新宽度 = 父容器宽度 - 控件X坐标 - 设计时右边距
-		width = parentWidth - localX - w.DesignMarginRight
-		if width < 0 {
-			width = 0
-		}
+	stretchX := w.AnchorRight
+	if stretchX {
+		// 新宽度 = 父容器宽度 - 控件X坐标 - 设计时右边距 - 右外边距
+		width = max(parentWidth-localX-w.DesignMarginRight-w.Margin.Right, 0)
 	}
 
 	// 如果锚定了底边，计算响应式高度
-	if w.AnchorBottom {
-		// 新高度 = 父容器高度 - 控件Y坐标 - 设计时底边距
-		height = parentHeight - localY - w.DesignMarginBottom
-		if height < 0 {
-			height = 0
-		}
+	stretchY := w.AnchorBottom
+	if stretchY {
+		// 新高度 = 父容器高度 - 控件Y坐标 - 设计时底边距 - 底外边距
+		height = max(parentHeight-localY-w.DesignMarginBottom-w.Margin.Bottom, 0)
 	}
 
+	return w.constrainSize(width, height, stretchX, stretchY)
+}
+
+// constrainSize 应用最小/最大尺寸和宽高比约束
+// 设置了宽高比时，只拉伸高度的控件由高度推导宽度，其他情况由宽度推导高度；推导出的尺寸仍受最小/最大约束
+func (w *BaseWidget) constrainSize(width, height int, stretchX, stretchY bool) (int, int) {
+	width = clampSize(width, w.MinWidth, w.MaxWidth)
+	height = clampSize(height, w.MinHeight, w.MaxHeight)
+
+	if w.AspectRatio > 0 {
+		if stretchY && !stretchX {
+			width = clampSize(int(math.Round(float64(height)*w.AspectRatio)), w.MinWidth, w.MaxWidth)
+		} else {
+			height = clampSize(int(math.Round(float64(width)/w.AspectRatio)), w.MinHeight, w.MaxHeight)
+		}
+	}
 	return width, height
 }
+
+// sizeLimits 最小/最大尺寸约束（为0的约束不生效）
+func (w *BaseWidget) sizeLimits() (minSize, maxSize image.Point) {
+	return image.Pt(w.MinWidth, w.MinHeight), image.Pt(w.MaxWidth, w.MaxHeight)
+}
+
+// clampSize 将尺寸限制在[minSize, maxSize]内（为0的约束不生效）
+func clampSize(size, minSize, maxSize int) int {
+	if maxSize > 0 && size > maxSize {
+		size = maxSize
+	}
+	if minSize > 0 && size < minSize {
+		size = minSize
+	}
+	return size
+}
+
+// isWidgetEnabled 判断控件是否处于启用状态（没有启用状态的控件视为启用）
+func isWidgetEnabled(widget Widget) bool {
+	switch w := widget.(type) {
+	case *ButtonWidget:
+		return w.Enabled
+	case *TextInputWidget:
+		return w.Enabled
+	case *CheckBoxWidget:
+		return w.Enabled
+	case *RadioButtonWidget:
+		return w.Enabled
+	case *SliderWidget:
+		return w.Enabled
+	case *ComboBoxWidget:
+		return w.Enabled
+	case *ListViewWidget:
+		return w.Enabled
+	case *GridViewWidget:
+		return w.Enabled
+	case *TableViewWidget:
+		return w.Enabled
+	}
+	return true
+}
+
+// isWidgetSelected 控件是否处于选中状态（复选框已勾选、单选按钮已选中）
+func isWidgetSelected(widget Widget) bool {
+	switch w := widget.(type) {
+	case *CheckBoxWidget:
+		return w.Checked
+	case *RadioButtonWidget:
+		return w.Selected
+	}
+	return false
+}
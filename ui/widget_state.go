@@ -0,0 +1,143 @@
+package ui
+
+import "strings"
+
+// WidgetState 控件的交互状态集合（位标志，多个状态可以同时成立）
+// 由输入分发器和焦点管理器维护：悬停、按下来自指针和触摸，焦点来自焦点管理器，禁用和选中每帧按控件的Enabled、Checked等属性同步
+type WidgetState uint8
+
+const (
+	WidgetStateHover    WidgetState = 1 << iota // 指针位于控件上
+	WidgetStatePressed                          // 左键或第一个触点在控件上按下且尚未抬起
+	WidgetStateFocused                          // 持有焦点
+	WidgetStateDisabled                         // 已禁用
+	WidgetStateSelected                         // 已选中（复选框、单选按钮）
+)
+
+// widgetStates 各状态及其名称（与主题的状态伪类相同），也是状态样式的应用顺序：后面的状态覆盖前面的状态
+var widgetStates = [...]struct {
+	flag WidgetState
+	name string
+}{
+	{WidgetStateHover, StateHover},
+	{WidgetStateFocused, StateFocused},
+	{WidgetStateSelected, StateSelected},
+	{WidgetStatePressed, StatePressed},
+	{WidgetStateDisabled, StateDisabled},
+}
+
+// ParseWidgetState 按名称获取状态（未知名称返回0）
+func ParseWidgetState(name string) WidgetState {
+	for _, s := range widgetStates {
+		if s.name == name {
+			return s.flag
+		}
+	}
+	return 0
+}
+
+// Has 是否包含全部指定状态
+func (s WidgetState) Has(flag WidgetState) bool {
+	return flag != 0 && s&flag == flag
+}
+
+// Names 获取成立的状态名称
+func (s WidgetState) Names() []string {
+	names := make([]string, 0, len(widgetStates))
+	for _, state := range widgetStates {
+		if s.Has(state.flag) {
+			names = append(names, state.name)
+		}
+	}
+	return names
+}
+
+// String 状态名称，多个状态用|连接，没有状态时为normal
+func (s WidgetState) String() string {
+	if s == 0 {
+		return "normal"
+	}
+	return strings.Join(s.Names(), "|")
+}
+
+// State 获取控件当前的交互状态
+func (w *BaseWidget) State() WidgetState {
+	return w.state
+}
+
+// HasState 控件是否处于指定名称的状态（hover、pressed、focused、disabled、selected）
+func (w *BaseWidget) HasState(name string) bool {
+	return w.state.Has(ParseWidgetState(name))
+}
+
+// stateFlags 获取交互状态的存储位置
+func (w *BaseWidget) stateFlags() *WidgetState {
+	return &w.state
+}
+
+// stateHolder 具有交互状态的控件（嵌入BaseWidget的控件都实现了该接口）
+type stateHolder interface {
+	stateFlags() *WidgetState
+}
+
+// legacyStateSyncer 保留了旧状态字段的控件（按钮和文本输入的CurrentState、滑动条的IsHovering等），这些字段按交互状态同步
+type legacyStateSyncer interface {
+	syncLegacyState()
+}
+
+// syncLegacyState 按交互状态同步控件的旧状态字段
+func syncLegacyState(widget Widget) {
+	if s, ok := widget.(legacyStateSyncer); ok {
+		s.syncLegacyState()
+	}
+}
+
+// setWidgetState 设置或清除控件的一个交互状态
+// 状态改变时应用状态样式、标记重绘，并通过push推送statechange事件（push为nil时静默改变），返回状态是否改变
+func setWidgetState(widget Widget, flag WidgetState, on bool, push func(WidgetEvent)) bool {
+	holder, ok := widget.(stateHolder)
+	if !ok {
+		return false
+	}
+	state := holder.stateFlags()
+	old := *state
+	if on {
+		*state |= flag
+	} else {
+		*state &^= flag
+	}
+	if *state == old {
+		return false
+	}
+
+	syncLegacyState(widget)
+	if styled, ok := widget.(styledWidget); ok {
+		applyStateStyles(widget, styled.style(), *state)
+	}
+	if d, ok := widget.(interface{ MarkDirty() }); ok {
+		d.MarkDirty()
+	}
+	if push != nil {
+		push(WidgetEvent{
+			Type:     EventStateChange,
+			WidgetID: widget.GetID(),
+			Widget:   widget,
+			Data: map[string]interface{}{
+				"state":  flag.String(),
+				"active": on,
+				"states": state.Names(),
+			},
+		})
+	}
+	return true
+}
+
+// syncWidgetStates 按控件的启用和选中属性同步控件树的禁用和选中状态，并按交互状态刷新旧状态字段
+func syncWidgetStates(widgets []Widget, push func(WidgetEvent)) {
+	for _, w := range widgets {
+		setWidgetState(w, WidgetStateDisabled, !isWidgetEnabled(w), push)
+		setWidgetState(w, WidgetStateSelected, isWidgetSelected(w), push)
+		syncLegacyState(w)
+		syncWidgetStates(w.GetChildren(), push)
+	}
+}
@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// stateEvents 提取控件的statechange事件，格式为"状态:是否成立"
+func stateEvents(events []WidgetEvent, widgetID string) []string {
+	var got []string
+	for _, e := range events {
+		if e.Type != EventStateChange || e.WidgetID != widgetID {
+			continue
+		}
+		state, _ := e.Data["state"].(string)
+		if active, _ := e.Data["active"].(bool); active {
+			got = append(got, state+":on")
+		} else {
+			got = append(got, state+":off")
+		}
+	}
+	return got
+}
+
+// TestWidgetState_Names 测试状态集合的名称
+func TestWidgetState_Names(t *testing.T) {
+	s := WidgetStateHover | WidgetStatePressed
+	if s.String() != "hover|pressed" {
+		t.Errorf("expected hover|pressed, got %s", s.String())
+	}
+	if WidgetState(0).String() != "normal" {
+		t.Errorf("expected normal for empty state, got %s", WidgetState(0).String())
+	}
+	if ParseWidgetState(StateFocused) != WidgetStateFocused || ParseWidgetState("active") != 0 {
+		t.Errorf("unexpected state parsing")
+	}
+	if s.Has(WidgetStateHover|WidgetStateFocused) || !s.Has(WidgetStatePressed) {
+		t.Errorf("unexpected Has result for %s", s)
+	}
+}
+
+// TestWidgetState_DispatcherTracksPointer 测试分发器维护悬停、按下和焦点状态并推送statechange事件
+func TestWidgetState_DispatcherTracksPointer(t *testing.T) {
+	d, src, eq := newDispatcherFixture()
+	defer eq.Close()
+	btn1 := d.roots[0].(*ButtonWidget)
+
+	src.x, src.y = 20, 20
+	d.Update()
+	if btn1.State() != WidgetStateHover {
+		t.Fatalf("expected hover, got %s", btn1.State())
+	}
+
+	btn1.dirty = false
+	src.buttons[ebiten.MouseButtonLeft] = true
+	d.Update()
+	if !btn1.HasState(StatePressed) || !btn1.HasState(StateFocused) || !btn1.HasState(StateHover) {
+		t.Errorf("expected hover, pressed and focused, got %s", btn1.State())
+	}
+	if !btn1.dirty {
+		t.Errorf("expected state change to mark the widget dirty")
+	}
+	if bg, _ := btn1.GetStateBackground(); bg.R != btn1.BackgroundColorPressed.R {
+		t.Errorf("expected pressed background, got %v", bg)
+	}
+
+	// 按住拖出控件时保持按下状态，抬起时清除
+	src.x, src.y = 600, 600
+	d.Update()
+	if !btn1.HasState(StatePressed) || btn1.HasState(StateHover) {
+		t.Errorf("expected pressed without hover while dragged outside, got %s", btn1.State())
+	}
+	src.buttons[ebiten.MouseButtonLeft] = false
+	d.Update()
+	if btn1.State() != WidgetStateFocused {
+		t.Errorf("expected only focused after release, got %s", btn1.State())
+	}
+
+	got := stateEvents(drainEvents(eq), "btn1")
+	want := []string{"hover:on", "pressed:on", "focused:on", "hover:off", "pressed:off"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected state events %v, got %v", want, got)
+	}
+}
+
+// TestWidgetState_SyncsDisabledAndSelected 测试禁用和选中状态按控件属性同步，禁用的控件不进入按下状态
+func TestWidgetState_SyncsDisabledAndSelected(t *testing.T) {
+	btn := NewButton("btn")
+	btn.X, btn.Y = 10, 10
+	btn.SetEnabled(false)
+	check := NewCheckBox("check", 10, 100, 100, 20)
+	check.SetChecked(true)
+
+	src := newMockInputSource()
+	eq := NewEventQueue()
+	defer eq.Close()
+	d := NewInputDispatcher(src, eq)
+	d.SetRoots([]Widget{btn, check})
+
+	// 设置顶层控件时静默同步
+	if btn.State() != WidgetStateDisabled || check.State() != WidgetStateSelected {
+		t.Fatalf("expected initial disabled/selected, got %s/%s", btn.State(), check.State())
+	}
+	if len(drainEvents(eq)) != 0 {
+		t.Errorf("expected SetRoots to sync states silently")
+	}
+
+	src.x, src.y = 20, 20
+	src.buttons[ebiten.MouseButtonLeft] = true
+	d.Update()
+	if btn.HasState(StatePressed) {
+		t.Errorf("expected disabled button not to be pressed")
+	}
+
+	check.SetChecked(false)
+	btn.SetEnabled(true)
+	d.Update()
+	events := drainEvents(eq)
+	if got := stateEvents(events, "check"); !reflect.DeepEqual(got, []string{"selected:off"}) {
+		t.Errorf("expected selected:off, got %v", got)
+	}
+	if got := stateEvents(events, "btn"); !reflect.DeepEqual(got, []string{"hover:on", "disabled:off"}) {
+		t.Errorf("expected hover:on and disabled:off, got %v", got)
+	}
+	for _, e := range events {
+		if e.Type == EventStateChange && e.WidgetID == "btn" && e.Data["state"] == StateDisabled {
+			if states, _ := e.Data["states"].([]string); !reflect.DeepEqual(states, []string{StateHover}) {
+				t.Errorf("expected remaining states [hover], got %v", e.Data["states"])
+			}
+		}
+	}
+}
+
+// TestWidgetState_SyncsLegacyFields 测试按钮、文本输入和滑动条的旧状态字段随交互状态同步
+func TestWidgetState_SyncsLegacyFields(t *testing.T) {
+	btn := NewButton("btn")
+	input := NewTextInput("input")
+	slider := NewSlider("slider", 0, 0, 100, 20)
+
+	setWidgetState(btn, WidgetStatePressed, true, nil)
+	setWidgetState(input, WidgetStateFocused, true, nil)
+	setWidgetState(slider, WidgetStateHover, true, nil)
+	setWidgetState(slider, WidgetStatePressed, true, nil)
+	if btn.CurrentState != ButtonStatePressed {
+		t.Errorf("expected button pressed, got %s", btn.CurrentState)
+	}
+	if !input.Focused || input.CurrentState != TextInputStateEditing {
+		t.Errorf("expected input focused and editing, got %v/%s", input.Focused, input.CurrentState)
+	}
+	if !slider.IsHovering || !slider.IsDragging {
+		t.Errorf("expected slider hovering and dragging, got %v/%v", slider.IsHovering, slider.IsDragging)
+	}
+
+	// 禁用后同步为disabled，直接改写的字段按交互状态恢复
+	btn.SetEnabled(false)
+	if btn.CurrentState != ButtonStateDisabled {
+		t.Errorf("expected button disabled, got %s", btn.CurrentState)
+	}
+	input.SetEnabled(false)
+	slider.IsDragging = false
+	syncWidgetStates([]Widget{btn, input, slider}, nil)
+	if input.Focused || input.CurrentState != TextInputStateDisabled {
+		t.Errorf("expected input unfocused and disabled, got %v/%s", input.Focused, input.CurrentState)
+	}
+	if !slider.IsDragging {
+		t.Errorf("expected slider IsDragging to follow the pressed state")
+	}
+}
+
+// TestWidgetState_StateStyles 测试.ui和主题声明的状态样式在状态成立时覆盖属性，状态清除后恢复
+func TestWidgetState_StateStyles(t *testing.T) {
+	loader := NewLoader()
+	roots, err := loader.LoadFromData(map[string]interface{}{
+		"widgets": []interface{}{
+			map[string]interface{}{"id": "panel", "type": "panel", "borderWidth": float64(1),
+				"states": map[string]interface{}{
+					"hover":   map[string]interface{}{"borderWidth": float64(3)},
+					"pressed": map[string]interface{}{"borderWidth": float64(5), "opacity": float64(80)},
+				}},
+			map[string]interface{}{"id": "btn", "type": "button", "fontSize": float64(16)},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	panel := roots[0].(*PanelWidget)
+	btn := roots[1].(*ButtonWidget)
+
+	setWidgetState(panel, WidgetStateHover, true, nil)
+	if panel.BorderWidth != 3 {
+		t.Errorf("expected hover border width 3, got %d", panel.BorderWidth)
+	}
+	setWidgetState(panel, WidgetStatePressed, true, nil)
+	if panel.BorderWidth != 5 || panel.Opacity != 80 {
+		t.Errorf("expected pressed style to win over hover, got border %d opacity %d", panel.BorderWidth, panel.Opacity)
+	}
+	setWidgetState(panel, WidgetStatePressed, false, nil)
+	setWidgetState(panel, WidgetStateHover, false, nil)
+	if panel.BorderWidth != 1 || panel.Opacity != 100 {
+		t.Errorf("expected properties restored, got border %d opacity %d", panel.BorderWidth, panel.Opacity)
+	}
+
+	// 主题的悬停规则映射到按钮默认状态的背景，.ui中直接声明的属性不被状态样式覆盖
+	theme := mustTheme(t,
+		rule("button", map[string]interface{}{"backgroundColor": "#101010"}),
+		rule("button:hover", map[string]interface{}{"backgroundColor": "#404040", "fontSize": float64(20)}),
+	)
+	SetTheme(roots, theme)
+	setWidgetState(btn, WidgetStateHover, true, nil)
+	if btn.BackgroundColorNormal != (RGBA{0x40, 0x40, 0x40, 255}) || btn.FontSize != 16 {
+		t.Errorf("unexpected hovered button: bg %v font size %d", btn.BackgroundColorNormal, btn.FontSize)
+	}
+
+	// 悬停时切换主题：基础值按默认状态恢复，新主题的状态样式立即生效
+	SetTheme(roots, mustTheme(t,
+		rule("button:hover", map[string]interface{}{"backgroundColor": "#808080"}),
+	))
+	if btn.BackgroundColorNormal != (RGBA{0x80, 0x80, 0x80, 255}) {
+		t.Errorf("expected new hover background, got %v", btn.BackgroundColorNormal)
+	}
+	setWidgetState(btn, WidgetStateHover, false, nil)
+	if btn.BackgroundColorNormal != NewButton("").BackgroundColorNormal {
+		t.Errorf("expected default background after leaving, got %v", btn.BackgroundColorNormal)
+	}
+}
@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"image"
+	"testing"
+)
+
+// TestWidget_EdgeAnchoring 测试边界锚定的各种组合（父容器400x300）
+func TestWidget_EdgeAnchoring(t *testing.T) {
+	tests := []struct {
+		name                     string
+		left, right, top, bottom bool
+		want                     image.Rectangle
+	}{
+		{"none", false, false, false, false, image.Rect(20, 30, 120, 80)},
+		{"left", true, false, false, false, image.Rect(20, 30, 120, 80)},
+		{"right", false, true, false, false, image.Rect(20, 30, 390, 80)},
+		{"left+right", true, true, false, false, image.Rect(20, 30, 390, 80)},
+		{"top", false, false, true, false, image.Rect(20, 30, 120, 80)},
+		{"bottom", false, false, false, true, image.Rect(20, 30, 120, 295)},
+		{"top+bottom", false, false, true, true, image.Rect(20, 30, 120, 295)},
+		{"right+bottom", false, true, false, true, image.Rect(20, 30, 390, 295)},
+		{"all", true, true, true, true, image.Rect(20, 30, 390, 295)},
+	}
+	for _, tt := range tests {
+		w := NewPanel("w")
+		w.X, w.Y = 20, 30
+		w.Width, w.Height = 100, 50
+		w.DesignMarginRight, w.DesignMarginBottom = 10, 5
+		w.AnchorLeft, w.AnchorRight = tt.left, tt.right
+		w.AnchorTop, w.AnchorBottom = tt.top, tt.bottom
+
+		if got := w.computeLocalBounds(400, 300); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// TestWidget_PointAnchoring 测试锚点定位（锚点+偏移），以及与拉伸的组合
+func TestWidget_PointAnchoring(t *testing.T) {
+	tests := []struct {
+		name             string
+		anchorX, anchorY string
+		stretchX         bool
+		want             image.Rectangle
+	}{
+		{"top-left", "left", "top", false, image.Rect(10, -10, 110, 40)},
+		{"center", "center", "middle", false, image.Rect(210, 140, 310, 190)},
+		{"bottom-right", "right", "bottom", false, image.Rect(410, 290, 510, 340)},
+		{"center stretched", "center", "middle", true, image.Rect(210, 140, 390, 190)},
+	}
+	for _, tt := range tests {
+		w := NewPanel("w")
+		w.PositionMode = "anchor"
+		w.AnchorX, w.AnchorY = tt.anchorX, tt.anchorY
+		w.OffsetX, w.OffsetY = 10, -10
+		w.Width, w.Height = 100, 50
+		w.DesignMarginRight = 10
+		w.AnchorLeft, w.AnchorRight = tt.stretchX, tt.stretchX
+
+		if got := w.computeLocalBounds(400, 300); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// TestWidget_SizeConstraints 测试最小/最大尺寸和宽高比约束（父容器400x300，左上角(0,0)，四边锚定时拉伸到整个父容器）
+func TestWidget_SizeConstraints(t *testing.T) {
+	tests := []struct {
+		name                  string
+		stretchX, stretchY    bool
+		minW, maxW            int
+		minH, maxH            int
+		ratio                 float64
+		wantWidth, wantHeight int
+	}{
+		{"unconstrained", true, true, 0, 0, 0, 0, 0, 400, 300},
+		{"max width", true, true, 0, 250, 0, 0, 0, 250, 300},
+		{"max height", true, true, 0, 0, 0, 120, 0, 400, 120},
+		{"min width", false, false, 150, 0, 0, 0, 0, 150, 50},
+		{"min height", false, false, 0, 0, 80, 0, 0, 100, 80},
+		{"min beats max", false, false, 150, 120, 0, 0, 0, 150, 50},
+		{"ratio from width", true, false, 0, 0, 0, 0, 2, 400, 200},
+		{"ratio from height", false, true, 0, 0, 0, 0, 2, 600, 300},
+		{"ratio fixed size", false, false, 0, 0, 0, 0, 4, 100, 25},
+		{"ratio clamped", true, false, 0, 0, 0, 150, 2, 400, 150},
+		{"ratio after max", true, true, 0, 300, 0, 0, 1.5, 300, 200},
+	}
+	for _, tt := range tests {
+		w := NewPanel("w")
+		w.Width, w.Height = 100, 50
+		w.AnchorLeft, w.AnchorRight = tt.stretchX, tt.stretchX
+		w.AnchorTop, w.AnchorBottom = tt.stretchY, tt.stretchY
+		w.MinWidth, w.MaxWidth = tt.minW, tt.maxW
+		w.MinHeight, w.MaxHeight = tt.minH, tt.maxH
+		w.AspectRatio = tt.ratio
+
+		got := w.computeLocalBounds(400, 300)
+		if got.Dx() != tt.wantWidth || got.Dy() != tt.wantHeight {
+			t.Errorf("%s: expected %dx%d, got %dx%d", tt.name, tt.wantWidth, tt.wantHeight, got.Dx(), got.Dy())
+		}
+	}
+}
+
+// TestWidget_RightAnchorStretchesFromPosition 测试只锚定右/底边时以X/Y为左/上边拉伸（与设计器一致），并受最大尺寸约束
+func TestWidget_RightAnchorStretchesFromPosition(t *testing.T) {
+	w := NewPanel("w")
+	w.X, w.Y = 20, 30
+	w.Width, w.Height = 100, 50
+	w.MaxHeight = 200
+	w.AnchorRight, w.AnchorBottom = true, true
+	w.DesignMarginRight, w.DesignMarginBottom = 10, 5
+
+	if got, want := w.computeLocalBounds(400, 300), image.Rect(20, 30, 390, 230); got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestWidget_MeasureAppliesConstraints 测试测量阶段应用尺寸约束（弹性布局使用约束后的首选尺寸）
+func TestWidget_MeasureAppliesConstraints(t *testing.T) {
+	panel := newFlexChild("panel", 400, 100)
+	panel.Layout = "flex"
+	a := newFlexChild("a", 50, 20)
+	a.MinWidth = 80
+	b := newFlexChild("b", 300, 20)
+	b.MaxWidth = 120
+	panel.AddChild(a)
+	panel.AddChild(b)
+
+	PerformLayout([]Widget{panel}, 800, 600)
+
+	if got, want := computedBounds(t, a), image.Rect(0, 0, 80, 20); got != want {
+		t.Errorf("expected a bounds %v, got %v", want, got)
+	}
+	if got, want := computedBounds(t, b), image.Rect(80, 0, 200, 20); got != want {
+		t.Errorf("expected b bounds %v, got %v", want, got)
+	}
+}
+
+// TestParseAspectRatio 测试宽高比解析
+func TestParseAspectRatio(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  float64
+		ok    bool
+	}{
+		{1.5, 1.5, true},
+		{"16:9", 16.0 / 9.0, true},
+		{"4/3", 4.0 / 3.0, true},
+		{"2", 2, true},
+		{"0:1", 0, false},
+		{"abc", 0, false},
+		{-1.0, 0, false},
+		{nil, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseAspectRatio(tt.value)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("%v: expected (%v, %v), got (%v, %v)", tt.value, tt.want, tt.ok, got, ok)
+		}
+	}
+}